@@ -17,7 +17,7 @@ func AdminOnlyMiddleware() gin.HandlerFunc {
 		}
 
 		userClaims, ok := claims.(*utils.Claims)
-		if !ok || userClaims.Role != "admin" {
+		if !ok || !utils.IsAdmin(userClaims) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
 			c.Abort()
 			return
@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/pkg/authz"
+	"thanhnt208/container-adm-service/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthorizeMiddleware lets the request through when the policy allows the
+// caller to perform action. On routes with an :id parameter the policy is
+// evaluated against that container. It must run after JWTAuthMiddleware.
+func AuthorizeMiddleware(authzService service.IAuthzService, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := utils.ClaimsFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Unauthorized",
+			})
+			return
+		}
+
+		var containerID uint
+		if raw := c.Param("id"); raw != "" {
+			id, err := strconv.ParseUint(raw, 10, 64)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error": "Invalid ID format",
+				})
+				return
+			}
+			containerID = uint(id)
+		}
+
+		decision, err := authzService.Authorize(c, service.SubjectFromClaims(claims), action, containerID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to authorize request",
+			})
+			return
+		}
+		if !decision.Allowed {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Access denied: " + decision.Reason,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AuthorizeCollectionMiddleware authorizes routes that act on many
+// containers, such as listing or exporting. Rules with conditions narrow the
+// containers the handler sees instead of deciding upfront. It must run after
+// JWTAuthMiddleware.
+func AuthorizeCollectionMiddleware(authzService service.IAuthzService, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := utils.ClaimsFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Unauthorized",
+			})
+			return
+		}
+
+		decision, scope := authzService.AuthorizeCollection(service.SubjectFromClaims(claims), action)
+		if !decision.Allowed {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Access denied: " + decision.Reason,
+			})
+			return
+		}
+
+		c.Set(authz.ScopeKey, scope)
+		c.Next()
+	}
+}
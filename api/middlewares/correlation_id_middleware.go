@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"thanhnt208/container-adm-service/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const CorrelationIDHeader = "X-Correlation-ID"
+
+func CorrelationIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		correlationID := c.GetHeader(CorrelationIDHeader)
+		if correlationID == "" {
+			correlationID = c.GetHeader("X-Request-ID")
+		}
+		if correlationID == "" {
+			correlationID = utils.NewCorrelationID()
+		}
+
+		c.Set(utils.CorrelationIDKey, correlationID)
+		c.Header(CorrelationIDHeader, correlationID)
+		c.Next()
+	}
+}
@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 	"thanhnt208/container-adm-service/utils"
@@ -17,13 +18,18 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-		claims, err := utils.ParseJWT(tokenStr)
+		claims, err := utils.ParseJWT(c.Request.Context(), tokenStr)
+		if errors.Is(err, utils.ErrTokenRevoked) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
 			return
 		}
-		c.Set("claims", claims)
+		c.Set(utils.ClaimsKey, claims)
 		c.Next()
 	}
 }
@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"thanhnt208/container-adm-service/pkg/metrics"
+	"thanhnt208/container-adm-service/pkg/ratelimit"
+	"thanhnt208/container-adm-service/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitMiddleware limits each user to the rate configured for action on
+// this route. It must run after JWTAuthMiddleware; anonymous callers are
+// keyed by client IP.
+func RateLimitMiddleware(limiter ratelimit.IRateLimiter, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if claims, ok := utils.ClaimsFromContext(c); ok {
+			key = "user:" + strconv.FormatUint(uint64(claims.UserID), 10)
+		}
+		key += ":" + c.Request.Method + ":" + c.FullPath()
+
+		res, err := limiter.Allow(c.Request.Context(), action, key)
+		if err != nil || res.Allowed {
+			c.Next()
+			return
+		}
+
+		metrics.RateLimitedRequests.WithLabelValues(action, utils.TransportREST).Inc()
+		c.Header("Retry-After", strconv.Itoa(max(1, int(math.Ceil(res.RetryAfter.Seconds())))))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+			"error": "Rate limit exceeded, retry later",
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package middlewares
+
+import (
+	"thanhnt208/container-adm-service/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestMetaMiddleware records the client IP and transport of the request
+// so that changes can be attributed in the audit log.
+func RequestMetaMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(utils.RequestMetaKey, utils.RequestMeta{
+			SourceIP:  c.ClientIP(),
+			Transport: utils.TransportREST,
+		})
+		c.Next()
+	}
+}
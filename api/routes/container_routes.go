@@ -3,48 +3,173 @@ package routes
 import (
 	"thanhnt208/container-adm-service/api/middlewares"
 	"thanhnt208/container-adm-service/internal/delivery/rest"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/pkg/authz"
+	"thanhnt208/container-adm-service/pkg/metrics"
+	"thanhnt208/container-adm-service/pkg/ratelimit"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupContainerRoutes(h *rest.RestContainerHandler) *gin.Engine {
+func SetupContainerRoutes(h *rest.RestContainerHandler, jobHandler *rest.RestJobHandler, healthHandler *rest.RestHealthHandler, auditHandler *rest.RestAuditHandler, tokenHandler *rest.RestTokenHandler, quotaHandler *rest.RestQuotaHandler, registryCredentialHandler *rest.RestRegistryCredentialHandler, authzHandler *rest.RestAuthzHandler, authzService service.IAuthzService, limiter ratelimit.IRateLimiter) *gin.Engine {
 	router := gin.Default()
+	router.Use(middlewares.CorrelationIDMiddleware())
+	router.Use(middlewares.MetricsMiddleware())
+	router.Use(middlewares.RequestMetaMiddleware())
+
+	registerHealthRoutes(router, healthHandler)
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
 
 	router.POST("/create",
 		middlewares.JWTAuthMiddleware(),
-		middlewares.CheckScopeMiddleware("container:create"),
+		middlewares.AuthorizeMiddleware(authzService, authz.ActionContainerCreate),
+		middlewares.RateLimitMiddleware(limiter, authz.ActionContainerCreate),
 		h.CreateContainer,
 	)
 
 	router.POST("/view",
 		middlewares.JWTAuthMiddleware(),
-		middlewares.CheckScopeMiddleware("container:read"),
+		middlewares.AuthorizeCollectionMiddleware(authzService, authz.ActionContainerRead),
+		middlewares.RateLimitMiddleware(limiter, authz.ActionContainerRead),
 		h.ViewContainers,
 	)
 
 	router.PUT("/update/:id",
 		middlewares.JWTAuthMiddleware(),
-		middlewares.CheckScopeMiddleware("container:update"),
+		middlewares.AuthorizeMiddleware(authzService, authz.ActionContainerUpdate),
+		middlewares.RateLimitMiddleware(limiter, authz.ActionContainerUpdate),
 		h.UpdateContainer,
 	)
 
 	router.DELETE("/delete/:id",
 		middlewares.JWTAuthMiddleware(),
-		middlewares.CheckScopeMiddleware("container:delete"),
+		middlewares.AuthorizeMiddleware(authzService, authz.ActionContainerDelete),
+		middlewares.RateLimitMiddleware(limiter, authz.ActionContainerDelete),
 		h.DeleteContainer,
 	)
 
 	router.POST("/import",
 		middlewares.JWTAuthMiddleware(),
-		middlewares.CheckScopeMiddleware("container:import"),
+		middlewares.AuthorizeMiddleware(authzService, authz.ActionContainerImport),
+		middlewares.RateLimitMiddleware(limiter, authz.ActionContainerImport),
 		h.ImportContainers,
 	)
 
 	router.POST("/export",
 		middlewares.JWTAuthMiddleware(),
-		middlewares.CheckScopeMiddleware("container:export"),
+		middlewares.AuthorizeCollectionMiddleware(authzService, authz.ActionContainerExport),
+		middlewares.RateLimitMiddleware(limiter, authz.ActionContainerExport),
 		h.ExportContainers,
 	)
 
+	containerActions := []struct{ name, action string }{
+		{dto.ContainerActionStart, authz.ActionContainerStart},
+		{dto.ContainerActionStop, authz.ActionContainerStop},
+		{dto.ContainerActionRestart, authz.ActionContainerRestart},
+		{dto.ContainerActionPause, authz.ActionContainerPause},
+		{dto.ContainerActionUnpause, authz.ActionContainerUnpause},
+		{dto.ContainerActionKill, authz.ActionContainerKill},
+	}
+	for _, a := range containerActions {
+		router.POST("/containers/:id/actions/"+a.name,
+			middlewares.JWTAuthMiddleware(),
+			middlewares.AuthorizeMiddleware(authzService, a.action),
+			middlewares.RateLimitMiddleware(limiter, a.action),
+			h.ContainerAction(a.name),
+		)
+	}
+
+	router.GET("/containers/:id/logs",
+		middlewares.JWTAuthMiddleware(),
+		middlewares.AuthorizeMiddleware(authzService, authz.ActionContainerLogs),
+		middlewares.RateLimitMiddleware(limiter, authz.ActionContainerLogs),
+		h.GetContainerLogs,
+	)
+
+	router.GET("/containers/:id/exec",
+		middlewares.JWTAuthMiddleware(),
+		middlewares.AuthorizeMiddleware(authzService, authz.ActionContainerExec),
+		middlewares.RateLimitMiddleware(limiter, authz.ActionContainerExec),
+		h.ExecContainer,
+	)
+
+	router.GET("/containers/:id/stats",
+		middlewares.JWTAuthMiddleware(),
+		middlewares.AuthorizeMiddleware(authzService, authz.ActionContainerRead),
+		middlewares.RateLimitMiddleware(limiter, authz.ActionContainerRead),
+		h.GetContainerStats,
+	)
+
+	router.GET("/containers/:id/history",
+		middlewares.JWTAuthMiddleware(),
+		middlewares.AuthorizeMiddleware(authzService, authz.ActionContainerRead),
+		middlewares.RateLimitMiddleware(limiter, authz.ActionContainerRead),
+		h.GetContainerStatusHistory,
+	)
+
+	// Jobs are addressed by :job_id; AuthorizeMiddleware reads :id as a
+	// container ID.
+	router.GET("/jobs/:job_id",
+		middlewares.JWTAuthMiddleware(),
+		middlewares.AuthorizeMiddleware(authzService, authz.ActionJobRead),
+		middlewares.RateLimitMiddleware(limiter, authz.ActionJobRead),
+		jobHandler.GetJob,
+	)
+
+	router.GET("/jobs/:job_id/events",
+		middlewares.JWTAuthMiddleware(),
+		middlewares.AuthorizeMiddleware(authzService, authz.ActionJobRead),
+		middlewares.RateLimitMiddleware(limiter, authz.ActionJobRead),
+		jobHandler.WatchJob,
+	)
+
+	router.POST("/jobs/:job_id/cancel",
+		middlewares.JWTAuthMiddleware(),
+		middlewares.AuthorizeMiddleware(authzService, authz.ActionJobCancel),
+		middlewares.RateLimitMiddleware(limiter, authz.ActionJobCancel),
+		jobHandler.CancelJob,
+	)
+
+	router.GET("/audit",
+		middlewares.JWTAuthMiddleware(),
+		middlewares.AdminOnlyMiddleware(),
+		auditHandler.GetAuditEvents,
+	)
+
+	router.POST("/authz/check",
+		middlewares.JWTAuthMiddleware(),
+		authzHandler.CheckAuthorization,
+	)
+
+	router.GET("/quotas/me",
+		middlewares.JWTAuthMiddleware(),
+		quotaHandler.GetMyQuota,
+	)
+
+	adminQuotas := router.Group("/admin/quotas", middlewares.JWTAuthMiddleware(), middlewares.AdminOnlyMiddleware())
+	adminQuotas.GET("", quotaHandler.ListQuotas)
+	adminQuotas.POST("", quotaHandler.CreateQuota)
+	adminQuotas.GET("/:id", quotaHandler.GetQuota)
+	adminQuotas.PUT("/:id", quotaHandler.UpdateQuota)
+	adminQuotas.DELETE("/:id", quotaHandler.DeleteQuota)
+
+	adminRegistries := router.Group("/admin/registry-credentials", middlewares.JWTAuthMiddleware(), middlewares.AdminOnlyMiddleware())
+	adminRegistries.GET("", registryCredentialHandler.ListRegistryCredentials)
+	adminRegistries.PUT("/:registry", registryCredentialHandler.SetRegistryCredential)
+	adminRegistries.DELETE("/:registry", registryCredentialHandler.DeleteRegistryCredential)
+
+	router.POST("/admin/tokens/revoke",
+		middlewares.JWTAuthMiddleware(),
+		middlewares.AdminOnlyMiddleware(),
+		tokenHandler.RevokeToken,
+	)
+
+	router.POST("/admin/users/:id/revoke-tokens",
+		middlewares.JWTAuthMiddleware(),
+		middlewares.AdminOnlyMiddleware(),
+		tokenHandler.RevokeUserTokens,
+	)
+
 	return router
 }
\ No newline at end of file
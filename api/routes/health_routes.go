@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"thanhnt208/container-adm-service/internal/delivery/rest"
+	"thanhnt208/container-adm-service/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupHealthRoutes builds a router that serves only the health endpoints
+// and metrics, for binaries that have no REST API of their own.
+func SetupHealthRoutes(h *rest.RestHealthHandler) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery())
+	registerHealthRoutes(router, h)
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	return router
+}
+
+func registerHealthRoutes(router *gin.Engine, h *rest.RestHealthHandler) {
+	router.GET("/healthz", h.Healthz)
+	router.GET("/readyz", h.Readyz)
+}
@@ -1,21 +1,31 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"thanhnt208/container-adm-service/config"
 	"thanhnt208/container-adm-service/external/client"
 	"thanhnt208/container-adm-service/infrastructure"
+	"thanhnt208/container-adm-service/internal/bootstrap"
 	"thanhnt208/container-adm-service/internal/delivery/grpc"
 	"thanhnt208/container-adm-service/internal/repository"
 	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/pkg/authz"
 	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/pkg/metrics"
+	"thanhnt208/container-adm-service/pkg/ratelimit"
 	"thanhnt208/container-adm-service/proto/pb"
+	"thanhnt208/container-adm-service/utils"
+	"time"
 
 	grpcServer "google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
@@ -40,15 +50,51 @@ func main() {
 		panic("Failed to connect to Elasticsearch: " + err.Error())
 	}
 
-	dockerClient, err := client.NewDockerClient()
+	redisInfra := infrastructure.NewRedis(cfg)
+	redisClient, err := redisInfra.ConnectClient()
+	if err != nil {
+		log.Error("Failed to connect to Redis", "error", err)
+		panic("Failed to connect to Redis: " + err.Error())
+	}
+	defer redisInfra.Close()
+
+	secretCipher := bootstrap.NewSecretCipher(cfg, log)
+	auditRepository := repository.NewAuditRepository(db, log)
+	registryCredentialRepository := repository.NewRegistryCredentialRepository(db, log)
+	registryCredentialService := service.NewRegistryCredentialService(registryCredentialRepository, auditRepository, secretCipher, log)
+
+	dockerClient, err := client.NewDockerClient(registryCredentialService)
 	if err != nil {
 		log.Error("Failed to create Docker client", "error", err)
 		panic("Failed to create Docker client: " + err.Error())
 	}
 
 	containerRepository := repository.NewContainerRepository(db, esClient, log)
-	containerService := service.NewContainerService(containerRepository, log, dockerClient)
-	containerHandler := grpc.NewGrpcServerHandler(containerService, log)
+	imagePolicy := bootstrap.NewImagePolicy(cfg, log)
+	containerService := service.NewContainerService(containerRepository, auditRepository, log, dockerClient, imagePolicy)
+	jobRepository := repository.NewJobRepository(db, log)
+	jobService := service.NewJobService(jobRepository, containerService, log)
+	containerHandler := grpc.NewGrpcServerHandler(containerService, jobService, log)
+
+	authzEngine, err := authz.LoadEngine(cfg.AuthzPolicyFile)
+	if err != nil {
+		log.Error("Failed to load authorization policy", "error", err)
+		panic("Failed to load authorization policy: " + err.Error())
+	}
+	authzService := service.NewAuthzService(authzEngine, containerRepository, log)
+
+	tokenRevocationRepository := repository.NewTokenRevocationRepository(redisClient, log)
+	tokenRevocationService := service.NewTokenRevocationService(tokenRevocationRepository, auditRepository, log, cfg.MaxTokenTTL())
+	utils.SetRevocationChecker(tokenRevocationService)
+
+	kafkaInfra := infrastructure.NewKafka(cfg)
+	healthService := service.NewHealthService(log, map[string]service.HealthCheck{
+		"postgres":      postgresDB.Ping,
+		"elasticsearch": elasticsearchClient.Ping,
+		"docker":        dockerClient.Ping,
+		"kafka":         kafkaInfra.Ping,
+		"redis":         redisInfra.Ping,
+	}, 2*time.Second)
 
 	grpcPort := cfg.GrpcPort
 	log.Info("Starting gRPC server", "port", grpcPort)
@@ -59,9 +105,33 @@ func main() {
 		panic("Failed to listen on port: " + err.Error())
 	}
 
-	grpcServer := grpcServer.NewServer()
+	rateLimiter, err := ratelimit.Configure(cfg.RateLimitEnabled, cfg.RateLimits, redisClient, log)
+	if err != nil {
+		log.Error("Invalid rate limit configuration", "error", err)
+		panic("Invalid rate limit configuration: " + err.Error())
+	}
+	grpcServer := grpcServer.NewServer(
+		grpcServer.ChainUnaryInterceptor(
+			grpc.MetricsUnaryInterceptor(),
+			grpc.AuthUnaryInterceptor(authzService, log),
+			grpc.RateLimitUnaryInterceptor(rateLimiter, log),
+		),
+		grpcServer.ChainStreamInterceptor(
+			grpc.MetricsStreamInterceptor(),
+			grpc.AuthStreamInterceptor(authzService, log),
+			grpc.RateLimitStreamInterceptor(rateLimiter, log),
+		),
+	)
 	pb.RegisterContainerAdmServiceServer(grpcServer, containerHandler)
 
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go grpc.WatchHealth(ctx, healthServer, healthService, log, 10*time.Second)
+	go imagePolicy.Watch(ctx, time.Duration(cfg.ImagePolicyReload)*time.Second)
+
 	go func() {
 		if err := grpcServer.Serve(lis); err != nil {
 			log.Error("Failed to serve gRPC server", "error", err)
@@ -69,12 +139,29 @@ func main() {
 		}
 	}()
 
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", metrics.Handler())
+	metricsSrv := &http.Server{
+		Addr:    ":" + cfg.GrpcMetricsPort,
+		Handler: metricsMux,
+	}
+
+	go func() {
+		log.Info("Starting metrics listener", "port", cfg.GrpcMetricsPort)
+		if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error("Failed to start metrics listener", "error", err)
+		}
+	}()
+
 	fmt.Printf("gRPC server is running on port %s\n", grpcPort)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Info("Shutting down gRPC server...")
+	cancel()
+	healthServer.Shutdown()
+	metricsSrv.Close()
 	grpcServer.GracefulStop()
 	log.Info("gRPC server exiting")
 }
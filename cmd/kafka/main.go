@@ -2,16 +2,21 @@ package main
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"thanhnt208/container-adm-service/api/routes"
 	"thanhnt208/container-adm-service/config"
 	"thanhnt208/container-adm-service/external/client"
 	"thanhnt208/container-adm-service/infrastructure"
+	"thanhnt208/container-adm-service/internal/bootstrap"
 	kafkaHandler "thanhnt208/container-adm-service/internal/delivery/kafka"
+	"thanhnt208/container-adm-service/internal/delivery/rest"
 	"thanhnt208/container-adm-service/internal/repository"
 	"thanhnt208/container-adm-service/internal/service"
 	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
 
 	"golang.org/x/net/context"
 )
@@ -48,15 +53,86 @@ func main() {
 		panic("Failed to connect to Kafka consumer: " + err.Error())
 	}
 
-	dockerClient, err := client.NewDockerClient()
+	kafkaProducer, err := kafkaInfra.ConnectProducer()
+	if err != nil {
+		log.Error("Failed to connect to Kafka producer", "error", err)
+		panic("Failed to connect to Kafka producer: " + err.Error())
+	}
+
+	secretCipher := bootstrap.NewSecretCipher(cfg, log)
+	auditRepository := repository.NewAuditRepository(db, log)
+	registryCredentialRepository := repository.NewRegistryCredentialRepository(db, log)
+	registryCredentialService := service.NewRegistryCredentialService(registryCredentialRepository, auditRepository, secretCipher, log)
+
+	dockerClient, err := client.NewDockerClient(registryCredentialService)
 	if err != nil {
 		log.Error("Failed to create Docker client", "error", err)
 		panic("Failed to create Docker client: " + err.Error())
 	}
 
 	containerRepository := repository.NewContainerRepository(db, esClient, log)
-	containerService := service.NewContainerService(containerRepository, log, dockerClient)
-	kafkaConsumerHandler := kafkaHandler.NewKafkaConsumerHandler(containerService, log, kafkaConsumer)
+	imagePolicy := bootstrap.NewImagePolicy(cfg, log)
+	containerService := service.NewContainerService(containerRepository, auditRepository, log, dockerClient, imagePolicy)
+	go imagePolicy.Watch(ctx, time.Duration(cfg.ImagePolicyReload)*time.Second)
+	kafkaConsumerHandler := kafkaHandler.NewKafkaConsumerHandler(containerService, log, kafkaConsumer, kafkaProducer, kafkaHandler.ConsumerOptions{
+		Workers:         cfg.KafkaWorkers,
+		MaxRetries:      cfg.KafkaMaxRetries,
+		InitialBackoff:  500 * time.Millisecond,
+		MaxBackoff:      30 * time.Second,
+		DeadLetterTopic: cfg.KafkaDLQTopic,
+	})
+	reconciler := service.NewReconciler(containerRepository, log, dockerClient, time.Duration(cfg.ReconcileInterval)*time.Second)
+	statsSampler := service.NewStatsSampler(containerRepository, log, dockerClient, time.Duration(cfg.StatsInterval)*time.Second)
+
+	checkpointRepository := repository.NewCheckpointRepository(db, log)
+	eventWatcher := service.NewEventWatcher(containerRepository, checkpointRepository, log, dockerClient)
+	outboxRepository := repository.NewOutboxRepository(db, log)
+	outboxRelay := service.NewOutboxRelay(outboxRepository, log, kafkaProducer, cfg.KafkaEventsTopic, time.Second)
+
+	healthService := service.NewHealthService(log, map[string]service.HealthCheck{
+		"postgres":      postgresDB.Ping,
+		"elasticsearch": elasticsearchClient.Ping,
+		"docker":        dockerClient.Ping,
+		"kafka":         kafkaInfra.Ping,
+	}, 2*time.Second)
+	healthSrv := &http.Server{
+		Addr:    ":" + cfg.HealthPort,
+		Handler: routes.SetupHealthRoutes(rest.NewRestHealthHandler(healthService)),
+	}
+
+	go func() {
+		log.Info("Starting health listener", "port", cfg.HealthPort)
+		if err := healthSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error("Failed to start health listener", "error", err)
+		}
+	}()
+
+	containerMetrics := service.NewContainerMetrics(containerService, log, 15*time.Second)
+
+	go func() {
+		log.Info("Starting container metrics updater")
+		containerMetrics.Run(ctx)
+	}()
+
+	go func() {
+		log.Info("Starting reconciler", "interval", cfg.ReconcileInterval)
+		reconciler.Run(ctx)
+	}()
+
+	go func() {
+		log.Info("Starting stats sampler", "interval", cfg.StatsInterval)
+		statsSampler.Run(ctx)
+	}()
+
+	go func() {
+		log.Info("Starting Docker event watcher")
+		eventWatcher.Run(ctx)
+	}()
+
+	go func() {
+		log.Info("Starting outbox relay", "topic", cfg.KafkaEventsTopic)
+		outboxRelay.Run(ctx)
+	}()
 
 	consumerDone := make(chan error, 1)
 
@@ -95,11 +171,23 @@ func main() {
 		}
 	}
 
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	if err := healthSrv.Shutdown(shutdownCtx); err != nil {
+		log.Error("Failed to shut down health listener", "error", err)
+	}
+
 	if err := kafkaConsumer.Close(); err != nil {
 		log.Error("Failed to close Kafka consumer", "error", err)
 	} else {
 		log.Info("Kafka consumer closed successfully")
 	}
 
+	if err := kafkaProducer.Close(); err != nil {
+		log.Error("Failed to close Kafka producer", "error", err)
+	} else {
+		log.Info("Kafka producer closed successfully")
+	}
+
 	log.Info("Service shutdown complete")
 }
@@ -10,10 +10,14 @@ import (
 	"thanhnt208/container-adm-service/config"
 	"thanhnt208/container-adm-service/external/client"
 	"thanhnt208/container-adm-service/infrastructure"
+	"thanhnt208/container-adm-service/internal/bootstrap"
 	"thanhnt208/container-adm-service/internal/delivery/rest"
 	"thanhnt208/container-adm-service/internal/repository"
 	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/pkg/authz"
 	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/pkg/ratelimit"
+	"thanhnt208/container-adm-service/utils"
 	"time"
 )
 
@@ -39,17 +43,84 @@ func main() {
 		panic("Failed to connect to Elasticsearch: " + err.Error())
 	}
 
-	dockerClient, err := client.NewDockerClient()
+	redisInfra := infrastructure.NewRedis(cfg)
+	redisClient, err := redisInfra.ConnectClient()
+	if err != nil {
+		log.Error("Failed to connect to Redis", "error", err)
+		panic("Failed to connect to Redis: " + err.Error())
+	}
+	defer redisInfra.Close()
+
+	secretCipher := bootstrap.NewSecretCipher(cfg, log)
+	auditRepository := repository.NewAuditRepository(db, log)
+	registryCredentialRepository := repository.NewRegistryCredentialRepository(db, log)
+	registryCredentialService := service.NewRegistryCredentialService(registryCredentialRepository, auditRepository, secretCipher, log)
+
+	dockerClient, err := client.NewDockerClient(registryCredentialService)
 	if err != nil {
 		log.Error("Failed to create Docker client", "error", err)
 		panic("Failed to create Docker client: " + err.Error())
 	}
 
 	containerRepository := repository.NewContainerRepository(db, esClient, log)
-	containerService := service.NewContainerService(containerRepository, log, dockerClient)
-	containerRestHandler := rest.NewRestServerHandler(containerService, log)
+	imagePolicy := bootstrap.NewImagePolicy(cfg, log)
+	containerService := service.NewContainerService(containerRepository, auditRepository, log, dockerClient, imagePolicy)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go imagePolicy.Watch(ctx, time.Duration(cfg.ImagePolicyReload)*time.Second)
+
+	jobRepository := repository.NewJobRepository(db, log)
+	jobService := service.NewJobService(jobRepository, containerService, log)
+	jobRunner := service.NewJobRunner(jobRepository, containerService, log, cfg.JobWorkers, time.Second)
+	jobRunnerDone := make(chan struct{})
+	go func() {
+		defer close(jobRunnerDone)
+		jobRunner.Run(ctx)
+	}()
+
+	containerRestHandler := rest.NewRestServerHandler(containerService, jobService, log)
+	jobHandler := rest.NewRestJobHandler(jobService, log)
+
+	kafkaInfra := infrastructure.NewKafka(cfg)
+	healthService := service.NewHealthService(log, map[string]service.HealthCheck{
+		"postgres":      postgresDB.Ping,
+		"elasticsearch": elasticsearchClient.Ping,
+		"docker":        dockerClient.Ping,
+		"kafka":         kafkaInfra.Ping,
+		"redis":         redisInfra.Ping,
+	}, 2*time.Second)
+	healthHandler := rest.NewRestHealthHandler(healthService)
+
+	tokenRevocationRepository := repository.NewTokenRevocationRepository(redisClient, log)
+	tokenRevocationService := service.NewTokenRevocationService(tokenRevocationRepository, auditRepository, log, cfg.MaxTokenTTL())
+	utils.SetRevocationChecker(tokenRevocationService)
+	tokenHandler := rest.NewRestTokenHandler(tokenRevocationService, log)
 
-	r := routes.SetupContainerRoutes(containerRestHandler)
+	registryCredentialHandler := rest.NewRestRegistryCredentialHandler(registryCredentialService, log)
+
+	quotaRepository := repository.NewQuotaRepository(db, log)
+	quotaService := service.NewQuotaService(quotaRepository, auditRepository, log)
+	quotaHandler := rest.NewRestQuotaHandler(quotaService, log)
+
+	authzEngine, err := authz.LoadEngine(cfg.AuthzPolicyFile)
+	if err != nil {
+		log.Error("Failed to load authorization policy", "error", err)
+		panic("Failed to load authorization policy: " + err.Error())
+	}
+	authzService := service.NewAuthzService(authzEngine, containerRepository, log)
+	authzHandler := rest.NewRestAuthzHandler(authzService, log)
+
+	auditService := service.NewAuditService(auditRepository, log)
+	auditHandler := rest.NewRestAuditHandler(auditService, log)
+
+	rateLimiter, err := ratelimit.Configure(cfg.RateLimitEnabled, cfg.RateLimits, redisClient, log)
+	if err != nil {
+		log.Error("Invalid rate limit configuration", "error", err)
+		panic("Invalid rate limit configuration: " + err.Error())
+	}
+
+	r := routes.SetupContainerRoutes(containerRestHandler, jobHandler, healthHandler, auditHandler, tokenHandler, quotaHandler, registryCredentialHandler, authzHandler, authzService, rateLimiter)
 
 	port := cfg.ServerPort
 	srv := &http.Server{
@@ -70,12 +141,21 @@ func main() {
 	<-quit
 	log.Info("Shutting down server gracefully...")
 
-	ctxShutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	cancel()
+	ctxShutdown, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
 
 	if err := srv.Shutdown(ctxShutdown); err != nil {
 		log.Fatal("REST server forced to shutdown:", "error", err)
 	}
 
+	// Running jobs store their outcome before the runner returns.
+	select {
+	case <-jobRunnerDone:
+		log.Info("Job runner stopped")
+	case <-ctxShutdown.Done():
+		log.Warn("Job runner did not stop before the shutdown timeout")
+	}
+
 	log.Info("REST server exiting")
 }
@@ -3,30 +3,60 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	ServerPort      string
-	GrpcPort        string
-	DBHost          string
-	DBPort          string
-	DBUser          string
-	DBPassword      string
-	DBName          string
-	RedisAddr       string
-	RedisPassword   string
-	EsAddr          string
-	KafkaBrokers    []string
-	KafkaTopic      string
-	KafkaGroupID    string
-	LogLevel        string
-	LogFile         string
-	JWTSecret       string
-	JWTExpiresIn    int
-	RefreshTokenTTL int
+	ServerPort        string
+	GrpcPort          string
+	HealthPort        string
+	GrpcMetricsPort   string
+	DBHost            string
+	DBPort            string
+	DBUser            string
+	DBPassword        string
+	DBName            string
+	RedisAddr         string
+	RedisPassword     string
+	EsAddr            string
+	KafkaBrokers      []string
+	KafkaTopic        string
+	KafkaGroupID      string
+	KafkaEventsTopic  string
+	KafkaDLQTopic     string
+	KafkaWorkers      int
+	KafkaMaxRetries   int
+	LogLevel          string
+	LogFile           string
+	JWTSecret         string
+	JWTExpiresIn      int
+	JWTAllowHS256     bool
+	JWTAlgorithms     []string
+	JWTJWKSURL        string
+	JWTJWKSFile       string
+	JWTJWKSRefresh    int
+	JWTIssuer         string
+	JWTAudience       string
+	JWTLeeway         int
+	JWTMaxLifetime    int
+	RefreshTokenTTL   int
+	ReconcileInterval int
+	// StatsInterval is the period in seconds of the resource usage sampler.
+	// Only cmd/kafka runs the sampler, and it is off at the default of 0.
+	StatsInterval    int
+	JobWorkers       int
+	RateLimitEnabled bool
+	RateLimits       []string
+	ImagePolicyFile  string
+	// ImagePolicyReload is the period in seconds at which the image policy
+	// file is checked for changes; 0 disables hot reload.
+	ImagePolicyReload int
+	RegistryCredsKey  string
+	AuthzPolicyFile   string
 }
 
 var (
@@ -46,32 +76,112 @@ func LoadConfig() *Config {
 		if err != nil {
 			refreshTokenTTL = 604800
 		}
+		kafkaWorkers, err := strconv.Atoi(getEnv("KAFKA_WORKERS", "8"))
+		if err != nil {
+			kafkaWorkers = 8
+		}
+		kafkaMaxRetries, err := strconv.Atoi(getEnv("KAFKA_MAX_RETRIES", "5"))
+		if err != nil {
+			kafkaMaxRetries = 5
+		}
+		jwtAllowHS256, err := strconv.ParseBool(getEnv("JWT_ALLOW_HS256", "false"))
+		if err != nil {
+			jwtAllowHS256 = false
+		}
+		jwtJWKSRefresh, err := strconv.Atoi(getEnv("JWT_JWKS_REFRESH", "300"))
+		if err != nil {
+			jwtJWKSRefresh = 300
+		}
+		jwtLeeway, err := strconv.Atoi(getEnv("JWT_LEEWAY", "30"))
+		if err != nil {
+			jwtLeeway = 30
+		}
+		jwtMaxLifetime, err := strconv.Atoi(getEnv("JWT_MAX_LIFETIME", "86400"))
+		if err != nil {
+			jwtMaxLifetime = 86400
+		}
+		rateLimitEnabled, err := strconv.ParseBool(getEnv("RATE_LIMIT_ENABLED", "true"))
+		if err != nil {
+			rateLimitEnabled = true
+		}
+		imagePolicyReload, err := strconv.Atoi(getEnv("IMAGE_POLICY_RELOAD", "30"))
+		if err != nil {
+			imagePolicyReload = 30
+		}
+		reconcileInterval, err := strconv.Atoi(getEnv("RECONCILE_INTERVAL", "60"))
+		if err != nil {
+			reconcileInterval = 60
+		}
+		statsInterval, err := strconv.Atoi(getEnv("STATS_SAMPLE_INTERVAL", "0"))
+		if err != nil {
+			statsInterval = 0
+		}
+		jobWorkers, err := strconv.Atoi(getEnv("JOB_WORKERS", "4"))
+		if err != nil {
+			jobWorkers = 4
+		}
 
 		configInstance = &Config{
-			ServerPort:      getEnv("SERVER_PORT", "8001"),
-			GrpcPort:        getEnv("GRPC_PORT", "50051"),
-			DBHost:          getEnv("DB_HOST", "localhost"),
-			DBPort:          getEnv("DB_PORT", "5432"),
-			DBUser:          getEnv("DB_USER", "postgres"),
-			DBPassword:      getEnv("DB_PASSWORD", "password"),
-			DBName:          getEnv("DB_NAME", "admdb"),
-			RedisAddr:       getEnv("REDIS_ADDR", "localhost:6379"),
-			RedisPassword:   getEnv("REDIS_PASSWORD", ""),
-			EsAddr:          getEnv("ELASTICSEARCH_URL", "http://localhost:9200"),
-			KafkaBrokers:    []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
-			KafkaTopic:      getEnv("KAFKA_TOPIC", "container_topic"),
-			KafkaGroupID:    getEnv("KAFKA_GROUP_ID", "container_group_id"),
-			LogLevel:        getEnv("LOG_LEVEL", "info"),
-			LogFile:         getEnv("LOG_FILE", "../../logs/container-adm.log"),
-			JWTSecret:       getEnv("JWT_SECRET", "supersecretkey"),
-			JWTExpiresIn:    jwtExpiresIn,
-			RefreshTokenTTL: refreshTokenTTL,
+			ServerPort:        getEnv("SERVER_PORT", "8001"),
+			GrpcPort:          getEnv("GRPC_PORT", "50051"),
+			HealthPort:        getEnv("HEALTH_PORT", "8081"),
+			GrpcMetricsPort:   getEnv("GRPC_METRICS_PORT", "8082"),
+			DBHost:            getEnv("DB_HOST", "localhost"),
+			DBPort:            getEnv("DB_PORT", "5432"),
+			DBUser:            getEnv("DB_USER", "postgres"),
+			DBPassword:        getEnv("DB_PASSWORD", "password"),
+			DBName:            getEnv("DB_NAME", "admdb"),
+			RedisAddr:         getEnv("REDIS_ADDR", "localhost:6379"),
+			RedisPassword:     getEnv("REDIS_PASSWORD", ""),
+			EsAddr:            getEnv("ELASTICSEARCH_URL", "http://localhost:9200"),
+			KafkaBrokers:      []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
+			KafkaTopic:        getEnv("KAFKA_TOPIC", "container_topic"),
+			KafkaGroupID:      getEnv("KAFKA_GROUP_ID", "container_group_id"),
+			KafkaEventsTopic:  getEnv("KAFKA_EVENTS_TOPIC", "container_events"),
+			KafkaDLQTopic:     getEnv("KAFKA_DLQ_TOPIC", "container_status_dlq"),
+			KafkaWorkers:      kafkaWorkers,
+			KafkaMaxRetries:   kafkaMaxRetries,
+			LogLevel:          getEnv("LOG_LEVEL", "info"),
+			LogFile:           getEnv("LOG_FILE", "../../logs/container-adm.log"),
+			JWTSecret:         getEnv("JWT_SECRET", "supersecretkey"),
+			JWTExpiresIn:      jwtExpiresIn,
+			JWTAllowHS256:     jwtAllowHS256,
+			JWTAlgorithms:     splitList(getEnv("JWT_ALGORITHMS", "RS256,ES256")),
+			JWTJWKSURL:        getEnv("JWT_JWKS_URL", ""),
+			JWTJWKSFile:       getEnv("JWT_JWKS_FILE", ""),
+			JWTJWKSRefresh:    jwtJWKSRefresh,
+			JWTIssuer:         getEnv("JWT_ISSUER", ""),
+			JWTAudience:       getEnv("JWT_AUDIENCE", ""),
+			JWTLeeway:         jwtLeeway,
+			JWTMaxLifetime:    jwtMaxLifetime,
+			RefreshTokenTTL:   refreshTokenTTL,
+			ReconcileInterval: reconcileInterval,
+			StatsInterval:     statsInterval,
+			JobWorkers:        jobWorkers,
+			RateLimitEnabled:  rateLimitEnabled,
+			ImagePolicyFile:   getEnv("IMAGE_POLICY_FILE", ""),
+			ImagePolicyReload: imagePolicyReload,
+			RegistryCredsKey:  getEnv("REGISTRY_CREDENTIALS_KEY", ""),
+			AuthzPolicyFile:   getEnv("AUTHZ_POLICY_FILE", ""),
+			RateLimits:        splitList(getEnv("RATE_LIMITS", "container:create=10/1m,container:import=2/1m,default=300/1m")),
 		}
 	})
 
 	return configInstance
 }
 
+// MaxTokenTTL is the longest time an accepted token can stay valid, which
+// is how long a revocation has to be remembered. It covers the tokens this
+// service issues and, when a JWKS is configured, the lifetime allowed for
+// tokens of the external issuer, plus the clock leeway.
+func (c *Config) MaxTokenTTL() time.Duration {
+	ttl := max(c.JWTExpiresIn, c.RefreshTokenTTL)
+	if c.JWTJWKSURL != "" || c.JWTJWKSFile != "" {
+		ttl = max(ttl, c.JWTMaxLifetime)
+	}
+	return time.Duration(ttl+c.JWTLeeway) * time.Second
+}
+
 func getEnv(key, fallback string) string {
 	val := os.Getenv(key)
 	if val == "" {
@@ -79,3 +189,13 @@ func getEnv(key, fallback string) string {
 	}
 	return val
 }
+
+func splitList(val string) []string {
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -2,47 +2,115 @@ package client
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"path"
+	"strconv"
+	"strings"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/utils"
+	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/go-connections/nat"
 )
 
 type IDockerClient interface {
-	StartContainer(ctx context.Context, containerName, imageName string) (string, error)
+	PullImage(ctx context.Context, imageName string) error
+	StartContainer(ctx context.Context, containerName, imageName string, spec *model.ContainerSpec) (string, error)
 	StopContainer(ctx context.Context, containerID string) error
 	RemoveContainer(ctx context.Context, containerID string) error
 	StartExistingContainer(ctx context.Context, containerID string) error
+	RestartContainer(ctx context.Context, containerID string, timeout *int) error
+	PauseContainer(ctx context.Context, containerID string) error
+	UnpauseContainer(ctx context.Context, containerID string) error
+	KillContainer(ctx context.Context, containerID, signal string) error
+	WaitContainerStopped(ctx context.Context, containerID string, timeout time.Duration) (bool, error)
+	ListContainers(ctx context.Context) ([]ContainerState, error)
+	InspectContainer(ctx context.Context, containerID string) (*ContainerState, error)
+	Events(ctx context.Context, since time.Time) (<-chan ContainerEvent, <-chan error)
+	Logs(ctx context.Context, containerID string, opts dto.ContainerLogOptions) (<-chan dto.ContainerLogLine, <-chan error)
+	Stats(ctx context.Context, containerID string) (*dto.ContainerStats, error)
+	StreamStats(ctx context.Context, containerID string) (<-chan dto.ContainerStats, <-chan error)
+	Exec(ctx context.Context, containerID string, opts dto.ExecOptions) (IExecSession, error)
+	Ping(ctx context.Context) error
+}
+
+var (
+	ErrContainerNotFound = errors.New("docker container not found")
+
+	// ErrContainerConflict is returned when the state of the container does
+	// not allow the action, such as pausing a stopped container.
+	ErrContainerConflict = errors.New("docker container state conflicts with the action")
+
+	// ErrInvalidArgument is returned when the daemon rejects an argument of
+	// the call, such as an unknown signal.
+	ErrInvalidArgument = errors.New("invalid docker argument")
+)
+
+// ContainerState is the daemon's view of a container, as returned by list and inspect.
+type ContainerState struct {
+	ID     string
+	Name   string
+	Image  string
+	State  string
+	Labels map[string]string
+}
+
+// ContainerEvent is a lifecycle event emitted by the Docker daemon for a container.
+type ContainerEvent struct {
+	ContainerID string
+	Name        string
+	Action      string
+	Time        time.Time
+	Attributes  map[string]string
+}
+
+// RegistryAuthProvider supplies the encoded registry login for an image
+// pull, or an empty string to pull anonymously.
+type RegistryAuthProvider interface {
+	RegistryAuth(ctx context.Context, imageName string) (string, error)
 }
 
 type dockerClient struct {
-	client *client.Client
+	client       *client.Client
+	registryAuth RegistryAuthProvider
 }
 
-func NewDockerClient() (IDockerClient, error) {
+// NewDockerClient connects to the daemon from the environment. registryAuth
+// may be nil, in which case images are pulled anonymously.
+func NewDockerClient(registryAuth RegistryAuthProvider) (IDockerClient, error) {
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker client: %w", err)
 	}
-	return &dockerClient{client: cli}, nil
+	return &instrumentedDockerClient{next: &dockerClient{client: cli, registryAuth: registryAuth}}, nil
 }
 
-func (d *dockerClient) StartContainer(ctx context.Context, containerName, imageName string) (string, error) {
-	out, err := d.client.ImagePull(ctx, imageName, image.PullOptions{})
+// StartContainer creates and starts a container from an image that was
+// already pulled with PullImage.
+func (d *dockerClient) StartContainer(ctx context.Context, containerName, imageName string, spec *model.ContainerSpec) (string, error) {
+	config, hostConfig, err := buildContainerConfig(imageName, spec)
 	if err != nil {
-		return "", fmt.Errorf("failed to pull image %s: %w", imageName, err)
+		return "", fmt.Errorf("invalid container spec for %s: %w", containerName, err)
 	}
-	defer out.Close()
-	io.Copy(io.Discard, out)
 
+	progress := utils.ProgressFromContext(ctx)
+	progress.Step(model.JobStepCreate, containerName)
 	resp, err := d.client.ContainerCreate(
 		ctx,
-		&container.Config{
-			Image: imageName,
-		},
-		nil,
+		config,
+		hostConfig,
 		nil,
 		nil,
 		containerName,
@@ -52,9 +120,11 @@ func (d *dockerClient) StartContainer(ctx context.Context, containerName, imageN
 		return "", fmt.Errorf("failed to create container %s: %w", containerName, err)
 	}
 
+	progress.Step(model.JobStepStart, containerName)
 	if err := d.client.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
-		// Clean up the container if it fails to start
-		if removeErr := d.RemoveContainer(ctx, resp.ID); removeErr != nil {
+		// Clean up the container if it fails to start, also when the start
+		// failed because ctx was cancelled.
+		if removeErr := d.RemoveContainer(context.WithoutCancel(ctx), resp.ID); removeErr != nil {
 			return "", fmt.Errorf("failed to remove container %s after start failure: %w", resp.ID, removeErr)
 		}
 		return "", fmt.Errorf("failed to start container %s: %w", containerName, err)
@@ -63,6 +133,57 @@ func (d *dockerClient) StartContainer(ctx context.Context, containerName, imageN
 	return resp.ID, nil
 }
 
+// PullImage pulls imageName and reports the download of each layer to the
+// progress reporter of ctx.
+func (d *dockerClient) PullImage(ctx context.Context, imageName string) error {
+	progress := utils.ProgressFromContext(ctx)
+	progress.Step(model.JobStepPull, imageName)
+
+	var pullOptions image.PullOptions
+	if d.registryAuth != nil {
+		var err error
+		if pullOptions.RegistryAuth, err = d.registryAuth.RegistryAuth(ctx, imageName); err != nil {
+			return fmt.Errorf("failed to resolve registry credentials for %s: %w", imageName, err)
+		}
+	}
+
+	out, err := d.client.ImagePull(ctx, imageName, pullOptions)
+	if err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", imageName, err)
+	}
+	defer out.Close()
+
+	return readPullProgress(out, imageName, progress)
+}
+
+// readPullProgress reads the progress messages of an image pull. The daemon
+// reports a failed pull in the stream rather than as an error of the call.
+func readPullProgress(r io.Reader, imageName string, progress utils.ProgressReporter) error {
+	decoder := json.NewDecoder(r)
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := decoder.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read pull progress of image %s: %w", imageName, err)
+		}
+		if msg.Error != nil {
+			return fmt.Errorf("failed to pull image %s: %s", imageName, msg.Error.Message)
+		}
+		// Messages without an ID, and the one naming the tag being pulled,
+		// are about the whole image rather than a layer.
+		if msg.ID == "" || strings.HasPrefix(msg.Status, "Pulling from") {
+			continue
+		}
+		var current, total int64
+		if msg.Progress != nil {
+			current, total = msg.Progress.Current, msg.Progress.Total
+		}
+		progress.Layer(msg.ID, msg.Status, current, total)
+	}
+}
+
 func (d *dockerClient) StopContainer(ctx context.Context, containerID string) error {
 	if err := d.client.ContainerStop(ctx, containerID, container.StopOptions{}); err != nil {
 		return fmt.Errorf("failed to stop container %s: %w", containerID, err)
@@ -83,3 +204,248 @@ func (d *dockerClient) StartExistingContainer(ctx context.Context, containerID s
 	}
 	return nil
 }
+
+// RestartContainer stops and starts the container. timeout is how many
+// seconds to wait for it to stop before it is killed; nil uses the stop
+// timeout of the container.
+func (d *dockerClient) RestartContainer(ctx context.Context, containerID string, timeout *int) error {
+	if err := d.client.ContainerRestart(ctx, containerID, container.StopOptions{Timeout: timeout}); err != nil {
+		return fmt.Errorf("failed to restart container %s: %w", containerID, actionError(err))
+	}
+	return nil
+}
+
+func (d *dockerClient) PauseContainer(ctx context.Context, containerID string) error {
+	if err := d.client.ContainerPause(ctx, containerID); err != nil {
+		return fmt.Errorf("failed to pause container %s: %w", containerID, actionError(err))
+	}
+	return nil
+}
+
+func (d *dockerClient) UnpauseContainer(ctx context.Context, containerID string) error {
+	if err := d.client.ContainerUnpause(ctx, containerID); err != nil {
+		return fmt.Errorf("failed to unpause container %s: %w", containerID, actionError(err))
+	}
+	return nil
+}
+
+// KillContainer sends signal to the main process of the container, or
+// SIGKILL when signal is empty.
+func (d *dockerClient) KillContainer(ctx context.Context, containerID, signal string) error {
+	if err := d.client.ContainerKill(ctx, containerID, signal); err != nil {
+		return fmt.Errorf("failed to kill container %s: %w", containerID, actionError(err))
+	}
+	return nil
+}
+
+// WaitContainerStopped waits up to timeout for the container to stop
+// running and reports whether it did.
+func (d *dockerClient) WaitContainerStopped(ctx context.Context, containerID string, timeout time.Duration) (bool, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	results, errs := d.client.ContainerWait(waitCtx, containerID, container.WaitConditionNotRunning)
+	select {
+	case <-results:
+		return true, nil
+	case err := <-errs:
+		if waitCtx.Err() != nil && ctx.Err() == nil {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to wait for container %s: %w", containerID, actionError(err))
+	}
+}
+
+// actionError maps the daemon's refusal of an action to the matching
+// sentinel error, keeping the daemon's message.
+func actionError(err error) error {
+	switch {
+	case cerrdefs.IsNotFound(err):
+		return fmt.Errorf("%w: %v", ErrContainerNotFound, err)
+	case cerrdefs.IsConflict(err):
+		return fmt.Errorf("%w: %v", ErrContainerConflict, err)
+	case cerrdefs.IsInvalidArgument(err):
+		return fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+	}
+	return err
+}
+
+func (d *dockerClient) ListContainers(ctx context.Context) ([]ContainerState, error) {
+	summaries, err := d.client.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	states := make([]ContainerState, 0, len(summaries))
+	for _, summary := range summaries {
+		var name string
+		if len(summary.Names) > 0 {
+			name = strings.TrimPrefix(summary.Names[0], "/")
+		}
+		states = append(states, ContainerState{
+			ID:     summary.ID,
+			Name:   name,
+			Image:  summary.Image,
+			State:  summary.State,
+			Labels: summary.Labels,
+		})
+	}
+	return states, nil
+}
+
+func (d *dockerClient) InspectContainer(ctx context.Context, containerID string) (*ContainerState, error) {
+	resp, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return nil, fmt.Errorf("failed to inspect container %s: %w", containerID, ErrContainerNotFound)
+		}
+		return nil, fmt.Errorf("failed to inspect container %s: %w", containerID, err)
+	}
+
+	state := &ContainerState{
+		ID:   resp.ID,
+		Name: strings.TrimPrefix(resp.Name, "/"),
+	}
+	if resp.Config != nil {
+		state.Image = resp.Config.Image
+		state.Labels = resp.Config.Labels
+	}
+	if resp.State != nil {
+		state.State = resp.State.Status
+	}
+	return state, nil
+}
+
+// Events subscribes to the container lifecycle events the service tracks,
+// replaying everything the daemon still has since the given time. The event
+// channel is closed once the subscription ends; the reason is sent on the
+// error channel.
+func (d *dockerClient) Events(ctx context.Context, since time.Time) (<-chan ContainerEvent, <-chan error) {
+	args := filters.NewArgs(
+		filters.Arg("type", string(events.ContainerEventType)),
+		filters.Arg("event", string(events.ActionStart)),
+		filters.Arg("event", string(events.ActionDie)),
+		filters.Arg("event", string(events.ActionStop)),
+		filters.Arg("event", string(events.ActionPause)),
+		filters.Arg("event", string(events.ActionUnPause)),
+		filters.Arg("event", string(events.ActionRestart)),
+		filters.Arg("event", string(events.ActionOOM)),
+		filters.Arg("event", string(events.ActionHealthStatus)),
+		filters.Arg("event", string(events.ActionDestroy)),
+	)
+
+	options := events.ListOptions{Filters: args}
+	if !since.IsZero() {
+		options.Since = fmt.Sprintf("%d.%09d", since.Unix(), since.Nanosecond())
+	}
+
+	messages, errs := d.client.Events(ctx, options)
+
+	out := make(chan ContainerEvent)
+	outErrs := make(chan error, 1)
+	go func() {
+		defer close(out)
+		defer close(outErrs)
+
+		for {
+			select {
+			case msg := <-messages:
+				event := ContainerEvent{
+					ContainerID: msg.Actor.ID,
+					Name:        msg.Actor.Attributes["name"],
+					Action:      string(msg.Action),
+					Time:        time.Unix(0, msg.TimeNano).UTC(),
+					Attributes:  msg.Actor.Attributes,
+				}
+				select {
+				case out <- event:
+				case <-ctx.Done():
+					outErrs <- ctx.Err()
+					return
+				}
+			case err := <-errs:
+				if err == nil {
+					err = io.EOF
+				}
+				outErrs <- fmt.Errorf("docker events stream closed: %w", err)
+				return
+			}
+		}
+	}()
+
+	return out, outErrs
+}
+
+func buildContainerConfig(imageName string, spec *model.ContainerSpec) (*container.Config, *container.HostConfig, error) {
+	config := &container.Config{
+		Image: imageName,
+	}
+	hostConfig := &container.HostConfig{}
+
+	if spec == nil {
+		return config, hostConfig, nil
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	for key, value := range spec.Env {
+		config.Env = append(config.Env, key+"="+value)
+	}
+	config.Labels = spec.Labels
+	config.Cmd = spec.Command
+
+	if len(spec.Ports) > 0 {
+		config.ExposedPorts = nat.PortSet{}
+		hostConfig.PortBindings = nat.PortMap{}
+	}
+	for _, p := range spec.Ports {
+		protocol := p.Protocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		port, err := nat.NewPort(protocol, strconv.Itoa(p.ContainerPort))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid port %d/%s: %w", p.ContainerPort, protocol, err)
+		}
+		config.ExposedPorts[port] = struct{}{}
+
+		binding := nat.PortBinding{HostIP: p.HostIP}
+		if p.HostPort > 0 {
+			binding.HostPort = strconv.Itoa(p.HostPort)
+		}
+		hostConfig.PortBindings[port] = append(hostConfig.PortBindings[port], binding)
+	}
+
+	for _, v := range spec.Volumes {
+		mountType := mount.TypeVolume
+		if path.IsAbs(v.Source) {
+			mountType = mount.TypeBind
+		}
+		hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+			Type:     mountType,
+			Source:   v.Source,
+			Target:   v.Target,
+			ReadOnly: v.ReadOnly,
+		})
+	}
+
+	if spec.RestartPolicy != "" {
+		hostConfig.RestartPolicy = container.RestartPolicy{
+			Name: container.RestartPolicyMode(spec.RestartPolicy),
+		}
+	}
+
+	hostConfig.Resources.CPUShares = spec.CPUShares
+	hostConfig.Resources.Memory = spec.MemoryBytes
+
+	return config, hostConfig, nil
+}
+
+func (d *dockerClient) Ping(ctx context.Context) error {
+	if _, err := d.client.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping Docker daemon: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// layerReport is a call of ProgressReporter.Layer.
+type layerReport struct {
+	id, status     string
+	current, total int64
+}
+
+type recordingProgress struct {
+	layers []layerReport
+}
+
+func (p *recordingProgress) Step(name, target string) {}
+
+func (p *recordingProgress) Layer(id, status string, current, total int64) {
+	p.layers = append(p.layers, layerReport{id, status, current, total})
+}
+
+func TestReadPullProgress(t *testing.T) {
+	stream := `{"status":"Pulling from library/nginx","id":"1.27"}
+{"status":"Pulling fs layer","progressDetail":{},"id":"a1"}
+{"status":"Downloading","progressDetail":{"current":512,"total":2048},"progress":"[==>  ]","id":"a1"}
+{"status":"Download complete","progressDetail":{},"id":"a1"}
+{"status":"Digest: sha256:abc"}
+{"status":"Status: Downloaded newer image for nginx:1.27"}
+`
+	progress := &recordingProgress{}
+	if err := readPullProgress(strings.NewReader(stream), "nginx:1.27", progress); err != nil {
+		t.Fatalf("readPullProgress() error = %v", err)
+	}
+
+	want := []layerReport{
+		{"a1", "Pulling fs layer", 0, 0},
+		{"a1", "Downloading", 512, 2048},
+		{"a1", "Download complete", 0, 0},
+	}
+	if !reflect.DeepEqual(progress.layers, want) {
+		t.Errorf("layers = %+v, want %+v", progress.layers, want)
+	}
+}
+
+func TestReadPullProgressErrors(t *testing.T) {
+	tests := map[string]struct {
+		stream string
+		want   string
+	}{
+		"error in stream": {
+			stream: `{"status":"Pulling fs layer","id":"a1"}
+{"errorDetail":{"message":"manifest unknown"},"error":"manifest unknown"}
+`,
+			want: "failed to pull image nginx:1.27: manifest unknown",
+		},
+		"malformed stream": {
+			stream: `{"status":"Downloading","id":`,
+			want:   "failed to read pull progress of image nginx:1.27",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := readPullProgress(strings.NewReader(tt.stream), "nginx:1.27", &recordingProgress{})
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("readPullProgress() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
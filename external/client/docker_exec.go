@@ -0,0 +1,138 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"thanhnt208/container-adm-service/internal/dto"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+const (
+	// execExitPollInterval and execExitPollAttempts bound how long ExitCode
+	// waits for the daemon to notice a process whose output has ended.
+	execExitPollInterval = 100 * time.Millisecond
+	execExitPollAttempts = 20
+)
+
+// IExecSession is a command running in a container with its standard
+// streams attached.
+type IExecSession interface {
+	ID() string
+	// Write sends input to the command's stdin.
+	Write(p []byte) (int, error)
+	// CloseStdin signals the end of input.
+	CloseStdin() error
+	// CopyOutput copies the command's output until it exits or the session
+	// is closed. With a TTY everything is written to stdout.
+	CopyOutput(stdout, stderr io.Writer) error
+	Resize(ctx context.Context, width, height uint) error
+	// ExitCode returns the exit code once the command has finished.
+	ExitCode(ctx context.Context) (int, error)
+	Close() error
+}
+
+type execSession struct {
+	id     string
+	tty    bool
+	conn   types.HijackedResponse
+	client *client.Client
+}
+
+// Exec creates an exec instance running opts.Cmd in the container and
+// attaches to its stdin, stdout and stderr, which starts it.
+func (d *dockerClient) Exec(ctx context.Context, containerID string, opts dto.ExecOptions) (IExecSession, error) {
+	var consoleSize *[2]uint
+	if opts.Tty && opts.Width > 0 && opts.Height > 0 {
+		consoleSize = &[2]uint{opts.Height, opts.Width}
+	}
+
+	created, err := d.client.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+		Cmd:          opts.Cmd,
+		Tty:          opts.Tty,
+		ConsoleSize:  consoleSize,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exec in container %s: %w", containerID, actionError(err))
+	}
+
+	conn, err := d.client.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{
+		Tty:         opts.Tty,
+		ConsoleSize: consoleSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach to exec %s: %w", created.ID, actionError(err))
+	}
+
+	return &execSession{
+		id:     created.ID,
+		tty:    opts.Tty,
+		conn:   conn,
+		client: d.client,
+	}, nil
+}
+
+func (s *execSession) ID() string {
+	return s.id
+}
+
+func (s *execSession) Write(p []byte) (int, error) {
+	return s.conn.Conn.Write(p)
+}
+
+func (s *execSession) CloseStdin() error {
+	return s.conn.CloseWrite()
+}
+
+func (s *execSession) CopyOutput(stdout, stderr io.Writer) error {
+	var err error
+	if s.tty {
+		_, err = io.Copy(stdout, s.conn.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, s.conn.Reader)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read output of exec %s: %w", s.id, err)
+	}
+	return nil
+}
+
+func (s *execSession) Resize(ctx context.Context, width, height uint) error {
+	if err := s.client.ContainerExecResize(ctx, s.id, container.ResizeOptions{Width: width, Height: height}); err != nil {
+		return fmt.Errorf("failed to resize exec %s: %w", s.id, actionError(err))
+	}
+	return nil
+}
+
+func (s *execSession) ExitCode(ctx context.Context) (int, error) {
+	for attempt := 0; ; attempt++ {
+		info, err := s.client.ContainerExecInspect(ctx, s.id)
+		if err != nil {
+			return -1, fmt.Errorf("failed to inspect exec %s: %w", s.id, actionError(err))
+		}
+		if !info.Running {
+			return info.ExitCode, nil
+		}
+		if attempt == execExitPollAttempts {
+			return -1, fmt.Errorf("exec %s is still running", s.id)
+		}
+
+		select {
+		case <-ctx.Done():
+			return -1, ctx.Err()
+		case <-time.After(execExitPollInterval):
+		}
+	}
+}
+
+func (s *execSession) Close() error {
+	return s.conn.Conn.Close()
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"thanhnt208/container-adm-service/internal/dto"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+const (
+	// logFrameHeaderLen is the size of the header Docker puts in front of
+	// every frame of a multiplexed stream: the stream type in the first
+	// byte and the payload size as a big-endian uint32 in the last four.
+	logFrameHeaderLen = 8
+
+	// maxLogLineLen splits lines longer than this, so a container writing
+	// without newlines cannot make us buffer without bound.
+	maxLogLineLen = 64 * 1024
+)
+
+// Logs reads the log of a container line by line. The line channel is
+// closed once the log ends, or with Follow once ctx is done; the reason is
+// sent on the error channel, io.EOF for a log that was read to the end.
+func (d *dockerClient) Logs(ctx context.Context, containerID string, opts dto.ContainerLogOptions) (<-chan dto.ContainerLogLine, <-chan error) {
+	out := make(chan dto.ContainerLogLine)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errs)
+
+		// Containers with a TTY have a single raw stream without framing.
+		info, err := d.client.ContainerInspect(ctx, containerID)
+		if err != nil {
+			errs <- fmt.Errorf("failed to inspect container %s: %w", containerID, actionError(err))
+			return
+		}
+		tty := info.Config != nil && info.Config.Tty
+
+		rc, err := d.client.ContainerLogs(ctx, containerID, logsOptions(opts))
+		if err != nil {
+			errs <- fmt.Errorf("failed to read logs of container %s: %w", containerID, actionError(err))
+			return
+		}
+		defer rc.Close()
+
+		emit := func(line dto.ContainerLogLine) bool {
+			if opts.Timestamps {
+				line = splitLogTimestamp(line)
+			}
+			select {
+			case out <- line:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if tty {
+			err = readRawLog(rc, emit)
+		} else {
+			err = readMultiplexedLog(rc, emit)
+		}
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, context.Canceled) {
+			err = fmt.Errorf("failed to read logs of container %s: %w", containerID, err)
+		}
+		errs <- err
+	}()
+
+	return out, errs
+}
+
+func logsOptions(opts dto.ContainerLogOptions) container.LogsOptions {
+	options := container.LogsOptions{
+		ShowStdout: opts.Stdout,
+		ShowStderr: opts.Stderr,
+		Follow:     opts.Follow,
+		Timestamps: opts.Timestamps,
+		Tail:       "all",
+	}
+	if opts.Tail >= 0 {
+		options.Tail = strconv.Itoa(opts.Tail)
+	}
+	if !opts.Since.IsZero() {
+		options.Since = fmt.Sprintf("%d.%09d", opts.Since.Unix(), opts.Since.Nanosecond())
+	}
+	if !opts.Until.IsZero() {
+		options.Until = fmt.Sprintf("%d.%09d", opts.Until.Unix(), opts.Until.Nanosecond())
+	}
+	return options
+}
+
+// readRawLog splits the unframed output of a TTY container into lines. A TTY
+// merges stderr into stdout, so every line is reported as stdout.
+func readRawLog(r io.Reader, emit func(dto.ContainerLogLine) bool) error {
+	reader := bufio.NewReaderSize(r, maxLogLineLen)
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if len(chunk) > 0 {
+			text := strings.TrimRight(string(chunk), "\r\n")
+			if !emit(dto.ContainerLogLine{Stream: dto.LogStreamStdout, Text: text}) {
+				return nil
+			}
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readMultiplexedLog demultiplexes Docker's framed stream. A frame is not
+// necessarily a whole line, so each stream keeps the start of its current
+// line until the rest arrives.
+func readMultiplexedLog(r io.Reader, emit func(dto.ContainerLogLine) bool) error {
+	pending := map[string]*bytes.Buffer{
+		dto.LogStreamStdout: {},
+		dto.LogStreamStderr: {},
+	}
+	flush := func() {
+		for _, stream := range []string{dto.LogStreamStdout, dto.LogStreamStderr} {
+			if buf := pending[stream]; buf.Len() > 0 {
+				emit(dto.ContainerLogLine{Stream: stream, Text: strings.TrimRight(buf.String(), "\r")})
+				buf.Reset()
+			}
+		}
+	}
+
+	header := make([]byte, logFrameHeaderLen)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				err = fmt.Errorf("truncated log frame header: %w", err)
+			}
+			flush()
+			return err
+		}
+
+		size := binary.BigEndian.Uint32(header[4:])
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			flush()
+			return fmt.Errorf("truncated log frame: %w", err)
+		}
+
+		var stream string
+		switch stdcopy.StdType(header[0]) {
+		case stdcopy.Stdin, stdcopy.Stdout:
+			stream = dto.LogStreamStdout
+		case stdcopy.Stderr:
+			stream = dto.LogStreamStderr
+		case stdcopy.Systemerr:
+			flush()
+			return fmt.Errorf("docker log stream error: %s", payload)
+		default:
+			flush()
+			return fmt.Errorf("unknown log stream type %d", header[0])
+		}
+
+		buf := pending[stream]
+		for len(payload) > 0 {
+			i := bytes.IndexByte(payload, '\n')
+			if i < 0 {
+				buf.Write(payload)
+				break
+			}
+			buf.Write(payload[:i])
+			payload = payload[i+1:]
+			if !emit(dto.ContainerLogLine{Stream: stream, Text: strings.TrimRight(buf.String(), "\r")}) {
+				return nil
+			}
+			buf.Reset()
+		}
+		if buf.Len() > maxLogLineLen {
+			if !emit(dto.ContainerLogLine{Stream: stream, Text: buf.String()}) {
+				return nil
+			}
+			buf.Reset()
+		}
+	}
+}
+
+// splitLogTimestamp moves the RFC3339 timestamp Docker puts in front of
+// every line into the Timestamp field.
+func splitLogTimestamp(line dto.ContainerLogLine) dto.ContainerLogLine {
+	prefix, text, ok := strings.Cut(line.Text, " ")
+	if !ok {
+		prefix, text = line.Text, ""
+	}
+	ts, err := time.Parse(time.RFC3339Nano, prefix)
+	if err != nil {
+		return line
+	}
+	ts = ts.UTC()
+	line.Timestamp = &ts
+	line.Text = text
+	return line
+}
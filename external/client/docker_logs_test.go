@@ -0,0 +1,110 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"thanhnt208/container-adm-service/internal/dto"
+
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+func logFrame(stream stdcopy.StdType, payload string) []byte {
+	frame := make([]byte, logFrameHeaderLen, logFrameHeaderLen+len(payload))
+	frame[0] = byte(stream)
+	binary.BigEndian.PutUint32(frame[4:], uint32(len(payload)))
+	return append(frame, payload...)
+}
+
+func readLines(t *testing.T, data []byte) ([]dto.ContainerLogLine, error) {
+	t.Helper()
+	var lines []dto.ContainerLogLine
+	err := readMultiplexedLog(bytes.NewReader(data), func(line dto.ContainerLogLine) bool {
+		lines = append(lines, line)
+		return true
+	})
+	return lines, err
+}
+
+func TestReadMultiplexedLogInterleaved(t *testing.T) {
+	var data []byte
+	data = append(data, logFrame(stdcopy.Stdout, "hello ")...)
+	data = append(data, logFrame(stdcopy.Stderr, "warn")...)
+	data = append(data, logFrame(stdcopy.Stdout, "world\r\nsecond\n")...)
+	data = append(data, logFrame(stdcopy.Stderr, "ing\n")...)
+	data = append(data, logFrame(stdcopy.Stdout, "no newline")...)
+
+	lines, err := readLines(t, data)
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("readMultiplexedLog() error = %v, want io.EOF", err)
+	}
+
+	want := []dto.ContainerLogLine{
+		{Stream: dto.LogStreamStdout, Text: "hello world"},
+		{Stream: dto.LogStreamStdout, Text: "second"},
+		{Stream: dto.LogStreamStderr, Text: "warning"},
+		{Stream: dto.LogStreamStdout, Text: "no newline"},
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %+v, want %+v", lines, want)
+	}
+}
+
+func TestReadMultiplexedLogTruncated(t *testing.T) {
+	full := logFrame(stdcopy.Stderr, "complete\npartial")
+
+	tests := []struct {
+		name string
+		data []byte
+		want []dto.ContainerLogLine
+	}{
+		{
+			name: "truncated payload",
+			data: append(logFrame(stdcopy.Stdout, "start"), full[:len(full)-3]...),
+			want: []dto.ContainerLogLine{{Stream: dto.LogStreamStdout, Text: "start"}},
+		},
+		{
+			name: "truncated header",
+			data: append(full, logFrame(stdcopy.Stdout, "x")[:4]...),
+			want: []dto.ContainerLogLine{
+				{Stream: dto.LogStreamStderr, Text: "complete"},
+				{Stream: dto.LogStreamStderr, Text: "partial"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := readLines(t, tt.data)
+			if err == nil || errors.Is(err, io.EOF) {
+				t.Fatalf("readMultiplexedLog() error = %v, want a truncation error", err)
+			}
+			if !reflect.DeepEqual(lines, tt.want) {
+				t.Errorf("lines = %+v, want %+v", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMultiplexedLogSplitsLongLines(t *testing.T) {
+	long := string(bytes.Repeat([]byte("a"), maxLogLineLen+1))
+	lines, err := readLines(t, logFrame(stdcopy.Stdout, long))
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("readMultiplexedLog() error = %v, want io.EOF", err)
+	}
+	if len(lines) != 1 || lines[0].Text != long {
+		t.Errorf("got %d lines, want the long line emitted once", len(lines))
+	}
+}
+
+func TestLogsOptionsTail(t *testing.T) {
+	if got := logsOptions(dto.ContainerLogOptions{Tail: -1}).Tail; got != "all" {
+		t.Errorf("Tail = %q for -1, want %q", got, "all")
+	}
+	if got := logsOptions(dto.ContainerLogOptions{Tail: 10001}).Tail; got != "10001" {
+		t.Errorf("Tail = %q for 10001, want %q", got, "10001")
+	}
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/pkg/metrics"
+	"time"
+)
+
+// instrumentedDockerClient records latency and errors of every Docker API
+// call made through the wrapped client.
+type instrumentedDockerClient struct {
+	next IDockerClient
+}
+
+func observeDockerCall(operation string, start time.Time, err error) {
+	metrics.DockerCallDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	// A missing container or a refused action is an answer, not a failure of
+	// the daemon.
+	if err != nil && !errors.Is(err, ErrContainerNotFound) &&
+		!errors.Is(err, ErrContainerConflict) && !errors.Is(err, ErrInvalidArgument) {
+		metrics.DockerCallErrors.WithLabelValues(operation).Inc()
+	}
+}
+
+func (c *instrumentedDockerClient) PullImage(ctx context.Context, imageName string) error {
+	start := time.Now()
+	err := c.next.PullImage(ctx, imageName)
+	observeDockerCall("pull_image", start, err)
+	return err
+}
+
+func (c *instrumentedDockerClient) StartContainer(ctx context.Context, containerName, imageName string, spec *model.ContainerSpec) (string, error) {
+	start := time.Now()
+	id, err := c.next.StartContainer(ctx, containerName, imageName, spec)
+	observeDockerCall("start_container", start, err)
+	return id, err
+}
+
+func (c *instrumentedDockerClient) StopContainer(ctx context.Context, containerID string) error {
+	start := time.Now()
+	err := c.next.StopContainer(ctx, containerID)
+	observeDockerCall("stop_container", start, err)
+	return err
+}
+
+func (c *instrumentedDockerClient) RemoveContainer(ctx context.Context, containerID string) error {
+	start := time.Now()
+	err := c.next.RemoveContainer(ctx, containerID)
+	observeDockerCall("remove_container", start, err)
+	return err
+}
+
+func (c *instrumentedDockerClient) StartExistingContainer(ctx context.Context, containerID string) error {
+	start := time.Now()
+	err := c.next.StartExistingContainer(ctx, containerID)
+	observeDockerCall("start_existing_container", start, err)
+	return err
+}
+
+func (c *instrumentedDockerClient) RestartContainer(ctx context.Context, containerID string, timeout *int) error {
+	start := time.Now()
+	err := c.next.RestartContainer(ctx, containerID, timeout)
+	observeDockerCall("restart_container", start, err)
+	return err
+}
+
+func (c *instrumentedDockerClient) PauseContainer(ctx context.Context, containerID string) error {
+	start := time.Now()
+	err := c.next.PauseContainer(ctx, containerID)
+	observeDockerCall("pause_container", start, err)
+	return err
+}
+
+func (c *instrumentedDockerClient) UnpauseContainer(ctx context.Context, containerID string) error {
+	start := time.Now()
+	err := c.next.UnpauseContainer(ctx, containerID)
+	observeDockerCall("unpause_container", start, err)
+	return err
+}
+
+func (c *instrumentedDockerClient) KillContainer(ctx context.Context, containerID, signal string) error {
+	start := time.Now()
+	err := c.next.KillContainer(ctx, containerID, signal)
+	observeDockerCall("kill_container", start, err)
+	return err
+}
+
+func (c *instrumentedDockerClient) WaitContainerStopped(ctx context.Context, containerID string, timeout time.Duration) (bool, error) {
+	start := time.Now()
+	stopped, err := c.next.WaitContainerStopped(ctx, containerID, timeout)
+	observeDockerCall("wait_container", start, err)
+	return stopped, err
+}
+
+func (c *instrumentedDockerClient) ListContainers(ctx context.Context) ([]ContainerState, error) {
+	start := time.Now()
+	states, err := c.next.ListContainers(ctx)
+	observeDockerCall("list_containers", start, err)
+	return states, err
+}
+
+func (c *instrumentedDockerClient) InspectContainer(ctx context.Context, containerID string) (*ContainerState, error) {
+	start := time.Now()
+	state, err := c.next.InspectContainer(ctx, containerID)
+	observeDockerCall("inspect_container", start, err)
+	return state, err
+}
+
+// Events is a long-lived stream, so only its failures are counted.
+func (c *instrumentedDockerClient) Events(ctx context.Context, since time.Time) (<-chan ContainerEvent, <-chan error) {
+	events, errs := c.next.Events(ctx, since)
+
+	out := make(chan error, 1)
+	go func() {
+		defer close(out)
+		for err := range errs {
+			if err != nil && ctx.Err() == nil {
+				metrics.DockerCallErrors.WithLabelValues("events").Inc()
+			}
+			out <- err
+		}
+	}()
+
+	return events, out
+}
+
+// Logs is a stream as well; only failures other than the end of the log or
+// a missing container are counted.
+func (c *instrumentedDockerClient) Logs(ctx context.Context, containerID string, opts dto.ContainerLogOptions) (<-chan dto.ContainerLogLine, <-chan error) {
+	lines, errs := c.next.Logs(ctx, containerID, opts)
+
+	out := make(chan error, 1)
+	go func() {
+		defer close(out)
+		for err := range errs {
+			if err != nil && ctx.Err() == nil && !errors.Is(err, io.EOF) && !errors.Is(err, ErrContainerNotFound) {
+				metrics.DockerCallErrors.WithLabelValues("logs").Inc()
+			}
+			out <- err
+		}
+	}()
+
+	return lines, out
+}
+
+func (c *instrumentedDockerClient) Stats(ctx context.Context, containerID string) (*dto.ContainerStats, error) {
+	start := time.Now()
+	stats, err := c.next.Stats(ctx, containerID)
+	observeDockerCall("stats", start, err)
+	return stats, err
+}
+
+// StreamStats is a stream like Logs and counted the same way.
+func (c *instrumentedDockerClient) StreamStats(ctx context.Context, containerID string) (<-chan dto.ContainerStats, <-chan error) {
+	stats, errs := c.next.StreamStats(ctx, containerID)
+
+	out := make(chan error, 1)
+	go func() {
+		defer close(out)
+		for err := range errs {
+			if err != nil && ctx.Err() == nil && !errors.Is(err, io.EOF) && !errors.Is(err, ErrContainerNotFound) {
+				metrics.DockerCallErrors.WithLabelValues("stream_stats").Inc()
+			}
+			out <- err
+		}
+	}()
+
+	return stats, out
+}
+
+func (c *instrumentedDockerClient) Exec(ctx context.Context, containerID string, opts dto.ExecOptions) (IExecSession, error) {
+	start := time.Now()
+	session, err := c.next.Exec(ctx, containerID, opts)
+	observeDockerCall("exec", start, err)
+	return session, err
+}
+
+func (c *instrumentedDockerClient) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := c.next.Ping(ctx)
+	observeDockerCall("ping", start, err)
+	return err
+}
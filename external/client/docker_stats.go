@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"thanhnt208/container-adm-service/internal/dto"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// Stats returns a single stats snapshot of a running container. The daemon
+// takes two readings for it, so the call lasts about a second.
+func (d *dockerClient) Stats(ctx context.Context, containerID string) (*dto.ContainerStats, error) {
+	resp, err := d.client.ContainerStats(ctx, containerID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats of container %s: %w", containerID, actionError(err))
+	}
+	defer resp.Body.Close()
+
+	var raw container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode stats of container %s: %w", containerID, err)
+	}
+	return computeStats(&raw), nil
+}
+
+// StreamStats sends a stats snapshot about every second until ctx is done
+// or the container stops. The stats channel is then closed and the reason is
+// sent on the error channel, io.EOF when the daemon ended the stream.
+func (d *dockerClient) StreamStats(ctx context.Context, containerID string) (<-chan dto.ContainerStats, <-chan error) {
+	out := make(chan dto.ContainerStats)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errs)
+
+		resp, err := d.client.ContainerStats(ctx, containerID, true)
+		if err != nil {
+			errs <- fmt.Errorf("failed to stream stats of container %s: %w", containerID, actionError(err))
+			return
+		}
+		defer resp.Body.Close()
+
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var raw container.StatsResponse
+			if err := decoder.Decode(&raw); err != nil {
+				if ctx.Err() != nil {
+					err = ctx.Err()
+				} else if !errors.Is(err, io.EOF) {
+					err = fmt.Errorf("failed to decode stats of container %s: %w", containerID, err)
+				}
+				errs <- err
+				return
+			}
+
+			select {
+			case out <- *computeStats(&raw):
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return out, errs
+}
+
+// computeStats derives usage figures from a raw stats reading the same way
+// the docker CLI does.
+func computeStats(raw *container.StatsResponse) *dto.ContainerStats {
+	stats := &dto.ContainerStats{
+		Time:        raw.Read.UTC(),
+		OnlineCPUs:  raw.CPUStats.OnlineCPUs,
+		MemoryLimit: raw.MemoryStats.Limit,
+		PIDs:        raw.PidsStats.Current,
+	}
+
+	if stats.OnlineCPUs == 0 {
+		stats.OnlineCPUs = uint32(len(raw.CPUStats.CPUUsage.PercpuUsage))
+	}
+	cpuDelta := float64(raw.CPUStats.CPUUsage.TotalUsage) - float64(raw.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(raw.CPUStats.SystemUsage) - float64(raw.PreCPUStats.SystemUsage)
+	if cpuDelta > 0 && systemDelta > 0 {
+		stats.CPUPercent = cpuDelta / systemDelta * float64(stats.OnlineCPUs) * 100
+	}
+
+	// Page cache the kernel can reclaim is not counted as used, matching
+	// docker stats: total_inactive_file on cgroup v1, inactive_file on v2.
+	// v1 reports both, and only the total includes child cgroups.
+	stats.MemoryUsage = raw.MemoryStats.Usage
+	inactive, ok := raw.MemoryStats.Stats["total_inactive_file"]
+	if !ok {
+		inactive = raw.MemoryStats.Stats["inactive_file"]
+	}
+	if inactive < stats.MemoryUsage {
+		stats.MemoryUsage -= inactive
+	}
+	if stats.MemoryLimit > 0 {
+		stats.MemoryPercent = float64(stats.MemoryUsage) / float64(stats.MemoryLimit) * 100
+	}
+
+	for _, network := range raw.Networks {
+		stats.NetworkRxBytes += network.RxBytes
+		stats.NetworkTxBytes += network.TxBytes
+	}
+
+	for _, entry := range raw.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			stats.BlockReadBytes += entry.Value
+		case "write":
+			stats.BlockWriteBytes += entry.Value
+		}
+	}
+
+	return stats
+}
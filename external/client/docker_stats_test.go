@@ -0,0 +1,37 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestComputeStatsMemoryUsage(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats map[string]uint64
+		want  uint64
+	}{
+		{"cgroup v2", map[string]uint64{"inactive_file": 300}, 700},
+		{"cgroup v1 prefers the total", map[string]uint64{"inactive_file": 100, "total_inactive_file": 300}, 700},
+		{"no page cache figures", nil, 1000},
+		{"inactive above usage", map[string]uint64{"inactive_file": 2000}, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw := &container.StatsResponse{}
+			raw.MemoryStats.Usage = 1000
+			raw.MemoryStats.Limit = 2000
+			raw.MemoryStats.Stats = tt.stats
+
+			stats := computeStats(raw)
+			if stats.MemoryUsage != tt.want {
+				t.Errorf("MemoryUsage = %d, want %d", stats.MemoryUsage, tt.want)
+			}
+			if want := float64(tt.want) / 2000 * 100; stats.MemoryPercent != want {
+				t.Errorf("MemoryPercent = %v, want %v", stats.MemoryPercent, want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package infrastructure
 
 import (
+	"context"
 	"fmt"
 	"thanhnt208/container-adm-service/config"
 	"time"
@@ -48,6 +49,21 @@ func (d *Database) ConnectDB() (*gorm.DB, error) {
 	return d.db, nil
 }
 
+func (d *Database) Ping(ctx context.Context) error {
+	if d.db == nil {
+		if _, err := d.ConnectDB(); err != nil {
+			return err
+		}
+	}
+
+	sqlDB, err := d.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql.DB from gorm.DB: %w", err)
+	}
+
+	return sqlDB.PingContext(ctx)
+}
+
 func (d *Database) Close() error {
 	if d.db == nil {
 		return nil
@@ -1,6 +1,7 @@
 package infrastructure
 
 import (
+	"context"
 	"fmt"
 	"thanhnt208/container-adm-service/config"
 
@@ -34,6 +35,25 @@ func (e *Elasticsearch) ConnectElasticsearch() (*elasticsearch.Client, error) {
 	return e.client, nil
 }
 
+func (e *Elasticsearch) Ping(ctx context.Context) error {
+	if e.client == nil {
+		if _, err := e.ConnectElasticsearch(); err != nil {
+			return err
+		}
+	}
+
+	res, err := e.client.Ping(e.client.Ping.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to ping Elasticsearch: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch ping failed: %s", res.Status())
+	}
+	return nil
+}
+
 func (e *Elasticsearch) Close() error {
 	return nil
 }
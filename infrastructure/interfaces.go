@@ -11,6 +11,7 @@ import (
 
 type IDatabase interface {
 	ConnectDB() (*gorm.DB, error)
+	Ping(ctx context.Context) error
 	Close() error
 }
 
@@ -22,11 +23,25 @@ type IRedis interface {
 
 type IElasticsearch interface {
 	ConnectElasticsearch() (*elasticsearch.Client, error)
+	Ping(ctx context.Context) error
 	Close() error
 }
 
 type IKafka interface {
 	ConnectProducer() (*kafka.Writer, error)
 	ConnectConsumer(topics []string) (*kafka.Reader, error)
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// MessageReader is the consumer side of *kafka.Reader used with manual commits.
+type MessageReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
 	Close() error
 }
+
+// MessageWriter is the producer side of *kafka.Writer.
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
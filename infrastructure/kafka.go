@@ -1,6 +1,7 @@
 package infrastructure
 
 import (
+	"context"
 	"fmt"
 	"thanhnt208/container-adm-service/config"
 	"time"
@@ -23,10 +24,11 @@ func NewKafka(cfg *config.Config) IKafka {
 }
 
 func (k *Kafka) ConnectProducer() (*kafka.Writer, error) {
+	// No default topic: every message names its own. Messages are balanced by
+	// key so that all messages for one key stay in order on one partition.
 	k.writer = &kafka.Writer{
 		Addr:         kafka.TCP(k.brokers...),
-		Topic:        k.cfg.KafkaTopic,
-		Balancer:     &kafka.LeastBytes{},
+		Balancer:     &kafka.Hash{},
 		RequiredAcks: kafka.RequireAll,
 		Async:        false,
 	}
@@ -47,6 +49,31 @@ func (k *Kafka) ConnectConsumer(topics []string) (*kafka.Reader, error) {
 	return k.reader, nil
 }
 
+// Ping succeeds when at least one broker accepts a connection and returns
+// the cluster metadata.
+func (k *Kafka) Ping(ctx context.Context) error {
+	var lastErr error
+	for _, broker := range k.brokers {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		_, err = conn.Brokers()
+		conn.Close()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		return fmt.Errorf("no Kafka brokers configured")
+	}
+	return fmt.Errorf("failed to reach Kafka brokers: %w", lastErr)
+}
+
 func (k *Kafka) Close() error {
 	var writerErr, readerErr error
 
@@ -0,0 +1,32 @@
+// Package bootstrap holds the start-up wiring shared by the REST, gRPC and
+// Kafka binaries. Like the rest of main, it logs and panics on failure.
+package bootstrap
+
+import (
+	"thanhnt208/container-adm-service/config"
+	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/utils"
+)
+
+// NewImagePolicy loads the image policy from IMAGE_POLICY_FILE. The caller
+// starts Watch once its context exists.
+func NewImagePolicy(cfg *config.Config, log logger.ILogger) service.IImagePolicy {
+	imagePolicy, err := service.NewImagePolicy(cfg.ImagePolicyFile, log)
+	if err != nil {
+		log.Error("Failed to load image policy", "error", err)
+		panic("Failed to load image policy: " + err.Error())
+	}
+	return imagePolicy
+}
+
+// NewSecretCipher builds the cipher for registry passwords from
+// REGISTRY_CREDENTIALS_KEY.
+func NewSecretCipher(cfg *config.Config, log logger.ILogger) *utils.SecretCipher {
+	secretCipher, err := utils.NewSecretCipher(cfg.RegistryCredsKey)
+	if err != nil {
+		log.Error("Invalid registry credentials key", "error", err)
+		panic("Invalid registry credentials key: " + err.Error())
+	}
+	return secretCipher
+}
@@ -2,23 +2,35 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"thanhnt208/container-adm-service/internal/dto"
 	"thanhnt208/container-adm-service/internal/service"
 	"thanhnt208/container-adm-service/pkg/logger"
 	"thanhnt208/container-adm-service/proto/pb"
 	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// defaultLogTail is the number of log lines sent when a request sets no tail.
+const defaultLogTail = 100
+
 type GrpcServerHandler struct {
-	service service.IContainerService
+	service    service.IContainerService
+	jobService service.IJobService
 	pb.UnimplementedContainerAdmServiceServer
 	logger logger.ILogger
 }
 
-func NewGrpcServerHandler(service service.IContainerService, logger logger.ILogger) *GrpcServerHandler {
+func NewGrpcServerHandler(service service.IContainerService, jobService service.IJobService, logger logger.ILogger) *GrpcServerHandler {
 	return &GrpcServerHandler{
-		service: service,
-		logger:  logger,
+		service:    service,
+		jobService: jobService,
+		logger:     logger,
 	}
 }
 
@@ -68,6 +80,11 @@ func (h *GrpcServerHandler) GetContainerInformation(ctx context.Context, req *pb
 		return nil, fmt.Errorf("startTime must be less than endTime")
 	}
 
+	if !isValidUptimeMode(req.GetMode()) {
+		h.logger.Error("GetContainerInformation: invalid mode", "mode", req.GetMode())
+		return nil, fmt.Errorf("invalid mode: %s", req.GetMode())
+	}
+
 	numContainers, err := h.service.GetNumContainers(ctx)
 	if err != nil {
 		h.logger.Error("GetContainerInformation: failed to get number of containers", "error", err)
@@ -88,7 +105,7 @@ func (h *GrpcServerHandler) GetContainerInformation(ctx context.Context, req *pb
 	startTimeObj := time.Unix(startTime, 0)
 	endTimeObj := time.Unix(endTime, 0)
 
-	uptimeRatio, err := h.service.GetContainerUptimeRatio(ctx, startTimeObj, endTimeObj)
+	uptimeRatio, err := h.service.GetContainerUptimeRatio(ctx, startTimeObj, endTimeObj, req.GetMode())
 	if err != nil {
 		h.logger.Error("GetContainerInformation: failed to get uptime ratio", "error", err)
 		return nil, fmt.Errorf("failed to get uptime ratio: %w", err)
@@ -135,6 +152,11 @@ func (h *GrpcServerHandler) GetContainerUptimeDuration(ctx context.Context, req
         return nil, fmt.Errorf("startTime must be less than endTime")
     }
 
+    if !isValidUptimeMode(req.GetMode()) {
+        h.logger.Error("GetContainerUptimeDuration: invalid mode", "mode", req.GetMode())
+        return nil, fmt.Errorf("invalid mode: %s", req.GetMode())
+    }
+
     numContainers, err := h.service.GetNumContainers(ctx)
     if err != nil {
         h.logger.Error("GetContainerUptimeDuration: failed to get number of containers", "error", err)
@@ -155,7 +177,7 @@ func (h *GrpcServerHandler) GetContainerUptimeDuration(ctx context.Context, req
     startTimeObj := time.Unix(startTime, 0).UTC()
     endTimeObj := time.Unix(endTime, 0).UTC()
 
-    uptimeDetails, err := h.service.GetContainerUptimeDuration(ctx, startTimeObj, endTimeObj)
+    uptimeDetails, err := h.service.GetContainerUptimeDuration(ctx, startTimeObj, endTimeObj, req.GetMode())
     if err != nil {
         h.logger.Error("GetContainerUptimeDuration: failed to get uptime duration", "error", err)
         return nil, fmt.Errorf("failed to get uptime duration: %w", err)
@@ -184,3 +206,286 @@ func (h *GrpcServerHandler) GetContainerUptimeDuration(ctx context.Context, req
     }, nil
 }
 
+func (h *GrpcServerHandler) GetContainerStatusHistory(ctx context.Context, req *pb.GetContainerStatusHistoryRequest) (*pb.GetContainerStatusHistoryResponse, error) {
+	if req == nil {
+		h.logger.Error("GetContainerStatusHistory: request cannot be nil")
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	if req.GetId() == 0 {
+		h.logger.Error("GetContainerStatusHistory: id is required")
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	endTime := time.Now().UTC()
+	if req.GetEndTime() > 0 {
+		endTime = time.Unix(req.GetEndTime(), 0).UTC()
+	}
+
+	startTime := endTime.Add(-24 * time.Hour)
+	if req.GetStartTime() > 0 {
+		startTime = time.Unix(req.GetStartTime(), 0).UTC()
+	}
+
+	if !startTime.Before(endTime) {
+		h.logger.Error("GetContainerStatusHistory: startTime must be less than endTime", "startTime", startTime, "endTime", endTime)
+		return nil, status.Error(codes.InvalidArgument, "startTime must be less than endTime")
+	}
+
+	size := int(req.GetSize())
+	if size <= 0 {
+		size = 100
+	}
+	if size > 1000 {
+		h.logger.Error("GetContainerStatusHistory: size exceeds limit", "size", size)
+		return nil, status.Error(codes.InvalidArgument, "size must not exceed 1000")
+	}
+
+	history, err := h.service.GetContainerStatusHistory(ctx, uint(req.GetId()), startTime, endTime, req.GetStatus(), size, req.GetCursor())
+	if errors.Is(err, service.ErrInvalidStatus) || errors.Is(err, service.ErrInvalidHistoryCursor) {
+		h.logger.Warn("GetContainerStatusHistory: invalid request", "id", req.GetId(), "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, service.ErrContainerNotFound) {
+		h.logger.Warn("GetContainerStatusHistory: container not found", "id", req.GetId())
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		h.logger.Error("GetContainerStatusHistory: failed to get status history", "id", req.GetId(), "error", err)
+		return nil, status.Errorf(codes.Internal, "failed to get status history: %v", err)
+	}
+
+	spans := make([]*pb.StatusSpan, len(history.Spans))
+	for i, span := range history.Spans {
+		spans[i] = &pb.StatusSpan{
+			Status:    span.Status,
+			StartedAt: span.StartedAt.Unix(),
+			EndedAt:   span.EndedAt.Unix(),
+			Duration:  span.DurationMs,
+			Ongoing:   span.Ongoing,
+		}
+	}
+
+	h.logger.Info("GetContainerStatusHistory: successfully retrieved status history", "id", req.GetId(), "spans", len(spans))
+
+	return &pb.GetContainerStatusHistoryResponse{
+		Id:         uint64(history.ID),
+		Spans:      spans,
+		NextCursor: history.NextCursor,
+	}, nil
+}
+
+func (h *GrpcServerHandler) StartContainer(ctx context.Context, req *pb.ContainerActionRequest) (*pb.ContainerActionResponse, error) {
+	return h.runContainerAction(ctx, req, dto.ContainerActionStart)
+}
+
+func (h *GrpcServerHandler) StopContainer(ctx context.Context, req *pb.ContainerActionRequest) (*pb.ContainerActionResponse, error) {
+	return h.runContainerAction(ctx, req, dto.ContainerActionStop)
+}
+
+func (h *GrpcServerHandler) RestartContainer(ctx context.Context, req *pb.ContainerActionRequest) (*pb.ContainerActionResponse, error) {
+	return h.runContainerAction(ctx, req, dto.ContainerActionRestart)
+}
+
+func (h *GrpcServerHandler) PauseContainer(ctx context.Context, req *pb.ContainerActionRequest) (*pb.ContainerActionResponse, error) {
+	return h.runContainerAction(ctx, req, dto.ContainerActionPause)
+}
+
+func (h *GrpcServerHandler) UnpauseContainer(ctx context.Context, req *pb.ContainerActionRequest) (*pb.ContainerActionResponse, error) {
+	return h.runContainerAction(ctx, req, dto.ContainerActionUnpause)
+}
+
+func (h *GrpcServerHandler) KillContainer(ctx context.Context, req *pb.ContainerActionRequest) (*pb.ContainerActionResponse, error) {
+	return h.runContainerAction(ctx, req, dto.ContainerActionKill)
+}
+
+func (h *GrpcServerHandler) runContainerAction(ctx context.Context, req *pb.ContainerActionRequest, action string) (*pb.ContainerActionResponse, error) {
+	if req == nil {
+		h.logger.Error("ContainerAction: request cannot be nil", "action", action)
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	if req.GetId() == 0 {
+		h.logger.Error("ContainerAction: id is required", "action", action)
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if req.GetTimeout() < 0 {
+		h.logger.Error("ContainerAction: timeout must not be negative", "action", action, "timeout", req.GetTimeout())
+		return nil, status.Error(codes.InvalidArgument, "timeout must not be negative")
+	}
+
+	container, err := h.service.RunContainerAction(ctx, uint(req.GetId()), action, dto.ContainerActionRequest{
+		Timeout: int(req.GetTimeout()),
+		Signal:  req.GetSignal(),
+	})
+	if err != nil {
+		h.logger.Error("ContainerAction: failed to run action", "id", req.GetId(), "action", action, "error", err)
+		return nil, containerActionError(action, err)
+	}
+
+	h.logger.Info("ContainerAction: successfully ran action", "id", req.GetId(), "action", action, "status", container.Status)
+
+	return &pb.ContainerActionResponse{
+		Id:          uint64(container.ID),
+		Status:      container.Status,
+		ContainerId: container.ContainerID,
+	}, nil
+}
+
+func (h *GrpcServerHandler) StreamContainerLogs(req *pb.StreamContainerLogsRequest, stream grpc.ServerStreamingServer[pb.ContainerLogLine]) error {
+	if req == nil {
+		h.logger.Error("StreamContainerLogs: request cannot be nil")
+		return fmt.Errorf("request cannot be nil")
+	}
+
+	if req.GetId() == 0 {
+		h.logger.Error("StreamContainerLogs: id is required")
+		return fmt.Errorf("id is required")
+	}
+
+	opts := dto.ContainerLogOptions{
+		Follow:     req.GetFollow(),
+		Tail:       int(req.GetTail()),
+		Timestamps: req.GetTimestamps(),
+		Stdout:     req.GetStdout(),
+		Stderr:     req.GetStderr(),
+	}
+	if opts.Tail == 0 {
+		opts.Tail = defaultLogTail
+	}
+	if req.GetSince() > 0 {
+		opts.Since = time.Unix(req.GetSince(), 0).UTC()
+	}
+	if req.GetUntil() > 0 {
+		opts.Until = time.Unix(req.GetUntil(), 0).UTC()
+	}
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && !opts.Since.Before(opts.Until) {
+		h.logger.Error("StreamContainerLogs: since must be less than until", "since", opts.Since, "until", opts.Until)
+		return fmt.Errorf("since must be less than until")
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	lines, errs, err := h.service.StreamContainerLogs(ctx, uint(req.GetId()), opts)
+	if errors.Is(err, service.ErrContainerNotFound) {
+		h.logger.Warn("StreamContainerLogs: container not found", "id", req.GetId())
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		h.logger.Error("StreamContainerLogs: failed to read logs", "id", req.GetId(), "error", err)
+		return fmt.Errorf("failed to read container logs: %w", err)
+	}
+
+	sent := 0
+	for line := range lines {
+		msg := &pb.ContainerLogLine{
+			Stream: line.Stream,
+			Text:   line.Text,
+		}
+		if line.Timestamp != nil {
+			msg.TimestampNano = line.Timestamp.UnixNano()
+		}
+		if err := stream.Send(msg); err != nil {
+			cancel()
+			for range lines {
+			}
+			<-errs
+			h.logger.Warn("StreamContainerLogs: client went away", "id", req.GetId(), "error", err)
+			return err
+		}
+		sent++
+	}
+
+	if err := <-errs; err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, context.Canceled) {
+		h.logger.Error("StreamContainerLogs: failed to read logs", "id", req.GetId(), "error", err)
+		return fmt.Errorf("failed to read container logs: %w", err)
+	}
+
+	h.logger.Info("StreamContainerLogs: log stream ended", "id", req.GetId(), "lines", sent)
+	return nil
+}
+
+func (h *GrpcServerHandler) StreamContainerStats(req *pb.StreamContainerStatsRequest, stream grpc.ServerStreamingServer[pb.ContainerStats]) error {
+	if req == nil {
+		h.logger.Error("StreamContainerStats: request cannot be nil")
+		return status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	if req.GetId() == 0 {
+		h.logger.Error("StreamContainerStats: id is required")
+		return status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	samples, errs, err := h.service.StreamContainerStats(ctx, uint(req.GetId()))
+	if errors.Is(err, service.ErrContainerNotFound) {
+		h.logger.Warn("StreamContainerStats: container not found", "id", req.GetId())
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, service.ErrContainerNotRunning) {
+		h.logger.Warn("StreamContainerStats: container is not running", "id", req.GetId(), "error", err)
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		h.logger.Error("StreamContainerStats: failed to read stats", "id", req.GetId(), "error", err)
+		return fmt.Errorf("failed to read container stats: %w", err)
+	}
+
+	sent := 0
+	for stats := range samples {
+		msg := &pb.ContainerStats{
+			TimeNano:         stats.Time.UnixNano(),
+			CpuPercent:       stats.CPUPercent,
+			OnlineCpus:       stats.OnlineCPUs,
+			MemoryUsageBytes: stats.MemoryUsage,
+			MemoryLimitBytes: stats.MemoryLimit,
+			MemoryPercent:    stats.MemoryPercent,
+			NetworkRxBytes:   stats.NetworkRxBytes,
+			NetworkTxBytes:   stats.NetworkTxBytes,
+			BlockReadBytes:   stats.BlockReadBytes,
+			BlockWriteBytes:  stats.BlockWriteBytes,
+			Pids:             stats.PIDs,
+		}
+		if err := stream.Send(msg); err != nil {
+			cancel()
+			for range samples {
+			}
+			<-errs
+			h.logger.Warn("StreamContainerStats: client went away", "id", req.GetId(), "error", err)
+			return err
+		}
+		sent++
+	}
+
+	if err := <-errs; err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, context.Canceled) {
+		h.logger.Error("StreamContainerStats: failed to read stats", "id", req.GetId(), "error", err)
+		return fmt.Errorf("failed to read container stats: %w", err)
+	}
+
+	h.logger.Info("StreamContainerStats: stats stream ended", "id", req.GetId(), "samples", sent)
+	return nil
+}
+
+// containerActionError maps a failed container action to the gRPC status
+// matching the HTTP status the REST route answers with.
+func containerActionError(action string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrContainerNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidContainerAction):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrContainerStateConflict):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return status.Errorf(codes.Internal, "failed to %s container: %v", action, err)
+}
+
+func isValidUptimeMode(mode string) bool {
+	return mode == "" || mode == dto.UptimeModeSample || mode == dto.UptimeModeTransition
+}
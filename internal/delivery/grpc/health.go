@@ -0,0 +1,42 @@
+package grpc
+
+import (
+	"context"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/proto/pb"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// WatchHealth runs the readiness checks every interval and publishes the
+// result through the standard grpc.health.v1 service, both for the server
+// as a whole and for ContainerAdmService. It returns when ctx is cancelled.
+func WatchHealth(ctx context.Context, server *health.Server, healthService service.IHealthService, logger logger.ILogger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		status := healthpb.HealthCheckResponse_SERVING
+		if report := healthService.Readiness(ctx); report.Status != dto.HealthStatusUp {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+
+		if status != last {
+			logger.Info("gRPC health status changed", "status", status.String())
+			last = status
+		}
+		server.SetServingStatus("", status)
+		server.SetServingStatus(pb.ContainerAdmService_ServiceDesc.ServiceName, status)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/pkg/authz"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/pkg/metrics"
+	"thanhnt208/container-adm-service/pkg/ratelimit"
+	"thanhnt208/container-adm-service/proto/pb"
+	"thanhnt208/container-adm-service/utils"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// MetricsUnaryInterceptor records the latency and status code of unary calls.
+func MetricsUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		metrics.GRPCRequestDuration.
+			WithLabelValues(info.FullMethod, status.Code(err).String()).
+			Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+// MetricsStreamInterceptor records the duration and status code of streams.
+func MetricsStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		metrics.GRPCRequestDuration.
+			WithLabelValues(info.FullMethod, status.Code(err).String()).
+			Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// MethodActions maps each RPC to the action it is authorized for, mirroring
+// the REST routes. Methods missing from this map are denied unless they are
+// public.
+var MethodActions = map[string]string{
+	pb.ContainerAdmService_GetAllContainers_FullMethodName:           authz.ActionContainerRead,
+	pb.ContainerAdmService_GetContainerInformation_FullMethodName:    authz.ActionContainerRead,
+	pb.ContainerAdmService_GetContainerUptimeDuration_FullMethodName: authz.ActionContainerRead,
+	pb.ContainerAdmService_GetContainerStatusHistory_FullMethodName:  authz.ActionContainerRead,
+	pb.ContainerAdmService_StartContainer_FullMethodName:             authz.ActionContainerStart,
+	pb.ContainerAdmService_StopContainer_FullMethodName:              authz.ActionContainerStop,
+	pb.ContainerAdmService_RestartContainer_FullMethodName:           authz.ActionContainerRestart,
+	pb.ContainerAdmService_PauseContainer_FullMethodName:             authz.ActionContainerPause,
+	pb.ContainerAdmService_UnpauseContainer_FullMethodName:           authz.ActionContainerUnpause,
+	pb.ContainerAdmService_KillContainer_FullMethodName:              authz.ActionContainerKill,
+	pb.ContainerAdmService_StreamContainerLogs_FullMethodName:        authz.ActionContainerLogs,
+	pb.ContainerAdmService_StreamContainerStats_FullMethodName:       authz.ActionContainerRead,
+	pb.ContainerAdmService_WatchJob_FullMethodName:                   authz.ActionJobRead,
+	pb.ContainerAdmService_CancelJob_FullMethodName:                  authz.ActionJobCancel,
+}
+
+// containerRequest is implemented by requests that target a single container.
+type containerRequest interface {
+	GetId() uint64
+}
+
+// publicServicePrefix is the health service, which probes call without a token.
+const publicServicePrefix = "/grpc.health.v1.Health/"
+
+// CollectionMethods act on many containers. Rules with conditions limit the
+// containers they cover instead of deciding upfront.
+var CollectionMethods = map[string]bool{
+	pb.ContainerAdmService_GetAllContainers_FullMethodName:           true,
+	pb.ContainerAdmService_GetContainerInformation_FullMethodName:    true,
+	pb.ContainerAdmService_GetContainerUptimeDuration_FullMethodName: true,
+}
+
+// AuthUnaryInterceptor authenticates the bearer token in the call metadata,
+// authorizes the action of the method against the policy and passes the
+// claims on in the context. Requests carrying a container ID are authorized
+// against that container, and collection methods pass the scope they were
+// authorized for on in the context.
+func AuthUnaryInterceptor(authzService service.IAuthzService, logger logger.ILogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, claims, err := authenticate(ctx, info.FullMethod, logger)
+		if err != nil {
+			return nil, err
+		}
+		if claims != nil {
+			if CollectionMethods[info.FullMethod] {
+				ctx, err = authorizeCollection(ctx, info.FullMethod, claims, authzService, logger)
+			} else {
+				err = authorizeRequest(ctx, info.FullMethod, claims, req, authzService, logger)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor is the streaming counterpart of AuthUnaryInterceptor.
+// Server-streaming calls carry a single request, so they are authorized once
+// the handler receives it, against the container it names. Other streams are
+// authorized upfront, where only rules without conditions can allow them.
+func AuthStreamInterceptor(authzService service.IAuthzService, logger logger.ILogger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, claims, err := authenticate(ss.Context(), info.FullMethod, logger)
+		if err != nil {
+			return err
+		}
+
+		stream := &authenticatedStream{ServerStream: ss, ctx: ctx}
+		if claims != nil {
+			if info.IsServerStream && !info.IsClientStream {
+				stream.authorize = func(req interface{}) error {
+					return authorizeRequest(ctx, info.FullMethod, claims, req, authzService, logger)
+				}
+			} else if err := authorizeRequest(ctx, info.FullMethod, claims, nil, authzService, logger); err != nil {
+				return err
+			}
+		}
+		return handler(srv, stream)
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	// authorize checks the first received message, when set.
+	authorize func(req interface{}) error
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authenticatedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if authorize := s.authorize; authorize != nil {
+		s.authorize = nil
+		return authorize(m)
+	}
+	return nil
+}
+
+// authenticate verifies the bearer token and returns the context carrying
+// its claims. Public methods are let through without claims.
+func authenticate(ctx context.Context, method string, logger logger.ILogger) (context.Context, *utils.Claims, error) {
+	meta := utils.RequestMeta{Transport: utils.TransportGRPC}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			meta.SourceIP = host
+		} else {
+			meta.SourceIP = p.Addr.String()
+		}
+	}
+	ctx = utils.ContextWithRequestMeta(ctx, meta)
+
+	if strings.HasPrefix(method, publicServicePrefix) {
+		return ctx, nil, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	authHeader := ""
+	if values := md.Get("authorization"); len(values) > 0 {
+		authHeader = values[0]
+	}
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return nil, nil, status.Error(codes.Unauthenticated, "missing or invalid token")
+	}
+
+	claims, err := utils.ParseJWT(ctx, strings.TrimPrefix(authHeader, "Bearer "))
+	if errors.Is(err, utils.ErrTokenRevoked) {
+		logger.Warn("Rejected gRPC call with revoked token", "method", method)
+		return nil, nil, status.Error(codes.Unauthenticated, "token has been revoked")
+	}
+	if err != nil {
+		logger.Warn("Rejected gRPC call with invalid token", "method", method, "error", err)
+		return nil, nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	}
+
+	return utils.ContextWithClaims(ctx, claims), claims, nil
+}
+
+// authorizeRequest evaluates the policy for the action of method, against
+// the container req names when it names one. ctx must carry the claims so
+// the container is loaded with the caller's visibility.
+func authorizeRequest(ctx context.Context, method string, claims *utils.Claims, req interface{}, authzService service.IAuthzService, logger logger.ILogger) error {
+	action, ok := MethodActions[method]
+	if !ok {
+		logger.Warn("Rejected gRPC call to method without action mapping", "method", method)
+		return status.Error(codes.PermissionDenied, "access denied")
+	}
+
+	var containerID uint
+	if r, ok := req.(containerRequest); ok {
+		containerID = uint(r.GetId())
+	}
+
+	decision, err := authzService.Authorize(ctx, service.SubjectFromClaims(claims), action, containerID)
+	if err != nil {
+		logger.Error("Failed to authorize gRPC call", "method", method, "error", err)
+		return status.Error(codes.Internal, "failed to authorize request")
+	}
+	if !decision.Allowed {
+		logger.Warn("Rejected gRPC call denied by policy", "method", method, "username", claims.Username)
+		return status.Error(codes.PermissionDenied, "access denied: "+decision.Reason)
+	}
+	return nil
+}
+
+// authorizeCollection evaluates the policy for the action of a collection
+// method and returns ctx carrying the scope the results must be limited to.
+func authorizeCollection(ctx context.Context, method string, claims *utils.Claims, authzService service.IAuthzService, logger logger.ILogger) (context.Context, error) {
+	action, ok := MethodActions[method]
+	if !ok {
+		logger.Warn("Rejected gRPC call to method without action mapping", "method", method)
+		return nil, status.Error(codes.PermissionDenied, "access denied")
+	}
+
+	decision, scope := authzService.AuthorizeCollection(service.SubjectFromClaims(claims), action)
+	if !decision.Allowed {
+		logger.Warn("Rejected gRPC call denied by policy", "method", method, "username", claims.Username)
+		return nil, status.Error(codes.PermissionDenied, "access denied: "+decision.Reason)
+	}
+	return authz.ContextWithScope(ctx, scope), nil
+}
+
+// RateLimitUnaryInterceptor applies the rate limit of the method's action to
+// the calling user. It must be chained after the auth interceptor.
+func RateLimitUnaryInterceptor(limiter ratelimit.IRateLimiter, logger logger.ILogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := rateLimit(ctx, limiter, info.FullMethod, logger); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitStreamInterceptor counts each stream as a single request.
+func RateLimitStreamInterceptor(limiter ratelimit.IRateLimiter, logger logger.ILogger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := rateLimit(ss.Context(), limiter, info.FullMethod, logger); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func rateLimit(ctx context.Context, limiter ratelimit.IRateLimiter, method string, logger logger.ILogger) error {
+	claims, ok := utils.ClaimsFromContext(ctx)
+	if !ok {
+		// Only public methods get here without claims.
+		return nil
+	}
+
+	scope := ratelimit.DefaultScope
+	if action, ok := MethodActions[method]; ok {
+		scope = action
+	}
+	key := "user:" + strconv.FormatUint(uint64(claims.UserID), 10) + ":" + method
+
+	res, err := limiter.Allow(ctx, scope, key)
+	if err != nil || res.Allowed {
+		return nil
+	}
+
+	metrics.RateLimitedRequests.WithLabelValues(scope, utils.TransportGRPC).Inc()
+	retryAfter := strconv.Itoa(max(1, int(math.Ceil(res.RetryAfter.Seconds()))))
+	if err := grpc.SetHeader(ctx, metadata.Pairs("retry-after", retryAfter)); err != nil {
+		logger.Warn("Failed to set retry-after header", "method", method, "error", err)
+	}
+	logger.Warn("Rate limited gRPC call", "method", method, "username", claims.Username)
+	return status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry after %ss", retryAfter)
+}
@@ -0,0 +1,136 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/proto/pb"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WatchJob sends the job whenever it changes, starting with its current
+// state, and ends the stream once the job finished.
+func (h *GrpcServerHandler) WatchJob(req *pb.WatchJobRequest, stream grpc.ServerStreamingServer[pb.Job]) error {
+	if req == nil {
+		h.logger.Error("WatchJob: request cannot be nil")
+		return status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	if _, err := uuid.Parse(req.GetId()); err != nil {
+		h.logger.Error("WatchJob: invalid job id", "id", req.GetId())
+		return status.Errorf(codes.InvalidArgument, "invalid job id: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	jobs, errs, err := h.jobService.WatchJob(ctx, req.GetId())
+	if err != nil {
+		h.logger.Error("WatchJob: failed to retrieve job", "id", req.GetId(), "error", err)
+		return jobError("retrieve", err)
+	}
+
+	for job := range jobs {
+		if err := stream.Send(toPbJob(&job)); err != nil {
+			cancel()
+			for range jobs {
+			}
+			<-errs
+			h.logger.Warn("WatchJob: client went away", "id", req.GetId(), "error", err)
+			return err
+		}
+	}
+
+	if err := <-errs; err != nil {
+		h.logger.Error("WatchJob: failed to retrieve job", "id", req.GetId(), "error", err)
+		return jobError("retrieve", err)
+	}
+
+	h.logger.Info("WatchJob: job finished", "id", req.GetId())
+	return nil
+}
+
+// CancelJob cancels a queued job, or asks the worker of a running job to
+// stop it.
+func (h *GrpcServerHandler) CancelJob(ctx context.Context, req *pb.CancelJobRequest) (*pb.Job, error) {
+	if req == nil {
+		h.logger.Error("CancelJob: request cannot be nil")
+		return nil, status.Error(codes.InvalidArgument, "request cannot be nil")
+	}
+
+	if _, err := uuid.Parse(req.GetId()); err != nil {
+		h.logger.Error("CancelJob: invalid job id", "id", req.GetId())
+		return nil, status.Errorf(codes.InvalidArgument, "invalid job id: %v", err)
+	}
+
+	job, err := h.jobService.CancelJob(ctx, req.GetId())
+	if err != nil {
+		h.logger.Error("CancelJob: failed to cancel job", "id", req.GetId(), "error", err)
+		return nil, jobError("cancel", err)
+	}
+
+	h.logger.Info("CancelJob: cancellation requested", "id", job.ID, "status", job.Status)
+	return toPbJob(job), nil
+}
+
+// jobError maps a failed job operation to the gRPC status matching the HTTP
+// status the REST route answers with.
+func jobError(action string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrJobNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrJobFinished):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Errorf(codes.Internal, "failed to %s job: %v", action, err)
+}
+
+func toPbJob(job *model.Job) *pb.Job {
+	steps := make([]*pb.JobStep, len(job.Steps))
+	for i, step := range job.Steps {
+		layers := make(map[string]*pb.JobLayer, len(step.Layers))
+		for id, layer := range step.Layers {
+			layers[id] = &pb.JobLayer{
+				Status:  layer.Status,
+				Current: layer.Current,
+				Total:   layer.Total,
+			}
+		}
+		steps[i] = &pb.JobStep{
+			Name:           step.Name,
+			Target:         step.Target,
+			Status:         step.Status,
+			Layers:         layers,
+			StartedAtNano:  step.StartedAt.UnixNano(),
+			FinishedAtNano: unixNano(step.FinishedAt),
+		}
+	}
+
+	return &pb.Job{
+		Id:              job.ID,
+		Type:            job.Type,
+		Status:          job.Status,
+		Steps:           steps,
+		Result:          string(job.Result),
+		Error:           job.Error,
+		ContainerId:     uint64(job.ContainerID),
+		CancelRequested: job.CancelRequested,
+		CreatedAtNano:   job.CreatedAt.UnixNano(),
+		UpdatedAtNano:   job.UpdatedAt.UnixNano(),
+		StartedAtNano:   unixNano(job.StartedAt),
+		FinishedAtNano:  unixNano(job.FinishedAt),
+	}
+}
+
+func unixNano(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.UnixNano()
+}
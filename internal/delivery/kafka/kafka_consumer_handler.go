@@ -3,81 +3,417 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"sync"
+	"thanhnt208/container-adm-service/infrastructure"
+	"thanhnt208/container-adm-service/internal/dto"
 	"thanhnt208/container-adm-service/internal/service"
 	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/pkg/metrics"
+	"thanhnt208/container-adm-service/utils"
+	"time"
 
 	"github.com/segmentio/kafka-go"
 )
 
+// errPoisonMessage marks messages that can never be processed, such as
+// malformed payloads. They go to the dead-letter topic without retrying.
+var errPoisonMessage = errors.New("poison message")
+
+type ConsumerOptions struct {
+	Workers         int
+	QueueSize       int
+	MaxRetries      int
+	InitialBackoff  time.Duration
+	MaxBackoff      time.Duration
+	DeadLetterTopic string
+}
+
 type KafkaConsumerHandler struct {
 	service service.IContainerService
 	logger  logger.ILogger
-	reader  *kafka.Reader
+	reader  infrastructure.MessageReader
+	dlq     infrastructure.MessageWriter
+	opts    ConsumerOptions
+
+	commitMu   sync.Mutex
+	partitions map[topicPartition]*partitionOffsets
 }
 
-func NewKafkaConsumerHandler(service service.IContainerService, logger logger.ILogger, reader *kafka.Reader) *KafkaConsumerHandler {
+type job struct {
+	message kafka.Message
+	payload *dto.StatusMessage
+	err     error
+}
+
+func NewKafkaConsumerHandler(service service.IContainerService, logger logger.ILogger, reader infrastructure.MessageReader, dlq infrastructure.MessageWriter, opts ConsumerOptions) *KafkaConsumerHandler {
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 64
+	}
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = 500 * time.Millisecond
+	}
+	if opts.MaxBackoff < opts.InitialBackoff {
+		opts.MaxBackoff = opts.InitialBackoff
+	}
+
 	return &KafkaConsumerHandler{
-		service: service,
-		logger:  logger,
-		reader:  reader,
+		service:    service,
+		logger:     logger,
+		reader:     reader,
+		dlq:        dlq,
+		opts:       opts,
+		partitions: make(map[topicPartition]*partitionOffsets),
 	}
 }
 
+// StartConsume fetches messages until ctx is cancelled and dispatches them to
+// a fixed pool of workers. Messages for the same container always go to the
+// same worker, so they are processed in order. Offsets are committed only
+// after a message has been processed or dead-lettered. On shutdown the
+// workers drain what has already been fetched before StartConsume returns.
 func (h *KafkaConsumerHandler) StartConsume(ctx context.Context) error {
+	queues := make([]chan job, h.opts.Workers)
+	var wg sync.WaitGroup
+
+	// In-flight work is allowed to finish after ctx is cancelled; only
+	// retry backoff is cut short by shutdown.
+	workCtx := utils.ContextWithRequestMeta(context.WithoutCancel(ctx), utils.RequestMeta{Transport: utils.TransportKafka})
+
+	for i := range queues {
+		queues[i] = make(chan job, h.opts.QueueSize)
+		wg.Add(1)
+		go func(queue <-chan job) {
+			defer wg.Done()
+			for j := range queue {
+				h.handle(workCtx, ctx, j)
+			}
+		}(queues[i])
+	}
+
+	defer func() {
+		for _, queue := range queues {
+			close(queue)
+		}
+		wg.Wait()
+		h.logger.Info("Kafka consumer workers drained")
+	}()
+
 	for {
+		msg, err := h.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				h.logger.Info("Stopping Kafka consumer due to context cancellation")
+				return ctx.Err()
+			}
+			h.logger.Error("Failed to fetch message from Kafka", "error", err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(h.opts.InitialBackoff):
+			}
+			continue
+		}
+
+		h.track(msg)
+		metrics.KafkaConsumerLag.
+			WithLabelValues(msg.Topic, strconv.Itoa(msg.Partition)).
+			Set(float64(msg.HighWaterMark - msg.Offset - 1))
+
+		j := job{message: msg}
+		j.payload, j.err = decodeStatusMessage(msg)
+
 		select {
+		case queues[h.workerFor(j)] <- j:
 		case <-ctx.Done():
 			h.logger.Info("Stopping Kafka consumer due to context cancellation")
 			return ctx.Err()
-		default:
-			msg, err := h.reader.ReadMessage(ctx)
-			if err != nil {
-				h.logger.Error("Failed to read message from Kafka", "error", err)
-				continue
-			}
+		}
+	}
+}
+
+func (h *KafkaConsumerHandler) workerFor(j job) int {
+	key := string(j.message.Key)
+	if j.payload != nil {
+		key = strconv.FormatUint(uint64(j.payload.ID), 10)
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return int(hash.Sum32() % uint32(h.opts.Workers))
+}
+
+func (h *KafkaConsumerHandler) handle(ctx, shutdownCtx context.Context, j job) {
+	start := time.Now()
+	topic := j.message.Topic
+	defer func() {
+		metrics.KafkaProcessingDuration.WithLabelValues(topic).Observe(time.Since(start).Seconds())
+	}()
+
+	err := j.err
+	attempts := 0
+	if err == nil {
+		attempts, err = h.processWithRetry(ctx, shutdownCtx, j)
+	}
+
+	if err != nil {
+		if shutdownCtx.Err() != nil && !errors.Is(err, errPoisonMessage) {
+			// Leave the offset uncommitted; the message is redelivered after restart.
+			h.logger.Warn("Abandoning message on shutdown", "partition", j.message.Partition, "offset", j.message.Offset, "error", err)
+			metrics.KafkaMessagesProcessed.WithLabelValues(topic, metrics.OutcomeAbandoned).Inc()
+			return
+		}
+		if dlqErr := h.deadLetter(ctx, shutdownCtx, j.message, err, attempts); dlqErr != nil {
+			h.logger.Error("Failed to dead-letter message", "partition", j.message.Partition, "offset", j.message.Offset, "error", dlqErr)
+			metrics.KafkaMessagesProcessed.WithLabelValues(topic, metrics.OutcomeAbandoned).Inc()
+			return
+		}
+		metrics.KafkaMessagesProcessed.WithLabelValues(topic, metrics.OutcomeDeadLettered).Inc()
+	} else {
+		metrics.KafkaMessagesProcessed.WithLabelValues(topic, metrics.OutcomeSuccess).Inc()
+	}
+
+	h.markDone(ctx, j.message)
+}
+
+func (h *KafkaConsumerHandler) processWithRetry(ctx, shutdownCtx context.Context, j job) (int, error) {
+	backoff := h.opts.InitialBackoff
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		if err = h.process(ctx, j.payload); err == nil {
+			return attempt, nil
+		}
+
+		if errors.Is(err, errPoisonMessage) || attempt > h.opts.MaxRetries {
+			return attempt, err
+		}
+
+		h.logger.Warn("Failed to process message, retrying", "ID", j.payload.ID, "attempt", attempt, "retryIn", backoff, "error", err)
+		metrics.KafkaMessagesProcessed.WithLabelValues(j.message.Topic, metrics.OutcomeRetry).Inc()
+		select {
+		case <-shutdownCtx.Done():
+			return attempt, err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > h.opts.MaxBackoff {
+			backoff = h.opts.MaxBackoff
+		}
+	}
+}
 
-			go func(message kafka.Message) {
-				h.logger.Info("Received message from Kafka", "key", string(msg.Key), "value", string(msg.Value))
+func (h *KafkaConsumerHandler) process(ctx context.Context, msg *dto.StatusMessage) error {
+	h.logger.Info("Processing message", "ID", msg.ID, "containerName", msg.ContainerName, "status", msg.Status, "eventTime", msg.EventTime, "producer", msg.Producer, "sequence", msg.Sequence)
 
-				var containerMessage struct {
-					ID            uint   `json:"id"`
-					ContainerName string `json:"container_name"`
-					Status        bool   `json:"status"`
-				}
+	applied, err := h.service.ApplyContainerStatus(ctx, msg.ID, msg.Status, msg.EventTime, msg.Origin())
+	if errors.Is(err, service.ErrQuotaExceeded) {
+		// Retrying will not free up quota.
+		return fmt.Errorf("%w: %v", errPoisonMessage, err)
+	}
+	if err != nil {
+		h.logger.Error("Failed to update container status", "ID", msg.ID, "error", err)
+		return err
+	}
+	if applied {
+		h.logger.Info("Successfully updated container status", "ID", msg.ID, "status", msg.Status)
+	}
 
-				if err := json.Unmarshal(message.Value, &containerMessage); err != nil {
-					h.logger.Error("Failed to unmarshal message", "error", err, "message", string(message.Value))
-					return
-				}
+	// The history is ordered by event time, so a stale status is still
+	// recorded; it only must not overwrite the current state.
+	if err := h.service.AddContainerStatus(ctx, msg.ID, msg.Status, msg.EventTime); err != nil {
+		h.logger.Error("Failed to add container status", "ID", msg.ID, "error", err)
+		return err
+	}
 
-				h.logger.Info("Processing message", "ID", containerMessage.ID, "containerName", containerMessage.ContainerName)
+	h.logger.Info("Successfully added container status", "ID", msg.ID, "status", msg.Status)
+	return nil
+}
 
-				status := "stopped"
-				if containerMessage.Status {
-					status = "running"
-				}
+// decodeStatusMessage decodes both the versioned status message and the
+// legacy v0 payload whose status is a boolean.
+func decodeStatusMessage(message kafka.Message) (*dto.StatusMessage, error) {
+	var raw struct {
+		SchemaVersion int             `json:"schema_version"`
+		ID            uint            `json:"id"`
+		ContainerName string          `json:"container_name"`
+		Status        json.RawMessage `json:"status"`
+		EventTime     *time.Time      `json:"event_time"`
+		Producer      string          `json:"producer"`
+		Sequence      int64           `json:"sequence"`
+	}
+	if err := json.Unmarshal(message.Value, &raw); err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal message: %v", errPoisonMessage, err)
+	}
+	if raw.ID == 0 {
+		return nil, fmt.Errorf("%w: message has no container id", errPoisonMessage)
+	}
 
-				updateData := map[string]interface{}{
-					"status": status,
-				}
+	msg := &dto.StatusMessage{
+		SchemaVersion: raw.SchemaVersion,
+		ID:            raw.ID,
+		ContainerName: raw.ContainerName,
+		Producer:      raw.Producer,
+		Sequence:      raw.Sequence,
+	}
 
-				_, err = h.service.UpdateContainer(ctx, containerMessage.ID, updateData)
-				if err != nil {
-					h.logger.Error("Failed to update container status", "ID", containerMessage.ID, "error", err)
-					return
-				}
-				h.logger.Info("Successfully updated container status", "ID", containerMessage.ID, "status", status)
+	switch raw.SchemaVersion {
+	case 0:
+		var running bool
+		if err := json.Unmarshal(raw.Status, &running); err != nil {
+			return nil, fmt.Errorf("%w: invalid v0 status: %v", errPoisonMessage, err)
+		}
+		msg.Status = "stopped"
+		if running {
+			msg.Status = "running"
+		}
+		msg.EventTime = message.Time
+	case dto.StatusMessageSchemaVersion:
+		if err := json.Unmarshal(raw.Status, &msg.Status); err != nil {
+			return nil, fmt.Errorf("%w: invalid status: %v", errPoisonMessage, err)
+		}
+		if !service.IsReportableStatus(msg.Status) {
+			return nil, fmt.Errorf("%w: unknown status %q", errPoisonMessage, msg.Status)
+		}
+		if raw.EventTime == nil {
+			return nil, fmt.Errorf("%w: message has no event_time", errPoisonMessage)
+		}
+		msg.EventTime = *raw.EventTime
+	default:
+		return nil, fmt.Errorf("%w: unsupported schema version %d", errPoisonMessage, raw.SchemaVersion)
+	}
 
-				h.logger.Info("Write to ES", "ID", containerMessage.ID, "status", status)
-				err = h.service.AddContainerStatus(ctx, containerMessage.ID, status)
-				if err != nil {
-					h.logger.Error("Failed to add container status", "ID", containerMessage.ID, "error", err)
-					return
-				}
+	if msg.EventTime.IsZero() {
+		msg.EventTime = time.Now()
+	}
+	return msg, nil
+}
 
-				h.logger.Info("Successfully added container status", "ID", containerMessage.ID, "status", status)
-			}(msg)
+// deadLetter copies the message to the dead-letter topic together with the
+// reason it failed. The write is retried until it succeeds or shutdown.
+func (h *KafkaConsumerHandler) deadLetter(ctx, shutdownCtx context.Context, msg kafka.Message, cause error, attempts int) error {
+	if h.dlq == nil || h.opts.DeadLetterTopic == "" {
+		h.logger.Error("Dropping failed message, no dead-letter topic configured", "partition", msg.Partition, "offset", msg.Offset, "error", cause)
+		return nil
+	}
+
+	headers := append([]kafka.Header{}, msg.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: "dlq_error", Value: []byte(cause.Error())},
+		kafka.Header{Key: "dlq_original_topic", Value: []byte(msg.Topic)},
+		kafka.Header{Key: "dlq_original_partition", Value: []byte(strconv.Itoa(msg.Partition))},
+		kafka.Header{Key: "dlq_original_offset", Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		kafka.Header{Key: "dlq_attempts", Value: []byte(strconv.Itoa(attempts))},
+		kafka.Header{Key: "dlq_failed_at", Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+	)
+
+	dead := kafka.Message{
+		Topic:   h.opts.DeadLetterTopic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	}
+
+	backoff := h.opts.InitialBackoff
+	for {
+		err := h.dlq.WriteMessages(ctx, dead)
+		if err == nil {
+			h.logger.Warn("Message sent to dead-letter topic", "topic", h.opts.DeadLetterTopic, "partition", msg.Partition, "offset", msg.Offset, "error", cause)
+			return nil
 		}
+
+		h.logger.Error("Failed to write to dead-letter topic, retrying", "retryIn", backoff, "error", err)
+		select {
+		case <-shutdownCtx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > h.opts.MaxBackoff {
+			backoff = h.opts.MaxBackoff
+		}
+	}
+}
+
+type topicPartition struct {
+	topic     string
+	partition int
+}
+
+// partitionOffsets tracks fetched offsets of one partition in fetch order.
+// Workers finish out of order, so only the completed prefix is committed.
+type partitionOffsets struct {
+	pending []int64
+	done    map[int64]kafka.Message
+}
+
+// track records a fetched message. Offsets of a partition only grow while
+// this consumer owns it, so an offset that does not means the partition
+// was reassigned and fetching restarted from the committed offset. What was
+// tracked before is dropped, as it may never be marked done.
+func (h *KafkaConsumerHandler) track(msg kafka.Message) {
+	h.commitMu.Lock()
+	defer h.commitMu.Unlock()
+
+	key := topicPartition{topic: msg.Topic, partition: msg.Partition}
+	p, ok := h.partitions[key]
+	if ok && len(p.pending) > 0 && msg.Offset <= p.pending[len(p.pending)-1] {
+		h.logger.Info("Kafka partition was reassigned, resetting its offsets", "topic", msg.Topic, "partition", msg.Partition, "offset", msg.Offset)
+		ok = false
+	}
+	if !ok {
+		p = &partitionOffsets{done: make(map[int64]kafka.Message)}
+		h.partitions[key] = p
+	}
+	p.pending = append(p.pending, msg.Offset)
+}
+
+func (h *KafkaConsumerHandler) markDone(ctx context.Context, msg kafka.Message) {
+	h.commitMu.Lock()
+	defer h.commitMu.Unlock()
+
+	key := topicPartition{topic: msg.Topic, partition: msg.Partition}
+	p, ok := h.partitions[key]
+	if !ok {
+		return
+	}
+	if _, tracked := slices.BinarySearch(p.pending, msg.Offset); !tracked {
+		// Fetched before the partition was reassigned.
+		return
+	}
+	p.done[msg.Offset] = msg
+
+	var commit *kafka.Message
+	for len(p.pending) > 0 {
+		done, ok := p.done[p.pending[0]]
+		if !ok {
+			break
+		}
+		delete(p.done, p.pending[0])
+		p.pending = p.pending[1:]
+		commit = &done
+	}
+	if len(p.pending) == 0 {
+		// Nothing is left in flight, so a revoked partition leaves no trace.
+		delete(h.partitions, key)
+	}
+
+	if commit == nil {
+		return
+	}
+
+	if err := h.reader.CommitMessages(ctx, *commit); err != nil {
+		// A later commit on this partition covers this offset as well.
+		h.logger.Error("Failed to commit Kafka offset", "partition", commit.Partition, "offset", commit.Offset, "error", err)
 	}
 }
 
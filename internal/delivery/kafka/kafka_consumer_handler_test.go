@@ -0,0 +1,417 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/service"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+func (nopLogger) Fatal(string, ...interface{}) {}
+func (nopLogger) Sync() error                  { return nil }
+
+// fakeReader serves messages from a channel and records commits. It signals
+// idle once every message was fetched and the consumer asks for more.
+type fakeReader struct {
+	messages chan kafka.Message
+	idle     chan struct{}
+
+	mu      sync.Mutex
+	commits []kafka.Message
+}
+
+func newFakeReader(messages ...kafka.Message) *fakeReader {
+	r := &fakeReader{messages: make(chan kafka.Message, len(messages)), idle: make(chan struct{}, 1)}
+	for _, msg := range messages {
+		r.messages <- msg
+	}
+	return r
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	select {
+	case msg := <-r.messages:
+		return msg, nil
+	default:
+	}
+
+	select {
+	case r.idle <- struct{}{}:
+	default:
+	}
+	select {
+	case msg := <-r.messages:
+		return msg, nil
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	}
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commits = append(r.commits, msgs...)
+	return nil
+}
+
+func (r *fakeReader) Close() error { return nil }
+
+// committed returns the highest committed offset of partition, or -1.
+func (r *fakeReader) committed(partition int) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	offset := int64(-1)
+	for _, msg := range r.commits {
+		if msg.Partition == partition && msg.Offset > offset {
+			offset = msg.Offset
+		}
+	}
+	return offset
+}
+
+// fakeWriter records dead-lettered messages after failing the first
+// failures writes.
+type fakeWriter struct {
+	mu       sync.Mutex
+	failures int
+	messages []kafka.Message
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.failures > 0 {
+		w.failures--
+		return errors.New("broker unavailable")
+	}
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+// fakeService applies statuses through apply and records the statuses of
+// every container in the order they were added.
+type fakeService struct {
+	service.IContainerService
+	apply func(id uint, status string) error
+
+	mu       sync.Mutex
+	attempts map[uint]int
+	history  map[uint][]string
+}
+
+func newFakeService(apply func(id uint, status string) error) *fakeService {
+	return &fakeService{apply: apply, attempts: make(map[uint]int), history: make(map[uint][]string)}
+}
+
+func (s *fakeService) ApplyContainerStatus(ctx context.Context, id uint, status string, at time.Time, origin dto.StatusOrigin) (bool, error) {
+	s.mu.Lock()
+	s.attempts[id]++
+	s.mu.Unlock()
+	if s.apply != nil {
+		if err := s.apply(id, status); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (s *fakeService) AddContainerStatus(ctx context.Context, id uint, status string, timestamp time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history[id] = append(s.history[id], status)
+	return nil
+}
+
+func statusMessage(partition int, offset int64, id uint, status string) kafka.Message {
+	value, _ := json.Marshal(map[string]interface{}{
+		"schema_version": dto.StatusMessageSchemaVersion,
+		"id":             id,
+		"status":         status,
+		"event_time":     time.Unix(1700000000+offset, 0).UTC(),
+	})
+	return kafka.Message{Topic: "status", Partition: partition, Offset: offset, Key: []byte(strconv.Itoa(int(id))), Value: value}
+}
+
+func testOptions() ConsumerOptions {
+	return ConsumerOptions{
+		Workers:         4,
+		MaxRetries:      2,
+		InitialBackoff:  time.Millisecond,
+		MaxBackoff:      2 * time.Millisecond,
+		DeadLetterTopic: "status.dlq",
+	}
+}
+
+// consumeAll runs the consumer until every message was committed on
+// partition 0 up to lastOffset, then stops it.
+func consumeAll(t *testing.T, h *KafkaConsumerHandler, reader *fakeReader, lastOffset int64) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- h.StartConsume(ctx) }()
+
+	deadline := time.After(5 * time.Second)
+	for reader.committed(0) < lastOffset {
+		select {
+		case <-deadline:
+			cancel()
+			t.Fatalf("committed offset %d, want %d", reader.committed(0), lastOffset)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("StartConsume() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestConsumerKeepsOrderPerContainer(t *testing.T) {
+	statuses := []string{"running", "paused", "running", "stopped"}
+	var messages []kafka.Message
+	offset := int64(0)
+	for round := range statuses {
+		for id := uint(1); id <= 8; id++ {
+			messages = append(messages, statusMessage(0, offset, id, statuses[round]))
+			offset++
+		}
+	}
+
+	// Containers take different times, so workers finish out of order.
+	svc := newFakeService(func(id uint, status string) error {
+		time.Sleep(time.Duration(id%3) * time.Millisecond)
+		return nil
+	})
+	reader := newFakeReader(messages...)
+	h := NewKafkaConsumerHandler(svc, nopLogger{}, reader, &fakeWriter{}, testOptions())
+	consumeAll(t, h, reader, offset-1)
+
+	for id := uint(1); id <= 8; id++ {
+		if got := fmt.Sprint(svc.history[id]); got != fmt.Sprint(statuses) {
+			t.Errorf("container %d statuses = %s, want %v", id, got, statuses)
+		}
+	}
+}
+
+func TestConsumerRetriesAndDeadLetters(t *testing.T) {
+	tests := []struct {
+		name         string
+		apply        func(attempt int) error
+		value        []byte
+		dlqFailures  int
+		wantAttempts int
+		wantDLQ      string
+	}{
+		{
+			name: "succeeds after retries",
+			apply: func(attempt int) error {
+				if attempt < 3 {
+					return errors.New("database unavailable")
+				}
+				return nil
+			},
+			wantAttempts: 3,
+		},
+		{
+			name:         "retries exhausted",
+			apply:        func(int) error { return errors.New("database unavailable") },
+			wantAttempts: 3,
+			wantDLQ:      "3",
+		},
+		{
+			name:         "quota error is not retried",
+			apply:        func(int) error { return fmt.Errorf("start: %w", service.ErrQuotaExceeded) },
+			wantAttempts: 1,
+			wantDLQ:      "1",
+		},
+		{
+			name:         "malformed payload is not processed",
+			value:        []byte(`{"id":`),
+			wantAttempts: 0,
+			wantDLQ:      "0",
+		},
+		{
+			name:         "dead-letter write is retried",
+			apply:        func(int) error { return fmt.Errorf("start: %w", service.ErrQuotaExceeded) },
+			dlqFailures:  2,
+			wantAttempts: 1,
+			wantDLQ:      "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			svc := newFakeService(func(uint, string) error {
+				attempts++
+				return tt.apply(attempts)
+			})
+			msg := statusMessage(0, 0, 7, "running")
+			msg.Headers = []kafka.Header{{Key: "trace", Value: []byte("abc")}}
+			if tt.value != nil {
+				msg.Value = tt.value
+			}
+			reader := newFakeReader(msg)
+			dlq := &fakeWriter{failures: tt.dlqFailures}
+			h := NewKafkaConsumerHandler(svc, nopLogger{}, reader, dlq, testOptions())
+			consumeAll(t, h, reader, 0)
+
+			if svc.attempts[7] != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", svc.attempts[7], tt.wantAttempts)
+			}
+			if tt.wantDLQ == "" {
+				if len(dlq.messages) != 0 {
+					t.Errorf("dead-lettered %d messages, want none", len(dlq.messages))
+				}
+				return
+			}
+			if len(dlq.messages) != 1 {
+				t.Fatalf("dead-lettered %d messages, want 1", len(dlq.messages))
+			}
+
+			dead := dlq.messages[0]
+			if dead.Topic != "status.dlq" || string(dead.Value) != string(msg.Value) {
+				t.Errorf("dead letter = %s on %q, want the original payload on status.dlq", dead.Value, dead.Topic)
+			}
+			headers := make(map[string]string)
+			for _, header := range dead.Headers {
+				headers[header.Key] = string(header.Value)
+			}
+			want := map[string]string{
+				"trace":                  "abc",
+				"dlq_original_topic":     "status",
+				"dlq_original_partition": "0",
+				"dlq_original_offset":    "0",
+				"dlq_attempts":           tt.wantDLQ,
+			}
+			for key, value := range want {
+				if headers[key] != value {
+					t.Errorf("header %s = %q, want %q", key, headers[key], value)
+				}
+			}
+			if headers["dlq_error"] == "" || headers["dlq_failed_at"] == "" {
+				t.Errorf("headers = %v, want dlq_error and dlq_failed_at", headers)
+			}
+		})
+	}
+}
+
+func TestConsumerDrainsOnShutdown(t *testing.T) {
+	var messages []kafka.Message
+	for offset := int64(0); offset < 6; offset++ {
+		messages = append(messages, statusMessage(0, offset, uint(offset%2+1), "running"))
+	}
+	svc := newFakeService(func(uint, string) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+	reader := newFakeReader(messages...)
+	h := NewKafkaConsumerHandler(svc, nopLogger{}, reader, &fakeWriter{}, testOptions())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- h.StartConsume(ctx) }()
+	// Everything is fetched but most of it still queued.
+	<-reader.idle
+	cancel()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("StartConsume() error = %v, want context.Canceled", err)
+	}
+	if got := len(svc.history[1]) + len(svc.history[2]); got != len(messages) {
+		t.Errorf("processed %d messages before returning, want %d", got, len(messages))
+	}
+	if got := reader.committed(0); got != 5 {
+		t.Errorf("committed offset = %d, want 5", got)
+	}
+}
+
+func TestMarkDoneCommitsContiguousPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		fetched []int64
+		done    []int64
+		want    []int64
+	}{
+		{"in order", []int64{0, 1, 2}, []int64{0, 1, 2}, []int64{0, 1, 2}},
+		{"out of order", []int64{0, 1, 2}, []int64{2, 1, 0}, []int64{2}},
+		{"gap holds back", []int64{0, 1, 2, 3}, []int64{0, 2, 3}, []int64{0}},
+		{"gap filled", []int64{0, 1, 2, 3}, []int64{0, 2, 3, 1}, []int64{0, 3}},
+		{"reassigned partition restarts", []int64{5, 6, 3, 4}, []int64{6, 3, 5, 4}, []int64{3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := newFakeReader()
+			h := NewKafkaConsumerHandler(newFakeService(nil), nopLogger{}, reader, nil, testOptions())
+			for _, offset := range tt.fetched {
+				h.track(kafka.Message{Topic: "status", Partition: 0, Offset: offset})
+			}
+			for _, offset := range tt.done {
+				h.markDone(context.Background(), kafka.Message{Topic: "status", Partition: 0, Offset: offset})
+			}
+
+			var got []int64
+			for _, msg := range reader.commits {
+				got = append(got, msg.Offset)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("commits = %v, want %v", got, tt.want)
+			}
+			if len(h.partitions) != 0 && len(tt.done) == len(tt.fetched) {
+				t.Errorf("partitions = %v, want none left once everything is committed", h.partitions)
+			}
+		})
+	}
+}
+
+func TestDecodeStatusMessage(t *testing.T) {
+	eventTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name       string
+		value      string
+		wantStatus string
+		poison     bool
+	}{
+		{"v1", `{"schema_version":1,"id":3,"status":"paused","event_time":"2026-01-02T03:04:05Z"}`, "paused", false},
+		{"legacy running", `{"id":3,"status":true}`, "running", false},
+		{"legacy stopped", `{"id":3,"status":false}`, "stopped", false},
+		{"malformed", `{"id":`, "", true},
+		{"no id", `{"schema_version":1,"status":"paused","event_time":"2026-01-02T03:04:05Z"}`, "", true},
+		{"missing is not reportable", `{"schema_version":1,"id":3,"status":"missing","event_time":"2026-01-02T03:04:05Z"}`, "", true},
+		{"no event time", `{"schema_version":1,"id":3,"status":"paused"}`, "", true},
+		{"unknown version", `{"schema_version":9,"id":3,"status":"paused"}`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := decodeStatusMessage(kafka.Message{Value: []byte(tt.value), Time: eventTime})
+			if tt.poison {
+				if !errors.Is(err, errPoisonMessage) {
+					t.Errorf("decodeStatusMessage() error = %v, want a poison message", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeStatusMessage() error = %v", err)
+			}
+			if msg.ID != 3 || msg.Status != tt.wantStatus || !msg.EventTime.Equal(eventTime) {
+				t.Errorf("message = %+v, want id 3 %s at %v", msg, tt.wantStatus, eventTime)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const DefaultAuditLimit = 100 // Default number of audit events per page
+
+type RestAuditHandler struct {
+	service service.IAuditService
+	logger  logger.ILogger
+}
+
+func NewRestAuditHandler(service service.IAuditService, logger logger.ILogger) *RestAuditHandler {
+	return &RestAuditHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *RestAuditHandler) respondWithError(c *gin.Context, statusCode int, message string, err error) {
+	if err != nil {
+		h.logger.Error(message, "error", err)
+	} else {
+		h.logger.Error(message)
+	}
+	c.JSON(statusCode, gin.H{
+		"error": message,
+	})
+}
+
+// GetAuditEvents lists audit events, newest first. With format=xlsx or
+// format=csv the matching events are returned as a file instead.
+func (h *RestAuditHandler) GetAuditEvents(c *gin.Context) {
+	var filter dto.AuditFilter
+	var err error
+
+	if containerID := c.Query("container_id"); containerID != "" {
+		id, err := strconv.ParseUint(containerID, 10, 64)
+		if err != nil {
+			h.respondWithError(c, http.StatusBadRequest, "Invalid 'container_id' parameter", err)
+			return
+		}
+		filter.ContainerID = uint(id)
+	}
+
+	filter.Actor = c.Query("actor")
+
+	if from := c.Query("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			h.respondWithError(c, http.StatusBadRequest, "Invalid 'from' parameter, expected RFC3339", err)
+			return
+		}
+	}
+
+	if to := c.Query("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			h.respondWithError(c, http.StatusBadRequest, "Invalid 'to' parameter, expected RFC3339", err)
+			return
+		}
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		h.respondWithError(c, http.StatusBadRequest, "'from' must not be after 'to'", nil)
+		return
+	}
+
+	if format := c.Query("format"); format != "" {
+		if format != service.AuditExportXLSX && format != service.AuditExportCSV {
+			h.respondWithError(c, http.StatusBadRequest, "Invalid 'format' parameter", nil)
+			return
+		}
+
+		exportData, err := h.service.ExportAuditEvents(c, &filter, format)
+		if err != nil {
+			h.respondWithError(c, http.StatusInternalServerError, "Failed to export audit events", err)
+			return
+		}
+
+		c.Header("Access-Control-Expose-Headers", "Content-Disposition")
+		c.Header("Content-Disposition", "attachment; filename="+exportData.FileName)
+		c.Header("File-Name", exportData.FileName)
+		c.Data(http.StatusOK, exportData.ContentType, exportData.Data)
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid 'offset' parameter", err)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(DefaultAuditLimit)))
+	if err != nil || limit <= 0 || limit > MaxRangeLimit {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid 'limit' parameter", err)
+		return
+	}
+
+	total, events, err := h.service.ListAuditEvents(c, &filter, offset, limit)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to retrieve audit events", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"count":  total,
+		"events": events,
+	})
+}
@@ -0,0 +1,87 @@
+package rest
+
+import (
+	"net/http"
+	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/pkg/authz"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RestAuthzHandler struct {
+	service service.IAuthzService
+	logger  logger.ILogger
+}
+
+func NewRestAuthzHandler(service service.IAuthzService, logger logger.ILogger) *RestAuthzHandler {
+	return &RestAuthzHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *RestAuthzHandler) respondWithError(c *gin.Context, statusCode int, message string, err error) {
+	if err != nil {
+		h.logger.Error(message, "error", err)
+	} else {
+		h.logger.Error(message)
+	}
+	c.JSON(statusCode, gin.H{
+		"error": message,
+	})
+}
+
+type authzCheckRequest struct {
+	Action      string         `json:"action" binding:"required"`
+	ContainerID uint           `json:"container_id"`
+	Subject     *authz.Subject `json:"subject"`
+}
+
+type authzCheckResponse struct {
+	Subject     authz.Subject `json:"subject"`
+	Action      string        `json:"action"`
+	ContainerID uint          `json:"container_id,omitempty"`
+	authz.Decision
+}
+
+// CheckAuthorization evaluates the policy without performing the action.
+// Callers check their own token; admins may pass any subject instead. The
+// container is loaded with the caller's visibility, so a container the
+// caller cannot see is evaluated as absent.
+func (h *RestAuthzHandler) CheckAuthorization(c *gin.Context) {
+	claims, ok := utils.ClaimsFromContext(c)
+	if !ok {
+		h.respondWithError(c, http.StatusUnauthorized, "Unauthorized", nil)
+		return
+	}
+
+	var req authzCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	subject := service.SubjectFromClaims(claims)
+	if req.Subject != nil {
+		if !utils.IsAdmin(claims) {
+			h.respondWithError(c, http.StatusForbidden, "Only admins may check another subject", nil)
+			return
+		}
+		subject = *req.Subject
+	}
+
+	decision, err := h.service.Authorize(c, subject, req.Action, req.ContainerID)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to evaluate authorization policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, authzCheckResponse{
+		Subject:     subject,
+		Action:      req.Action,
+		ContainerID: req.ContainerID,
+		Decision:    *decision,
+	})
+}
@@ -0,0 +1,250 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	ExecStreamStdout = 1 // First byte of output messages carrying stdout
+	ExecStreamStderr = 2 // First byte of output messages carrying stderr
+
+	ExecPingInterval   = 30 * time.Second // Interval of WebSocket pings
+	ExecWriteTimeout   = 10 * time.Second // Deadline of a single WebSocket write
+	ExecMaxMessageSize = 64 * 1024        // Largest message accepted from the client
+)
+
+var DefaultExecCommand = []string{"/bin/sh"}
+
+// execUpgrader keeps gorilla's default origin check, so browsers can only
+// open sessions from pages served by this service.
+var execUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+}
+
+// execControl is a text message of the exec protocol.
+type execControl struct {
+	Type     string `json:"type"`
+	Width    uint   `json:"width,omitempty"`
+	Height   uint   `json:"height,omitempty"`
+	ExitCode *int   `json:"exit_code,omitempty"`
+	Duration int64  `json:"duration_ms,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ExecContainer runs a command in a container over a WebSocket. The command
+// is given as repeated cmd parameters and defaults to /bin/sh.
+//
+// The client sends stdin as binary messages and control messages as JSON
+// text: {"type":"resize","width":80,"height":24} resizes the terminal and
+// {"type":"eof"} closes stdin. The server sends output as binary messages
+// whose first byte is 1 for stdout or 2 for stderr, and ends the session
+// with {"type":"exit","exit_code":0,"duration_ms":1234} or
+// {"type":"error","error":"..."} before closing the connection.
+func (h *RestContainerHandler) ExecContainer(c *gin.Context) {
+	idUint, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid ID format", err)
+		return
+	}
+
+	opts, err := parseExecOptions(c)
+	if err != nil {
+		h.respondWithError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	// The upgrader writes its own error response.
+	ws, err := execUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		h.logger.Warn("Failed to upgrade exec connection", "id", idUint, "error", err)
+		return
+	}
+	conn := &execConn{ws: ws}
+	defer ws.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	stdinReader, stdinWriter := io.Pipe()
+	defer stdinReader.Close()
+	resize := make(chan dto.ExecResize, 1)
+
+	go h.readExecInput(ws, cancel, stdinWriter, resize)
+	go conn.keepAlive(ctx)
+
+	result, err := h.service.ExecContainer(ctx, uint(idUint), opts, dto.ExecIO{
+		Stdin:  stdinReader,
+		Stdout: execStreamWriter{conn: conn, stream: ExecStreamStdout},
+		Stderr: execStreamWriter{conn: conn, stream: ExecStreamStderr},
+		Resize: resize,
+	})
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err != nil {
+		h.logger.Error("Exec session failed", "id", idUint, "error", err)
+		message := "Failed to exec in container"
+		if errors.Is(err, service.ErrContainerNotRunning) || errors.Is(err, service.ErrInvalidExecCommand) {
+			message = err.Error()
+		}
+		conn.writeControl(execControl{Type: "error", Error: message})
+		conn.close(websocket.CloseInternalServerErr, message)
+		return
+	}
+
+	exitCode := result.ExitCode
+	conn.writeControl(execControl{Type: "exit", ExitCode: &exitCode, Duration: result.Duration.Milliseconds()})
+	conn.close(websocket.CloseNormalClosure, "")
+}
+
+// readExecInput forwards the client's messages to the session until the
+// connection is closed, which also ends the session.
+func (h *RestContainerHandler) readExecInput(ws *websocket.Conn, cancel context.CancelFunc, stdin *io.PipeWriter, resize chan dto.ExecResize) {
+	defer cancel()
+	defer stdin.Close()
+
+	ws.SetReadLimit(ExecMaxMessageSize)
+	ws.SetReadDeadline(time.Now().Add(2 * ExecPingInterval))
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(2 * ExecPingInterval))
+	})
+
+	for {
+		messageType, data, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		ws.SetReadDeadline(time.Now().Add(2 * ExecPingInterval))
+
+		switch messageType {
+		case websocket.BinaryMessage:
+			if _, err := stdin.Write(data); err != nil {
+				// stdin was closed by an eof message or the session ended.
+				continue
+			}
+		case websocket.TextMessage:
+			var control execControl
+			if err := json.Unmarshal(data, &control); err != nil {
+				h.logger.Warn("Ignoring invalid exec control message", "error", err)
+				continue
+			}
+			switch control.Type {
+			case "resize":
+				if control.Width == 0 || control.Height == 0 {
+					continue
+				}
+				// Only the latest size matters, so a pending one is replaced.
+				select {
+				case <-resize:
+				default:
+				}
+				resize <- dto.ExecResize{Width: control.Width, Height: control.Height}
+			case "eof":
+				stdin.Close()
+			default:
+				h.logger.Warn("Ignoring unknown exec control message", "type", control.Type)
+			}
+		}
+	}
+}
+
+func parseExecOptions(c *gin.Context) (dto.ExecOptions, error) {
+	opts := dto.ExecOptions{Cmd: c.QueryArray("cmd")}
+	if len(opts.Cmd) == 0 {
+		opts.Cmd = DefaultExecCommand
+	}
+
+	var err error
+	if raw := c.Query("tty"); raw != "" {
+		if opts.Tty, err = strconv.ParseBool(raw); err != nil {
+			return opts, fmt.Errorf("Invalid 'tty' parameter, expected a boolean")
+		}
+	}
+	for name, target := range map[string]*uint{
+		"width":  &opts.Width,
+		"height": &opts.Height,
+	} {
+		if raw := c.Query(name); raw != "" {
+			value, err := strconv.ParseUint(raw, 10, 16)
+			if err != nil {
+				return opts, fmt.Errorf("Invalid '%s' parameter, expected a number of cells", name)
+			}
+			*target = uint(value)
+		}
+	}
+
+	return opts, nil
+}
+
+// execConn serializes writes, which gorilla/websocket requires.
+type execConn struct {
+	ws *websocket.Conn
+	mu sync.Mutex
+}
+
+func (c *execConn) write(messageType int, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ws.SetWriteDeadline(time.Now().Add(ExecWriteTimeout))
+	return c.ws.WriteMessage(messageType, data)
+}
+
+func (c *execConn) writeControl(control execControl) {
+	data, err := json.Marshal(control)
+	if err != nil {
+		return
+	}
+	c.write(websocket.TextMessage, data)
+}
+
+func (c *execConn) close(code int, text string) {
+	c.write(websocket.CloseMessage, websocket.FormatCloseMessage(code, text))
+}
+
+func (c *execConn) keepAlive(ctx context.Context) {
+	ticker := time.NewTicker(ExecPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.write(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// execStreamWriter sends output of one stream as binary messages tagged with
+// the stream.
+type execStreamWriter struct {
+	conn   *execConn
+	stream byte
+}
+
+func (w execStreamWriter) Write(p []byte) (int, error) {
+	message := make([]byte, 0, len(p)+1)
+	message = append(message, w.stream)
+	message = append(message, p...)
+	if err := w.conn.write(websocket.BinaryMessage, message); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
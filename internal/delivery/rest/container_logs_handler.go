@@ -0,0 +1,172 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultLogTail       = 100              // Default number of log lines from the end
+	MaxLogLines          = 10000            // Maximum number of log lines returned without follow
+	LogHeartbeatInterval = 15 * time.Second // Interval of keep-alive events while following
+)
+
+// GetContainerLogs returns the log of a container. Without follow the lines
+// are returned as JSON; with follow=true they are streamed as Server-Sent
+// Events until the client disconnects or the container stops:
+//
+//	event: log
+//	data: {"stream":"stdout","timestamp":"...","text":"..."}
+//
+// The stream ends with an "end" event, or an "error" event when reading the
+// log failed.
+func (h *RestContainerHandler) GetContainerLogs(c *gin.Context) {
+	idUint, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid ID format", err)
+		return
+	}
+
+	opts, err := parseLogOptions(c, time.Now().UTC())
+	if err != nil {
+		h.respondWithError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if !opts.Follow && (opts.Tail < 0 || opts.Tail > MaxLogLines) {
+		// Only the newest MaxLogLines are returned; the extra line tells
+		// whether older ones were left out.
+		opts.Tail = MaxLogLines + 1
+	}
+
+	lines, errs, err := h.service.StreamContainerLogs(c.Request.Context(), uint(idUint), opts)
+	if errors.Is(err, service.ErrContainerNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Container not found", err)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to read container logs", err)
+		return
+	}
+
+	if opts.Follow {
+		h.streamLogs(c, lines, errs)
+		return
+	}
+
+	// Long lines are split, so the log can still exceed MaxLogLines; keep
+	// the newest.
+	collected := make([]dto.ContainerLogLine, 0)
+	truncated := false
+	for line := range lines {
+		if len(collected) == MaxLogLines {
+			collected = append(collected[1:], line)
+			truncated = true
+			continue
+		}
+		collected = append(collected, line)
+	}
+	if err := <-errs; err != nil && !errors.Is(err, io.EOF) {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to read container logs", err)
+		return
+	}
+
+	h.respondWithSuccess(c, http.StatusOK, gin.H{
+		"id":        idUint,
+		"lines":     collected,
+		"count":     len(collected),
+		"truncated": truncated,
+	})
+}
+
+func (h *RestContainerHandler) streamLogs(c *gin.Context, lines <-chan dto.ContainerLogLine, errs <-chan error) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Keep reverse proxies from buffering the stream.
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(LogHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				if err := <-errs; err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, context.Canceled) {
+					h.logger.Error("Failed to stream container logs", "error", err)
+					c.SSEvent("error", gin.H{"error": "Failed to read container logs"})
+				} else {
+					c.SSEvent("end", gin.H{})
+				}
+				return false
+			}
+			c.SSEvent("log", line)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", gin.H{})
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// parseLogOptions reads the log query parameters. since and until accept
+// RFC3339 times or durations such as 10m, counted back from now.
+func parseLogOptions(c *gin.Context, now time.Time) (dto.ContainerLogOptions, error) {
+	opts := dto.ContainerLogOptions{Tail: DefaultLogTail}
+
+	var err error
+	for name, target := range map[string]*bool{
+		"follow":     &opts.Follow,
+		"timestamps": &opts.Timestamps,
+		"stdout":     &opts.Stdout,
+		"stderr":     &opts.Stderr,
+	} {
+		if raw := c.Query(name); raw != "" {
+			if *target, err = strconv.ParseBool(raw); err != nil {
+				return opts, fmt.Errorf("Invalid '%s' parameter, expected a boolean", name)
+			}
+		}
+	}
+
+	if raw := c.Query("tail"); raw == "all" {
+		opts.Tail = -1
+	} else if raw != "" {
+		if opts.Tail, err = strconv.Atoi(raw); err != nil || opts.Tail < 0 {
+			return opts, fmt.Errorf("Invalid 'tail' parameter, expected a number of lines or 'all'")
+		}
+	}
+
+	if opts.Since, err = parseLogTime(c.Query("since"), now); err != nil {
+		return opts, fmt.Errorf("Invalid 'since' parameter, expected RFC3339 or a duration")
+	}
+	if opts.Until, err = parseLogTime(c.Query("until"), now); err != nil {
+		return opts, fmt.Errorf("Invalid 'until' parameter, expected RFC3339 or a duration")
+	}
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && !opts.Since.Before(opts.Until) {
+		return opts, fmt.Errorf("'since' must be before 'until'")
+	}
+
+	return opts, nil
+}
+
+func parseLogTime(raw string, now time.Time) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
@@ -0,0 +1,39 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"thanhnt208/container-adm-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetContainerStats returns a snapshot of the CPU, memory, network and block
+// IO usage of a running container.
+func (h *RestContainerHandler) GetContainerStats(c *gin.Context) {
+	idUint, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid ID format", err)
+		return
+	}
+
+	stats, err := h.service.GetContainerStats(c.Request.Context(), uint(idUint))
+	if err != nil {
+		if errors.Is(err, service.ErrContainerNotFound) {
+			h.respondWithError(c, http.StatusNotFound, "Container not found", err)
+			return
+		}
+		if errors.Is(err, service.ErrContainerNotRunning) {
+			h.respondWithError(c, http.StatusConflict, err.Error(), err)
+			return
+		}
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to get container stats", err)
+		return
+	}
+
+	h.respondWithSuccess(c, http.StatusOK, gin.H{
+		"id":    idUint,
+		"stats": stats,
+	})
+}
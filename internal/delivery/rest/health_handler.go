@@ -0,0 +1,32 @@
+package rest
+
+import (
+	"net/http"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RestHealthHandler struct {
+	service service.IHealthService
+}
+
+func NewRestHealthHandler(service service.IHealthService) *RestHealthHandler {
+	return &RestHealthHandler{
+		service: service,
+	}
+}
+
+func (h *RestHealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.Liveness())
+}
+
+func (h *RestHealthHandler) Readyz(c *gin.Context) {
+	report := h.service.Readiness(c.Request.Context())
+	if report.Status != dto.HealthStatusUp {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,151 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const JobHeartbeatInterval = 15 * time.Second // Interval of keep-alive events while watching a job
+
+type RestJobHandler struct {
+	service service.IJobService
+	logger  logger.ILogger
+}
+
+func NewRestJobHandler(service service.IJobService, logger logger.ILogger) *RestJobHandler {
+	return &RestJobHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *RestJobHandler) respondWithError(c *gin.Context, statusCode int, message string, err error) {
+	if err != nil {
+		h.logger.Error(message, "error", err)
+	} else {
+		h.logger.Error(message)
+	}
+	c.JSON(statusCode, gin.H{
+		"error": message,
+	})
+}
+
+func (h *RestJobHandler) GetJob(c *gin.Context) {
+	id, ok := h.jobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.GetJob(c, id)
+	if errors.Is(err, service.ErrJobNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Job not found", nil)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to retrieve job", err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// WatchJob streams the job as Server-Sent Events. Every change is sent as a
+// "progress" event carrying the job; the stream ends with an "end" event
+// once the job finished, or an "error" event when it could not be read.
+func (h *RestJobHandler) WatchJob(c *gin.Context) {
+	id, ok := h.jobID(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c)
+	defer cancel()
+
+	jobs, errs, err := h.service.WatchJob(ctx, id)
+	if errors.Is(err, service.ErrJobNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Job not found", nil)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to retrieve job", err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Keep reverse proxies from buffering the stream.
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(JobHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	var last model.Job
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case job, ok := <-jobs:
+			if !ok {
+				if err := <-errs; err != nil && !errors.Is(err, context.Canceled) {
+					h.logger.Error("Failed to watch job", "id", id, "error", err)
+					c.SSEvent("error", gin.H{"error": "Failed to retrieve job"})
+				} else {
+					c.SSEvent("end", gin.H{"id": last.ID, "status": last.Status})
+				}
+				return false
+			}
+			last = job
+			c.SSEvent("progress", job)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", gin.H{})
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// CancelJob cancels a queued job, or asks the worker of a running job to
+// stop it; the job reports cancelled once it did.
+func (h *RestJobHandler) CancelJob(c *gin.Context) {
+	id, ok := h.jobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.service.CancelJob(c, id)
+	if errors.Is(err, service.ErrJobNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Job not found", nil)
+		return
+	}
+	if errors.Is(err, service.ErrJobFinished) {
+		h.respondWithError(c, http.StatusConflict, "Job already finished with status "+job.Status, err)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to cancel job", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Job cancellation requested",
+		"job":     job,
+	})
+}
+
+func (h *RestJobHandler) jobID(c *gin.Context) (string, bool) {
+	id := c.Param("job_id")
+	if _, err := uuid.Parse(id); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid job ID format", err)
+		return "", false
+	}
+	return id, true
+}
@@ -0,0 +1,169 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RestQuotaHandler struct {
+	service service.IQuotaService
+	logger  logger.ILogger
+}
+
+func NewRestQuotaHandler(service service.IQuotaService, logger logger.ILogger) *RestQuotaHandler {
+	return &RestQuotaHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *RestQuotaHandler) respondWithError(c *gin.Context, statusCode int, message string, err error) {
+	if err != nil {
+		h.logger.Error(message, "error", err)
+	} else {
+		h.logger.Error(message)
+	}
+	c.JSON(statusCode, gin.H{
+		"error": message,
+	})
+}
+
+// GetMyQuota returns the quota that applies to the caller and the caller's
+// current usage. A null quota means no limits apply.
+func (h *RestQuotaHandler) GetMyQuota(c *gin.Context) {
+	status, err := h.service.GetMyQuota(c)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to retrieve quota", err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+func (h *RestQuotaHandler) ListQuotas(c *gin.Context) {
+	quotas, err := h.service.ListQuotas(c)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to retrieve quotas", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"count":  len(quotas),
+		"quotas": quotas,
+	})
+}
+
+func (h *RestQuotaHandler) GetQuota(c *gin.Context) {
+	id, ok := h.quotaID(c)
+	if !ok {
+		return
+	}
+
+	quota, err := h.service.GetQuota(c, id)
+	if errors.Is(err, service.ErrQuotaNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Quota not found", nil)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to retrieve quota", err)
+		return
+	}
+	c.JSON(http.StatusOK, quota)
+}
+
+func (h *RestQuotaHandler) CreateQuota(c *gin.Context) {
+	var req dto.QuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid quota data", err)
+		return
+	}
+	if req.SubjectType == "" || strings.TrimSpace(req.Subject) == "" {
+		h.respondWithError(c, http.StatusBadRequest, "subject_type and subject are required", nil)
+		return
+	}
+	if req.SubjectType == model.QuotaSubjectUser {
+		if _, err := strconv.ParseUint(req.Subject, 10, 64); err != nil {
+			h.respondWithError(c, http.StatusBadRequest, "subject of a user quota must be a user ID", err)
+			return
+		}
+	}
+
+	quota := quotaFromRequest(&req)
+	quota.SubjectType = req.SubjectType
+	quota.Subject = strings.TrimSpace(req.Subject)
+
+	if err := h.service.CreateQuota(c, quota); err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to create quota", err)
+		return
+	}
+	c.JSON(http.StatusCreated, quota)
+}
+
+func (h *RestQuotaHandler) UpdateQuota(c *gin.Context) {
+	id, ok := h.quotaID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.QuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid quota data", err)
+		return
+	}
+
+	quota, err := h.service.UpdateQuota(c, id, quotaFromRequest(&req))
+	if errors.Is(err, service.ErrQuotaNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Quota not found", nil)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to update quota", err)
+		return
+	}
+	c.JSON(http.StatusOK, quota)
+}
+
+func (h *RestQuotaHandler) DeleteQuota(c *gin.Context) {
+	id, ok := h.quotaID(c)
+	if !ok {
+		return
+	}
+
+	err := h.service.DeleteQuota(c, id)
+	if errors.Is(err, service.ErrQuotaNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Quota not found", nil)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to delete quota", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Quota deleted successfully",
+		"id":      id,
+	})
+}
+
+func (h *RestQuotaHandler) quotaID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid ID format", err)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func quotaFromRequest(req *dto.QuotaRequest) *model.Quota {
+	return &model.Quota{
+		MaxContainers:  req.MaxContainers,
+		MaxRunning:     req.MaxRunning,
+		MaxCPUShares:   req.MaxCPUShares,
+		MaxMemoryBytes: req.MaxMemoryBytes,
+	}
+}
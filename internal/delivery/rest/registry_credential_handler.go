@@ -0,0 +1,99 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RestRegistryCredentialHandler struct {
+	service service.IRegistryCredentialService
+	logger  logger.ILogger
+}
+
+func NewRestRegistryCredentialHandler(service service.IRegistryCredentialService, logger logger.ILogger) *RestRegistryCredentialHandler {
+	return &RestRegistryCredentialHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *RestRegistryCredentialHandler) respondWithError(c *gin.Context, statusCode int, message string, err error) {
+	if err != nil {
+		h.logger.Error(message, "error", err)
+	} else {
+		h.logger.Error(message)
+	}
+	c.JSON(statusCode, gin.H{
+		"error": message,
+	})
+}
+
+// ListRegistryCredentials lists the registries with stored credentials and
+// their usernames. Passwords are never returned.
+func (h *RestRegistryCredentialHandler) ListRegistryCredentials(c *gin.Context) {
+	credentials, err := h.service.ListRegistryCredentials(c)
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to retrieve registry credentials", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"count":       len(credentials),
+		"credentials": credentials,
+	})
+}
+
+type registryCredentialRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// SetRegistryCredential stores the login for the registry host in the path,
+// replacing any previous one.
+func (h *RestRegistryCredentialHandler) SetRegistryCredential(c *gin.Context) {
+	registry := strings.TrimSpace(c.Param("registry"))
+	if registry == "" {
+		h.respondWithError(c, http.StatusBadRequest, "Registry is required", nil)
+		return
+	}
+
+	var req registryCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		// The binding error never includes field values, only their names.
+		h.respondWithError(c, http.StatusBadRequest, "username and password are required", err)
+		return
+	}
+
+	credential, err := h.service.SetRegistryCredential(c, registry, req.Username, req.Password)
+	if errors.Is(err, service.ErrRegistryCredentialsDisabled) {
+		h.respondWithError(c, http.StatusServiceUnavailable, "Registry credentials are not configured on this server", nil)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to store registry credential", err)
+		return
+	}
+	c.JSON(http.StatusOK, credential)
+}
+
+func (h *RestRegistryCredentialHandler) DeleteRegistryCredential(c *gin.Context) {
+	registry := c.Param("registry")
+
+	err := h.service.DeleteRegistryCredential(c, registry)
+	if errors.Is(err, service.ErrRegistryCredentialNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Registry credential not found", nil)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to delete registry credential", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Registry credential deleted successfully",
+		"registry": registry,
+	})
+}
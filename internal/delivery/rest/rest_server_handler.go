@@ -1,13 +1,16 @@
 package rest
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
 	"thanhnt208/container-adm-service/internal/service"
 	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,17 +19,22 @@ const (
 	MaxRangeLimit     = 1000             // Maximum range limit for pagination
 	MaxFileSize       = 10 * 1024 * 1024 // Maximum file size for import (10 MB)
 	SupportedFileType = ".xlsx"          // Supported file type for import
+
+	DefaultHistoryWindow = 24 * time.Hour // Default time window for status history
+	DefaultHistorySize   = 100            // Default number of status spans per page
 )
 
 type RestContainerHandler struct {
-	service service.IContainerService
-	logger  logger.ILogger
+	service    service.IContainerService
+	jobService service.IJobService
+	logger     logger.ILogger
 }
 
-func NewRestServerHandler(service service.IContainerService, logger logger.ILogger) *RestContainerHandler {
+func NewRestServerHandler(service service.IContainerService, jobService service.IJobService, logger logger.ILogger) *RestContainerHandler {
 	return &RestContainerHandler{
-		service: service,
-		logger:  logger,
+		service:    service,
+		jobService: jobService,
+		logger:     logger,
 	}
 }
 
@@ -45,6 +53,24 @@ func (h *RestContainerHandler) respondWithSuccess(c *gin.Context, statusCode int
 	c.JSON(statusCode, data)
 }
 
+// respondWithJob accepts a request that continues as a job and points the
+// client at it.
+func (h *RestContainerHandler) respondWithJob(c *gin.Context, message string, job *model.Job, data gin.H) {
+	location := "/jobs/" + job.ID
+	c.Header("Location", location)
+
+	body := gin.H{
+		"message":  message,
+		"job_id":   job.ID,
+		"status":   job.Status,
+		"location": location,
+	}
+	for key, value := range data {
+		body[key] = value
+	}
+	h.respondWithSuccess(c, http.StatusAccepted, body)
+}
+
 func (h *RestContainerHandler) CreateContainer(c *gin.Context) {
 	var req dto.CreateContainerRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -57,16 +83,28 @@ func (h *RestContainerHandler) CreateContainer(c *gin.Context) {
 		return
 	}
 
-	id, err := h.service.CreateContainer(c, req.ContainerName, req.ImageName)
+	if err := req.ContainerSpec.Validate(); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid container spec: "+err.Error(), err)
+		return
+	}
+
+	// Pulling the image can take longer than clients wait, so the container
+	// is created by a job.
+	job, err := h.jobService.SubmitCreateContainer(c, req)
+	if errors.Is(err, service.ErrQuotaExceeded) {
+		h.respondWithError(c, http.StatusForbidden, err.Error(), err)
+		return
+	}
+	if errors.Is(err, service.ErrImagePolicyViolation) {
+		h.respondWithError(c, http.StatusForbidden, err.Error(), err)
+		return
+	}
 	if err != nil {
 		h.respondWithError(c, http.StatusInternalServerError, "Failed to create container", err)
 		return
 	}
 
-	h.respondWithSuccess(c, http.StatusCreated, gin.H{
-		"message": "Container created successfully",
-		"id":      id,
-	})
+	h.respondWithJob(c, "Container creation accepted", job, nil)
 }
 
 func (h *RestContainerHandler) ViewContainers(c *gin.Context) {
@@ -137,7 +175,35 @@ func (h *RestContainerHandler) UpdateContainer(c *gin.Context) {
 		return
 	}
 
+	// A new image has to be pulled, so the update continues as a job.
+	if image, _ := updateReq["image_name"].(string); image != "" {
+		job, err := h.jobService.SubmitUpdateContainer(c, uint(idUint), updateReq)
+		if errors.Is(err, service.ErrQuotaExceeded) {
+			h.respondWithError(c, http.StatusForbidden, err.Error(), err)
+			return
+		}
+		if errors.Is(err, service.ErrImagePolicyViolation) {
+			h.respondWithError(c, http.StatusForbidden, err.Error(), err)
+			return
+		}
+		if err != nil {
+			h.respondWithError(c, http.StatusInternalServerError, "Failed to update container", err)
+			return
+		}
+
+		h.respondWithJob(c, "Container update accepted", job, gin.H{"id": idUint})
+		return
+	}
+
 	updatedData, err := h.service.UpdateContainer(c, uint(idUint), updateReq)
+	if errors.Is(err, service.ErrQuotaExceeded) {
+		h.respondWithError(c, http.StatusForbidden, err.Error(), err)
+		return
+	}
+	if errors.Is(err, service.ErrImagePolicyViolation) {
+		h.respondWithError(c, http.StatusForbidden, err.Error(), err)
+		return
+	}
 	if err != nil {
 		h.respondWithError(c, http.StatusInternalServerError, "Failed to update container", err)
 		return
@@ -150,6 +216,52 @@ func (h *RestContainerHandler) UpdateContainer(c *gin.Context) {
 	})
 }
 
+// ContainerAction returns the handler of one container action. The body is
+// optional; timeout applies to restart and signal to kill.
+func (h *RestContainerHandler) ContainerAction(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idUint, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			h.respondWithError(c, http.StatusBadRequest, "Invalid ID format", err)
+			return
+		}
+
+		var req dto.ContainerActionRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			h.respondWithError(c, http.StatusBadRequest, "Invalid request data", err)
+			return
+		}
+
+		container, err := h.service.RunContainerAction(c, uint(idUint), action, req)
+		if errors.Is(err, service.ErrContainerNotFound) {
+			h.respondWithError(c, http.StatusNotFound, "Container not found", nil)
+			return
+		}
+		if errors.Is(err, service.ErrInvalidContainerAction) {
+			h.respondWithError(c, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		if errors.Is(err, service.ErrContainerStateConflict) {
+			h.respondWithError(c, http.StatusConflict, err.Error(), err)
+			return
+		}
+		if errors.Is(err, service.ErrQuotaExceeded) {
+			h.respondWithError(c, http.StatusForbidden, err.Error(), err)
+			return
+		}
+		if err != nil {
+			h.respondWithError(c, http.StatusInternalServerError, "Failed to "+action+" container", err)
+			return
+		}
+
+		h.respondWithSuccess(c, http.StatusOK, gin.H{
+			"message": "Container " + action + " completed",
+			"id":      idUint,
+			"data":    container,
+		})
+	}
+}
+
 func (h *RestContainerHandler) DeleteContainer(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
@@ -209,25 +321,13 @@ func (h *RestContainerHandler) ImportContainers(c *gin.Context) {
 		return
 	}
 
-	var importResult *dto.ImportResult
-	importResult, err = h.service.ImportContainers(c, buf)
+	job, err := h.jobService.SubmitImportContainers(c, buf)
 	if err != nil {
 		h.respondWithError(c, http.StatusInternalServerError, "Failed to import containers", err)
 		return
 	}
-	if importResult == nil {
-		h.respondWithError(c, http.StatusInternalServerError, "Import result is nil", nil)
-		return
-	}
 
-	h.respondWithSuccess(c, http.StatusOK, gin.H{
-		"message":          "Containers imported successfully",
-		"file_name":        file.Filename,
-		"successful_count": importResult.SuccessfulCount,
-		"successful_items": importResult.SuccessfulItems,
-		"failed_count":     importResult.FailedCount,
-		"failed_items":     importResult.FailedItems,
-	})
+	h.respondWithJob(c, "Container import accepted", job, gin.H{"file_name": file.Filename})
 }
 
 func (h *RestContainerHandler) ExportContainers(c *gin.Context) {
@@ -280,3 +380,64 @@ func (h *RestContainerHandler) ExportContainers(c *gin.Context) {
 	c.Status(http.StatusOK)
 	c.Writer.Write(exportData.Data)
 }
+
+func (h *RestContainerHandler) GetContainerStatusHistory(c *gin.Context) {
+	idUint, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid ID format", err)
+		return
+	}
+
+	to := time.Now().UTC()
+	if raw := c.Query("to"); raw != "" {
+		if to, err = time.Parse(time.RFC3339, raw); err != nil {
+			h.respondWithError(c, http.StatusBadRequest, "Invalid 'to' parameter, expected RFC3339", err)
+			return
+		}
+	}
+
+	from := to.Add(-DefaultHistoryWindow)
+	if raw := c.Query("from"); raw != "" {
+		if from, err = time.Parse(time.RFC3339, raw); err != nil {
+			h.respondWithError(c, http.StatusBadRequest, "Invalid 'from' parameter, expected RFC3339", err)
+			return
+		}
+	}
+
+	if !from.Before(to) {
+		h.respondWithError(c, http.StatusBadRequest, "'from' must be before 'to'", nil)
+		return
+	}
+
+	size, err := strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(DefaultHistorySize)))
+	if err != nil || size <= 0 || size > MaxRangeLimit {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid 'size' parameter", err)
+		return
+	}
+
+	history, err := h.service.GetContainerStatusHistory(c, uint(idUint), from, to, c.Query("status"), size, c.Query("cursor"))
+	if errors.Is(err, service.ErrInvalidStatus) {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid 'status' parameter", err)
+		return
+	}
+	if errors.Is(err, service.ErrInvalidHistoryCursor) {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid 'cursor' parameter", err)
+		return
+	}
+	if errors.Is(err, service.ErrContainerNotFound) {
+		h.respondWithError(c, http.StatusNotFound, "Container not found", nil)
+		return
+	}
+	if err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to retrieve container status history", err)
+		return
+	}
+
+	h.respondWithSuccess(c, http.StatusOK, gin.H{
+		"id":          history.ID,
+		"from":        from,
+		"to":          to,
+		"spans":       history.Spans,
+		"next_cursor": history.NextCursor,
+	})
+}
@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+	"thanhnt208/container-adm-service/internal/service"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RestTokenHandler struct {
+	service service.ITokenRevocationService
+	logger  logger.ILogger
+}
+
+func NewRestTokenHandler(service service.ITokenRevocationService, logger logger.ILogger) *RestTokenHandler {
+	return &RestTokenHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *RestTokenHandler) respondWithError(c *gin.Context, statusCode int, message string, err error) {
+	if err != nil {
+		h.logger.Error(message, "error", err)
+	} else {
+		h.logger.Error(message)
+	}
+	c.JSON(statusCode, gin.H{
+		"error": message,
+	})
+}
+
+type revokeTokenRequest struct {
+	JTI       string    `json:"jti" binding:"required"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RevokeToken denylists a single token by its jti. Without expires_at the
+// entry is kept for the longest lifetime a token can have.
+func (h *RestTokenHandler) RevokeToken(c *gin.Context) {
+	var req revokeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.service.RevokeToken(c.Request.Context(), req.JTI, req.ExpiresAt); err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to revoke token", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Token revoked",
+		"jti":     req.JTI,
+	})
+}
+
+type revokeUserTokensRequest struct {
+	IssuedBefore time.Time `json:"issued_before"`
+}
+
+// RevokeUserTokens rejects every token of the user issued before
+// issued_before, or before now when it is omitted.
+func (h *RestTokenHandler) RevokeUserTokens(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		h.respondWithError(c, http.StatusBadRequest, "Invalid user ID", err)
+		return
+	}
+
+	var req revokeUserTokensRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			h.respondWithError(c, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+	if req.IssuedBefore.IsZero() {
+		req.IssuedBefore = time.Now()
+	}
+
+	if err := h.service.RevokeUserTokens(c.Request.Context(), uint(userID), req.IssuedBefore); err != nil {
+		h.respondWithError(c, http.StatusInternalServerError, "Failed to revoke user tokens", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "User tokens revoked",
+		"user_id":       userID,
+		"issued_before": req.IssuedBefore.UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+// Audit actions of admin changes. They concern no container, so their
+// entries have container ID 0.
+const (
+	AuditQuotaCreated              = "quota.created"
+	AuditQuotaUpdated              = "quota.updated"
+	AuditQuotaDeleted              = "quota.deleted"
+	AuditRegistryCredentialSet     = "registry_credential.set"
+	AuditRegistryCredentialDeleted = "registry_credential.deleted"
+	AuditTokenRevoked              = "token.revoked"
+	AuditUserTokensRevoked         = "token.user_revoked"
+)
+
+// AuditFilter narrows the audit log. Zero values do not filter. Actor matches
+// either the username or the numeric user ID.
+type AuditFilter struct {
+	ContainerID uint
+	Actor       string
+	From        time.Time
+	To          time.Time
+}
+
+// FieldChange is the old and new value of one changed field.
+type FieldChange struct {
+	Old interface{} `json:"old,omitempty"`
+	New interface{} `json:"new,omitempty"`
+}
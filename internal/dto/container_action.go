@@ -0,0 +1,30 @@
+package dto
+
+// Actions that change the run state of a container.
+const (
+	ContainerActionStart   = "start"
+	ContainerActionStop    = "stop"
+	ContainerActionRestart = "restart"
+	ContainerActionPause   = "pause"
+	ContainerActionUnpause = "unpause"
+	ContainerActionKill    = "kill"
+)
+
+var ContainerActions = []string{
+	ContainerActionStart,
+	ContainerActionStop,
+	ContainerActionRestart,
+	ContainerActionPause,
+	ContainerActionUnpause,
+	ContainerActionKill,
+}
+
+// ContainerActionRequest holds the options of a container action. Fields
+// that do not apply to the action are ignored.
+type ContainerActionRequest struct {
+	// Timeout is how many seconds restart waits for the container to stop
+	// before killing it. 0 uses the stop timeout of the container.
+	Timeout int `json:"timeout" binding:"min=0"`
+	// Signal is sent by kill, SIGKILL when empty.
+	Signal string `json:"signal"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"thanhnt208/container-adm-service/internal/model"
+	"time"
+)
+
+const ContainerEventSchemaVersion = 1
+
+const (
+	EventContainerCreated       = "container.created"
+	EventContainerUpdated       = "container.updated"
+	EventContainerDeleted       = "container.deleted"
+	EventContainerImported      = "container.imported"
+	EventContainerImageChanged  = "container.image_changed"
+	EventContainerStatusChanged = "container.status_changed"
+)
+
+// ContainerEvent is the message published to the container events topic.
+// Consumers must check SchemaVersion before decoding the rest.
+type ContainerEvent struct {
+	SchemaVersion int              `json:"schema_version"`
+	EventID       string           `json:"event_id"`
+	EventType     string           `json:"event_type"`
+	OccurredAt    time.Time        `json:"occurred_at"`
+	CorrelationID string           `json:"correlation_id"`
+	Actor         EventActor       `json:"actor"`
+	ContainerID   uint             `json:"container_id"`
+	ChangedFields []string         `json:"changed_fields,omitempty"`
+	Before        *model.Container `json:"before"`
+	After         *model.Container `json:"after"`
+}
+
+// EventActor identifies who caused the event. Changes made by background
+// workers without a user carry the "system" role.
+type EventActor struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
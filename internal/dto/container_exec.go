@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"io"
+	"time"
+)
+
+// AuditContainerExec is the audit action of an exec session.
+const AuditContainerExec = "container.exec"
+
+// ExecOptions describes a command to run in a container.
+type ExecOptions struct {
+	Cmd []string
+	Tty bool
+	// Initial terminal size, only used with Tty. Zero keeps Docker's default.
+	Width  uint
+	Height uint
+}
+
+// ExecResize is a new terminal size for a TTY session.
+type ExecResize struct {
+	Width  uint `json:"width"`
+	Height uint `json:"height"`
+}
+
+// ExecIO connects an exec session to its caller. Stdin may be nil when the
+// command gets no input; closing it closes the command's stdin. With a TTY
+// all output is written to Stdout.
+type ExecIO struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	Resize <-chan ExecResize
+}
+
+// ExecResult is the outcome of an exec session. ExitCode is -1 when it could
+// not be determined.
+type ExecResult struct {
+	ExecID   string        `json:"exec_id"`
+	ExitCode int           `json:"exit_code"`
+	Duration time.Duration `json:"duration"`
+}
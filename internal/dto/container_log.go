@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+const (
+	LogStreamStdout = "stdout"
+	LogStreamStderr = "stderr"
+)
+
+// ContainerLogOptions selects the log lines of a container.
+type ContainerLogOptions struct {
+	// Follow keeps the stream open and sends new lines as they are written.
+	Follow bool
+	// Tail is the number of lines to return from the end of the log, or a
+	// negative number for all of them.
+	Tail int
+	// Since and Until bound the lines by the time they were written; zero
+	// values leave the bound open.
+	Since time.Time
+	Until time.Time
+	// Timestamps adds the time each line was written.
+	Timestamps bool
+	Stdout     bool
+	Stderr     bool
+}
+
+// ContainerLogLine is one line of container output without its line ending.
+type ContainerLogLine struct {
+	Stream    string     `json:"stream"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	Text      string     `json:"text"`
+}
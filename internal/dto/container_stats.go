@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// ContainerStats is a snapshot of the resources a container uses. Network
+// and block IO are totals since the container started.
+type ContainerStats struct {
+	Time            time.Time `json:"time"`
+	CPUPercent      float64   `json:"cpu_percent"`
+	OnlineCPUs      uint32    `json:"online_cpus"`
+	MemoryUsage     uint64    `json:"memory_usage_bytes"`
+	MemoryLimit     uint64    `json:"memory_limit_bytes"`
+	MemoryPercent   float64   `json:"memory_percent"`
+	NetworkRxBytes  uint64    `json:"network_rx_bytes"`
+	NetworkTxBytes  uint64    `json:"network_tx_bytes"`
+	BlockReadBytes  uint64    `json:"block_read_bytes"`
+	BlockWriteBytes uint64    `json:"block_write_bytes"`
+	PIDs            uint64    `json:"pids"`
+}
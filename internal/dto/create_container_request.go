@@ -1,6 +1,9 @@
 package dto
 
+import "thanhnt208/container-adm-service/internal/model"
+
 type CreateContainerRequest struct {
 	ContainerName string `json:"container_name" binding:"required"`
 	ImageName     string `json:"image_name" binding:"required"`
+	model.ContainerSpec
 }
@@ -0,0 +1,18 @@
+package dto
+
+// ImportContainersJobRequest is the request of a container import job. The
+// file is stored with the job so any instance can run it.
+type ImportContainersJobRequest struct {
+	File []byte `json:"file"`
+}
+
+// UpdateContainerJobRequest is the request of a container update job.
+type UpdateContainerJobRequest struct {
+	ID         uint                   `json:"id"`
+	UpdateData map[string]interface{} `json:"update_data"`
+}
+
+// CreateContainerJobResult is the result of a container creation job.
+type CreateContainerJobResult struct {
+	ID int `json:"id"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+import "thanhnt208/container-adm-service/internal/model"
+
+// QuotaUsage is what a user currently consumes. CPU shares and memory count
+// running containers only.
+type QuotaUsage struct {
+	Containers  int64 `json:"containers"`
+	Running     int64 `json:"running"`
+	CPUShares   int64 `json:"cpu_shares"`
+	MemoryBytes int64 `json:"memory_bytes"`
+}
+
+// QuotaStatus is the quota that applies to a user, if any, and its usage.
+type QuotaStatus struct {
+	Quota *model.Quota `json:"quota"`
+	Usage QuotaUsage   `json:"usage"`
+}
+
+// QuotaRequest creates or updates a quota. The subject of an existing quota
+// cannot be changed, so it is ignored on update.
+type QuotaRequest struct {
+	SubjectType    string `json:"subject_type" binding:"omitempty,oneof=user role"`
+	Subject        string `json:"subject"`
+	MaxContainers  int64  `json:"max_containers" binding:"min=0"`
+	MaxRunning     int64  `json:"max_running" binding:"min=0"`
+	MaxCPUShares   int64  `json:"max_cpu_shares" binding:"min=0"`
+	MaxMemoryBytes int64  `json:"max_memory_bytes" binding:"min=0"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+type ReconcileResult struct {
+	Checked int `json:"checked"`
+	Updated int `json:"updated"`
+	Missing int `json:"missing"`
+	Failed  int `json:"failed"`
+}
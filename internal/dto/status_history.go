@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+type StatusSpan struct {
+	Status     string    `json:"status"`
+	StartedAt  time.Time `json:"started_at"`
+	EndedAt    time.Time `json:"ended_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Ongoing    bool      `json:"ongoing"`
+}
+
+type StatusHistory struct {
+	ID         uint         `json:"id"`
+	Spans      []StatusSpan `json:"spans"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+}
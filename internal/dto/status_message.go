@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+const StatusMessageSchemaVersion = 1
+
+// StatusMessage is a container status report read from the status topic.
+//
+// Version 1 carries the status as a string together with the time it was
+// observed and the producer's sequence number. Reports of one producer are
+// applied in sequence order; a sequence not above the last applied one is
+// dropped as stale:
+//
+//	{"schema_version":1,"id":1,"container_name":"web","status":"running",
+//	 "event_time":"2024-01-01T00:00:00Z","producer":"agent-1","sequence":42}
+//
+// Version 0 messages have no schema_version and a boolean status:
+//
+//	{"id":1,"container_name":"web","status":true}
+//
+// They have no event time of their own, so the broker timestamp is used.
+type StatusMessage struct {
+	SchemaVersion int       `json:"schema_version"`
+	ID            uint      `json:"id"`
+	ContainerName string    `json:"container_name"`
+	Status        string    `json:"status"`
+	EventTime     time.Time `json:"event_time"`
+	Producer      string    `json:"producer,omitempty"`
+	Sequence      int64     `json:"sequence,omitempty"`
+}
+
+// Origin returns the producer and sequence number of the message.
+func (m *StatusMessage) Origin() StatusOrigin {
+	return StatusOrigin{Producer: m.Producer, Sequence: m.Sequence}
+}
+
+// StatusOrigin identifies a status report by its producer and the position
+// in that producer's sequence. Reports without a producer or sequence, such
+// as Docker events and version 0 messages, are only ordered by time.
+type StatusOrigin struct {
+	Producer string
+	Sequence int64
+}
+
+// Ordered reports whether the origin carries a sequence number.
+func (o StatusOrigin) Ordered() bool {
+	return o.Producer != "" && o.Sequence > 0
+}
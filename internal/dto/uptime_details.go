@@ -2,7 +2,12 @@ package dto
 
 import "time"
 
+const (
+	UptimeModeSample     = "sample"
+	UptimeModeTransition = "transition"
+)
+
 type UptimeDetails struct {
 	TotalUptime        time.Duration            `json:"total_uptime"`
 	PerContainerUptime map[string]time.Duration `json:"per_container_uptime"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditResultSuccess = "success"
+	AuditResultFailure = "failure"
+)
+
+// AuditEvent records who changed a container, from where and with what
+// outcome. Successful changes are written in the same transaction as the
+// change itself.
+type AuditEvent struct {
+	ID            uint64          `json:"id" gorm:"primaryKey;autoIncrement"`
+	ActorUserID   uint            `json:"actor_user_id" gorm:"not null;default:0"`
+	ActorUsername string          `json:"actor_username" gorm:"not null;default:''"`
+	ActorRole     string          `json:"actor_role" gorm:"not null;default:''"`
+	Action        string          `json:"action" gorm:"not null"`
+	ContainerID   uint            `json:"container_id" gorm:"not null;default:0"`
+	Changes       json.RawMessage `json:"changes" gorm:"type:jsonb"`
+	SourceIP      string          `json:"source_ip" gorm:"not null;default:''"`
+	Transport     string          `json:"transport" gorm:"not null"`
+	CorrelationID string          `json:"correlation_id" gorm:"not null;default:''"`
+	Result        string          `json:"result" gorm:"not null"`
+	Error         string          `json:"error,omitempty" gorm:"not null;default:''"`
+	CreatedAt     time.Time       `json:"created_at" gorm:"autoCreateTime"`
+}
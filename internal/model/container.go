@@ -3,11 +3,18 @@ package model
 import "time"
 
 type Container struct {
-	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement"`
-	ContainerID   string    `json:"container_id" gorm:"unique;not null"`
-	ContainerName string    `json:"container_name" gorm:"unique;not null"`
-	ImageName     string    `json:"image_name" gorm:"not null"`
-	Status        string    `json:"status" gorm:"not null"`
-	CreatedAt     time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt     time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID                 uint          `json:"id" gorm:"primaryKey;autoIncrement"`
+	ContainerID        string        `json:"container_id" gorm:"unique;not null"`
+	ContainerName      string        `json:"container_name" gorm:"unique;not null"`
+	ImageName          string        `json:"image_name" gorm:"not null"`
+	Status             string        `json:"status" gorm:"not null"`
+	Spec               ContainerSpec `json:"spec" gorm:"type:jsonb;serializer:json;not null"`
+	LastStatusAt       *time.Time    `json:"last_status_at,omitempty"`
+	LastStatusProducer string        `json:"last_status_producer,omitempty" gorm:"not null;default:''"`
+	LastStatusSequence int64         `json:"last_status_sequence,omitempty" gorm:"not null;default:0"`
+	OwnerID            uint          `json:"owner_id" gorm:"index;not null;default:0"`
+	OwnerRole          string        `json:"owner_role" gorm:"not null;default:''"`
+	TenantID           string        `json:"tenant_id" gorm:"index;not null;default:''"`
+	CreatedAt          time.Time     `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt          time.Time     `json:"updated_at" gorm:"autoUpdateTime"`
 }
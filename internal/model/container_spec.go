@@ -0,0 +1,95 @@
+package model
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	RestartPolicyNo            = "no"
+	RestartPolicyAlways        = "always"
+	RestartPolicyOnFailure     = "on-failure"
+	RestartPolicyUnlessStopped = "unless-stopped"
+)
+
+// ContainerSpec is the runtime configuration a container is created with.
+// It is persisted alongside the container so that the same configuration can
+// be re-applied whenever the Docker container has to be recreated.
+type ContainerSpec struct {
+	Ports         []PortBinding     `json:"ports,omitempty"`
+	Env           map[string]string `json:"env,omitempty"`
+	Volumes       []VolumeMount     `json:"volumes,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	Command       []string          `json:"command,omitempty"`
+	RestartPolicy string            `json:"restart_policy,omitempty"`
+	CPUShares     int64             `json:"cpu_shares,omitempty"`
+	MemoryBytes   int64             `json:"memory_bytes,omitempty"`
+}
+
+type PortBinding struct {
+	ContainerPort int    `json:"container_port"`
+	HostPort      int    `json:"host_port,omitempty"`
+	HostIP        string `json:"host_ip,omitempty"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+type VolumeMount struct {
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	ReadOnly bool   `json:"read_only,omitempty"`
+}
+
+func (s *ContainerSpec) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	for _, p := range s.Ports {
+		if p.ContainerPort < 1 || p.ContainerPort > 65535 {
+			return fmt.Errorf("invalid container port: %d", p.ContainerPort)
+		}
+		if p.HostPort < 0 || p.HostPort > 65535 {
+			return fmt.Errorf("invalid host port: %d", p.HostPort)
+		}
+		if p.Protocol != "" && p.Protocol != "tcp" && p.Protocol != "udp" && p.Protocol != "sctp" {
+			return fmt.Errorf("invalid port protocol: %s", p.Protocol)
+		}
+	}
+
+	for key := range s.Env {
+		if strings.TrimSpace(key) == "" || strings.Contains(key, "=") {
+			return fmt.Errorf("invalid environment variable name: %q", key)
+		}
+	}
+
+	for _, v := range s.Volumes {
+		if strings.TrimSpace(v.Source) == "" {
+			return fmt.Errorf("volume source is required")
+		}
+		if !path.IsAbs(v.Target) {
+			return fmt.Errorf("volume target must be an absolute path: %s", v.Target)
+		}
+	}
+
+	for key := range s.Labels {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("label key cannot be empty")
+		}
+	}
+
+	if s.CPUShares < 0 {
+		return fmt.Errorf("cpu shares cannot be negative: %d", s.CPUShares)
+	}
+	if s.MemoryBytes < 0 {
+		return fmt.Errorf("memory limit cannot be negative: %d", s.MemoryBytes)
+	}
+
+	switch s.RestartPolicy {
+	case "", RestartPolicyNo, RestartPolicyAlways, RestartPolicyOnFailure, RestartPolicyUnlessStopped:
+	default:
+		return fmt.Errorf("invalid restart policy: %s", s.RestartPolicy)
+	}
+
+	return nil
+}
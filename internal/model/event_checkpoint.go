@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// EventCheckpoint is the position an event consumer resumes from after a
+// restart.
+type EventCheckpoint struct {
+	Source      string    `json:"source" gorm:"primaryKey"`
+	LastSeen    time.Time `json:"last_seen" gorm:"not null"`
+	LastEventID string    `json:"last_event_id" gorm:"not null;default:''"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
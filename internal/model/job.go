@@ -0,0 +1,93 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+)
+
+const (
+	JobTypeContainerCreate = "container.create"
+	JobTypeContainerImport = "container.import"
+	JobTypeContainerUpdate = "container.update"
+)
+
+// Steps a job reports while it runs.
+const (
+	JobStepStop    = "stop"
+	JobStepRemove  = "remove"
+	JobStepPull    = "pull"
+	JobStepCreate  = "create"
+	JobStepStart   = "start"
+	JobStepPersist = "persist"
+)
+
+const (
+	JobStepRunning   = "running"
+	JobStepSucceeded = "succeeded"
+	JobStepFailed    = "failed"
+)
+
+// Job is a slow operation that runs on a worker after its request returned.
+// The request is stored so that any instance can run it, together with the
+// caller it runs on behalf of.
+type Job struct {
+	ID              string          `json:"id" gorm:"type:uuid;primaryKey"`
+	Type            string          `json:"type" gorm:"not null"`
+	Status          string          `json:"status" gorm:"not null"`
+	Steps           []JobStep       `json:"steps" gorm:"type:jsonb;serializer:json;not null"`
+	Request         json.RawMessage `json:"-" gorm:"type:jsonb;not null"`
+	Result          json.RawMessage `json:"result,omitempty" gorm:"type:jsonb"`
+	Error           string          `json:"error,omitempty" gorm:"not null;default:''"`
+	ContainerID     uint            `json:"container_id,omitempty" gorm:"not null;default:0"`
+	Actor           JobActor        `json:"actor" gorm:"type:jsonb;serializer:json;not null"`
+	OwnerID         uint            `json:"owner_id" gorm:"index;not null;default:0"`
+	TenantID        string          `json:"tenant_id" gorm:"index;not null;default:''"`
+	CorrelationID   string          `json:"correlation_id" gorm:"not null;default:''"`
+	CancelRequested bool            `json:"cancel_requested" gorm:"not null;default:false"`
+	CreatedAt       time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
+	StartedAt       *time.Time      `json:"started_at,omitempty"`
+	FinishedAt      *time.Time      `json:"finished_at,omitempty"`
+}
+
+// IsFinished reports whether the job reached a final status.
+func (j *Job) IsFinished() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed || j.Status == JobStatusCancelled
+}
+
+// JobStep is one step of a job. Target names what the step works on, such
+// as the image being pulled or the container being created.
+type JobStep struct {
+	Name       string              `json:"name"`
+	Target     string              `json:"target,omitempty"`
+	Status     string              `json:"status"`
+	Layers     map[string]JobLayer `json:"layers,omitempty"`
+	StartedAt  time.Time           `json:"started_at"`
+	FinishedAt *time.Time          `json:"finished_at,omitempty"`
+}
+
+// JobLayer is the download progress of one image layer.
+type JobLayer struct {
+	Status  string `json:"status"`
+	Current int64  `json:"current,omitempty"`
+	Total   int64  `json:"total,omitempty"`
+}
+
+// JobActor is the caller a job runs on behalf of.
+type JobActor struct {
+	UserID    uint     `json:"user_id"`
+	Username  string   `json:"username"`
+	Role      string   `json:"role"`
+	TenantID  string   `json:"tenant_id,omitempty"`
+	Scopes    []string `json:"scopes,omitempty"`
+	SourceIP  string   `json:"source_ip,omitempty"`
+	Transport string   `json:"transport"`
+}
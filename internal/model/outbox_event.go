@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// OutboxEvent is a container lifecycle event waiting to be published to
+// Kafka. It is written in the same transaction as the change it describes.
+type OutboxEvent struct {
+	ID          uint64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	EventID     string     `json:"event_id" gorm:"type:uuid;unique;not null"`
+	EventType   string     `json:"event_type" gorm:"not null"`
+	AggregateID uint       `json:"aggregate_id" gorm:"not null"`
+	Payload     []byte     `json:"payload" gorm:"type:jsonb;not null"`
+	Attempts    int        `json:"attempts" gorm:"not null;default:0"`
+	LastError   string     `json:"last_error"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "container_outbox"
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+const (
+	QuotaSubjectUser = "user"
+	QuotaSubjectRole = "role"
+)
+
+// Quota caps what a single user may run. It applies to one user, or to every
+// user with a role; a user quota takes precedence over the quota of the
+// user's role. A limit of 0 means unlimited.
+type Quota struct {
+	ID             uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	SubjectType    string    `json:"subject_type" gorm:"not null;uniqueIndex:idx_quotas_subject"`
+	Subject        string    `json:"subject" gorm:"not null;uniqueIndex:idx_quotas_subject"`
+	MaxContainers  int64     `json:"max_containers" gorm:"not null;default:0"`
+	MaxRunning     int64     `json:"max_running" gorm:"not null;default:0"`
+	MaxCPUShares   int64     `json:"max_cpu_shares" gorm:"column:max_cpu_shares;not null;default:0"`
+	MaxMemoryBytes int64     `json:"max_memory_bytes" gorm:"not null;default:0"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
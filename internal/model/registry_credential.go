@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// RegistryCredential holds the login used to pull images from a registry
+// host. The password is stored encrypted and never serialized.
+type RegistryCredential struct {
+	ID                uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Registry          string    `json:"registry" gorm:"uniqueIndex;not null"`
+	Username          string    `json:"username" gorm:"not null"`
+	EncryptedPassword []byte    `json:"-" gorm:"not null"`
+	CreatedAt         time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt         time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/utils"
+
+	"gorm.io/gorm"
+)
+
+type IAuditRepository interface {
+	RecordFailure(ctx context.Context, action string, containerID uint, request interface{}, cause error) error
+	RecordEvent(ctx context.Context, action string, containerID uint, details interface{}, cause error) error
+	ListAuditEvents(ctx context.Context, filter *dto.AuditFilter, offset, limit int) (int64, []model.AuditEvent, error)
+}
+
+type auditRepository struct {
+	db     *gorm.DB
+	logger logger.ILogger
+}
+
+func NewAuditRepository(db *gorm.DB, logger logger.ILogger) IAuditRepository {
+	return &auditRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// RecordFailure writes the audit entry of a change that did not happen. Its
+// transaction was rolled back, so the entry is written on its own. request
+// holds the requested values, if any.
+func (r *auditRepository) RecordFailure(ctx context.Context, action string, containerID uint, request interface{}, cause error) error {
+	return r.RecordEvent(ctx, action, containerID, request, cause)
+}
+
+// RecordEvent writes the audit entry of something that does not change a
+// container, such as an exec session or an admin change. details is stored
+// as the changes and a non-nil cause marks the entry as failed.
+func (r *auditRepository) RecordEvent(ctx context.Context, action string, containerID uint, details interface{}, cause error) error {
+	var changes json.RawMessage
+	if details != nil {
+		raw, err := encodeAuditDetails(details)
+		if err != nil {
+			return fmt.Errorf("failed to encode audit details: %w", err)
+		}
+		changes = raw
+	}
+
+	event := newAuditEvent(ctx, action, containerID, changes)
+	if cause != nil {
+		event.Result = model.AuditResultFailure
+		event.Error = cause.Error()
+	}
+
+	if err := r.db.WithContext(ctx).Create(event).Error; err != nil {
+		r.logger.Error("Failed to write audit event", "action", action, "containerID", containerID, "error", err)
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	return nil
+}
+
+func (r *auditRepository) ListAuditEvents(ctx context.Context, filter *dto.AuditFilter, offset, limit int) (int64, []model.AuditEvent, error) {
+	query := r.db.WithContext(ctx).Model(&model.AuditEvent{})
+
+	if filter != nil {
+		if filter.ContainerID != 0 {
+			query = query.Where("container_id = ?", filter.ContainerID)
+		}
+		if filter.Actor != "" {
+			if userID, err := strconv.ParseUint(filter.Actor, 10, 64); err == nil {
+				query = query.Where("actor_username = ? OR actor_user_id = ?", filter.Actor, userID)
+			} else {
+				query = query.Where("actor_username = ?", filter.Actor)
+			}
+		}
+		if !filter.From.IsZero() {
+			query = query.Where("created_at >= ?", filter.From.UTC())
+		}
+		if !filter.To.IsZero() {
+			query = query.Where("created_at <= ?", filter.To.UTC())
+		}
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		r.logger.Error("Failed to count audit events", "error", err)
+		return 0, nil, fmt.Errorf("failed to count audit events: %w", err)
+	}
+
+	var events []model.AuditEvent
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+		r.logger.Error("Failed to retrieve audit events", "error", err)
+		return 0, nil, fmt.Errorf("failed to retrieve audit events: %w", err)
+	}
+
+	r.logger.Info("Audit events retrieved successfully", "total", total, "count", len(events))
+	return total, events, nil
+}
+
+// newAuditEvent fills in the actor and origin of a change from ctx.
+func newAuditEvent(ctx context.Context, action string, containerID uint, changes json.RawMessage) *model.AuditEvent {
+	meta := utils.RequestMetaFromContext(ctx)
+	event := &model.AuditEvent{
+		ActorRole:     "system",
+		Action:        action,
+		ContainerID:   containerID,
+		Changes:       changes,
+		SourceIP:      meta.SourceIP,
+		Transport:     meta.Transport,
+		CorrelationID: utils.CorrelationIDFromContext(ctx),
+		Result:        model.AuditResultSuccess,
+	}
+
+	if claims, ok := utils.ClaimsFromContext(ctx); ok {
+		event.ActorUserID = claims.UserID
+		event.ActorUsername = claims.Username
+		event.ActorRole = claims.Role
+	}
+	return event
+}
+
+// writeAudit records a successful change inside the transaction making it.
+func writeAudit(ctx context.Context, tx *gorm.DB, action string, before, after *model.Container) error {
+	changes, err := json.Marshal(diffContainers(before, after))
+	if err != nil {
+		return fmt.Errorf("failed to encode audit changes: %w", err)
+	}
+
+	var containerID uint
+	switch {
+	case after != nil:
+		containerID = after.ID
+	case before != nil:
+		containerID = before.ID
+	}
+
+	if err := tx.Create(newAuditEvent(ctx, action, containerID, changes)).Error; err != nil {
+		return fmt.Errorf("failed to write audit event: %w", err)
+	}
+	return nil
+}
+
+// diffContainers returns the fields that differ between two versions of a
+// container. A nil side means the container was created or deleted.
+func diffContainers(before, after *model.Container) map[string]dto.FieldChange {
+	oldFields := containerFields(before)
+	newFields := containerFields(after)
+
+	changes := make(map[string]dto.FieldChange)
+	for field, newValue := range newFields {
+		if oldValue, ok := oldFields[field]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changes[field] = dto.FieldChange{Old: oldFields[field], New: newValue}
+		}
+	}
+	for field, oldValue := range oldFields {
+		if _, ok := newFields[field]; !ok {
+			changes[field] = dto.FieldChange{Old: oldValue}
+		}
+	}
+
+	// Redacted only now so that a changed value still shows up as a change.
+	if change, ok := changes["spec"]; ok {
+		changes["spec"] = dto.FieldChange{Old: redactSpec(change.Old), New: redactSpec(change.New)}
+	}
+	return changes
+}
+
+// encodeAuditDetails encodes the details of an audit entry. A container spec
+// among them, like the one of a failed create, is redacted.
+func encodeAuditDetails(details interface{}) (json.RawMessage, error) {
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+	if json.Unmarshal(raw, &fields) != nil || fields["spec"] == nil {
+		return raw, nil
+	}
+	fields["spec"] = redactSpec(fields["spec"])
+	return json.Marshal(fields)
+}
+
+// redactSpec replaces the environment of a decoded container spec with the
+// sorted variable names. Values often hold secrets and must not reach the
+// audit log.
+func redactSpec(spec interface{}) interface{} {
+	fields, ok := spec.(map[string]interface{})
+	if !ok {
+		return spec
+	}
+	env, ok := fields["env"].(map[string]interface{})
+	if !ok {
+		return spec
+	}
+
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	redacted := make(map[string]interface{}, len(fields))
+	for field, value := range fields {
+		redacted[field] = value
+	}
+	redacted["env"] = names
+	return redacted
+}
+
+func containerFields(container *model.Container) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if container == nil {
+		return fields
+	}
+
+	raw, err := json.Marshal(container)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(raw, &fields)
+
+	// Bookkeeping timestamps change on every write and say nothing on their own.
+	delete(fields, "created_at")
+	delete(fields, "updated_at")
+	return fields
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"thanhnt208/container-adm-service/internal/model"
+)
+
+func TestDiffContainersRedactsEnv(t *testing.T) {
+	before := &model.Container{ID: 1, Spec: model.ContainerSpec{
+		Env:         map[string]string{"DB_PASSWORD": "old-secret", "DEBUG": "1"},
+		MemoryBytes: 1 << 20,
+	}}
+	after := &model.Container{ID: 1, Spec: model.ContainerSpec{
+		Env:         map[string]string{"DB_PASSWORD": "new-secret", "DEBUG": "1"},
+		MemoryBytes: 1 << 20,
+	}}
+
+	changes := diffContainers(before, after)
+	change, ok := changes["spec"]
+	if !ok {
+		t.Fatalf("changes = %v, want the changed spec", changes)
+	}
+
+	raw, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatalf("failed to encode changes: %v", err)
+	}
+	if strings.Contains(string(raw), "secret") {
+		t.Errorf("changes = %s, want env values redacted", raw)
+	}
+	for _, spec := range []interface{}{change.Old, change.New} {
+		fields := spec.(map[string]interface{})
+		env, _ := json.Marshal(fields["env"])
+		if string(env) != `["DB_PASSWORD","DEBUG"]` {
+			t.Errorf("env = %s, want the variable names", env)
+		}
+		if fields["memory_bytes"] != float64(1<<20) {
+			t.Errorf("memory_bytes = %v, want the rest of the spec kept", fields["memory_bytes"])
+		}
+	}
+}
+
+func TestEncodeAuditDetailsRedactsEnv(t *testing.T) {
+	tests := map[string]struct {
+		details interface{}
+		want    string
+	}{
+		"failed create": {
+			details: map[string]interface{}{
+				"image_name": "nginx:1.27",
+				"spec":       &model.ContainerSpec{Env: map[string]string{"TOKEN": "secret"}},
+			},
+			want: `{"image_name":"nginx:1.27","spec":{"env":["TOKEN"]}}`,
+		},
+		"spec without env": {
+			details: map[string]interface{}{"spec": map[string]interface{}{"cpu_shares": 512}},
+			want:    `{"spec":{"cpu_shares":512}}`,
+		},
+		"no spec": {
+			details: map[string]interface{}{"command": []string{"/bin/sh"}},
+			want:    `{"command":["/bin/sh"]}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			raw, err := encodeAuditDetails(tt.details)
+			if err != nil {
+				t.Fatalf("encodeAuditDetails() error = %v", err)
+			}
+			if string(raw) != tt.want {
+				t.Errorf("encodeAuditDetails() = %s, want %s", raw, tt.want)
+			}
+		})
+	}
+}
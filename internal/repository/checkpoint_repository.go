@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/pkg/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ICheckpointRepository interface {
+	GetCheckpoint(ctx context.Context, source string) (*model.EventCheckpoint, error)
+	SaveCheckpoint(ctx context.Context, checkpoint *model.EventCheckpoint) error
+}
+
+type checkpointRepository struct {
+	db     *gorm.DB
+	logger logger.ILogger
+}
+
+func NewCheckpointRepository(db *gorm.DB, logger logger.ILogger) ICheckpointRepository {
+	return &checkpointRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetCheckpoint returns the checkpoint of source, or nil when none was
+// saved yet.
+func (r *checkpointRepository) GetCheckpoint(ctx context.Context, source string) (*model.EventCheckpoint, error) {
+	var checkpoint model.EventCheckpoint
+	err := r.db.WithContext(ctx).Where("source = ?", source).First(&checkpoint).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to get event checkpoint", "source", source, "error", err)
+		return nil, fmt.Errorf("failed to get event checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+func (r *checkpointRepository) SaveCheckpoint(ctx context.Context, checkpoint *model.EventCheckpoint) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "source"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_seen", "last_event_id", "updated_at"}),
+	}).Create(checkpoint).Error
+	if err != nil {
+		r.logger.Error("Failed to save event checkpoint", "source", checkpoint.Source, "error", err)
+		return fmt.Errorf("failed to save event checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"thanhnt208/container-adm-service/internal/dto"
+	"time"
+)
+
+var ErrInvalidHistoryCursor = errors.New("invalid history cursor")
+
+// GetContainerStatusHistory returns the status spans of one container in
+// [from, to]. Consecutive documents with the same status are merged into one
+// span and every span lasts until the next status change. The first page
+// starts with the status the container had before from. Spans are filtered
+// by status after they are built so that durations are not affected by the
+// filter. cursor is the next_cursor of a previous page, or empty.
+func (r *containerRepository) GetContainerStatusHistory(ctx context.Context, id uint, from, to time.Time, status string, size int, cursor string) (*dto.StatusHistory, error) {
+	if from.After(to) {
+		r.logger.Error("Start time cannot be after end time", "from", from, "to", to)
+		return nil, fmt.Errorf("start time cannot be after end time")
+	}
+
+	searchAfter, err := decodeHistoryCursor(cursor)
+	if err != nil {
+		r.logger.Warn("Invalid history cursor", "cursor", cursor, "error", err)
+		return nil, err
+	}
+
+	// The last span is still ongoing when the window reaches into the future.
+	openEnd, ongoing := to, false
+	if now := time.Now().UTC(); to.After(now) {
+		openEnd, ongoing = now, true
+	}
+
+	history := &dto.StatusHistory{ID: id, Spans: []dto.StatusSpan{}}
+
+	var current *dto.StatusSpan
+	var currentSort []interface{}
+
+	if searchAfter == nil {
+		initial, found, err := r.getLastStatusBefore(ctx, id, from)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			current = &dto.StatusSpan{Status: initial, StartedAt: from.UTC()}
+			// Resuming after this span reads the window from its start.
+			currentSort = []interface{}{from.UnixMilli() - 1, id, ""}
+		}
+	}
+
+	closeCurrent := func(endedAt time.Time, ongoing bool) bool {
+		current.EndedAt = endedAt
+		current.DurationMs = endedAt.Sub(current.StartedAt).Milliseconds()
+		current.Ongoing = ongoing
+		if status == "" || current.Status == status {
+			history.Spans = append(history.Spans, *current)
+		}
+		return len(history.Spans) >= size
+	}
+
+	for {
+		query := map[string]interface{}{
+			"size": statusHistoryPageSize,
+			"query": map[string]interface{}{
+				"bool": map[string]interface{}{
+					"filter": []map[string]interface{}{
+						{"term": map[string]interface{}{"id": id}},
+						{"range": map[string]interface{}{
+							"timestamp": map[string]interface{}{
+								"gte": from.UTC().Format(time.RFC3339),
+								"lte": to.UTC().Format(time.RFC3339),
+							},
+						}},
+					},
+				},
+			},
+			"sort":    statusHistorySort("asc"),
+			"_source": []string{"id", "status", "timestamp"},
+		}
+		if searchAfter != nil {
+			query["search_after"] = searchAfter
+		}
+
+		body, err := r.searchStatusIndex(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		docs, lastSort, err := parseStatusHits(body)
+		if err != nil {
+			r.logger.Error("Failed to parse status history", "id", id, "error", err)
+			return nil, err
+		}
+
+		for _, doc := range docs {
+			if current != nil && doc.Status == current.Status {
+				currentSort = doc.sort
+				continue
+			}
+
+			if current != nil && closeCurrent(doc.Timestamp, false) {
+				// The next page resumes right after the last document of this span.
+				if history.NextCursor, err = encodeHistoryCursor(currentSort); err != nil {
+					return nil, err
+				}
+				return history, nil
+			}
+
+			current = &dto.StatusSpan{Status: doc.Status, StartedAt: doc.Timestamp}
+			currentSort = doc.sort
+		}
+
+		if len(docs) < statusHistoryPageSize || lastSort == nil {
+			break
+		}
+		searchAfter = lastSort
+	}
+
+	if current != nil {
+		closeCurrent(openEnd, ongoing)
+	}
+
+	r.logger.Info("Container status history retrieved successfully", "id", id, "spans", len(history.Spans))
+	return history, nil
+}
+
+// getLastStatusBefore returns the last status recorded for the container
+// strictly before t.
+func (r *containerRepository) getLastStatusBefore(ctx context.Context, id uint, t time.Time) (string, bool, error) {
+	query := map[string]interface{}{
+		"size": 1,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"term": map[string]interface{}{"id": id}},
+					{"range": map[string]interface{}{
+						"timestamp": map[string]interface{}{
+							"lt": t.UTC().Format(time.RFC3339),
+						},
+					}},
+				},
+			},
+		},
+		"sort":    statusHistorySort("desc"),
+		"_source": []string{"id", "status", "timestamp"},
+	}
+
+	body, err := r.searchStatusIndex(ctx, query)
+	if err != nil {
+		return "", false, err
+	}
+
+	docs, _, err := parseStatusHits(body)
+	if err != nil {
+		r.logger.Error("Failed to parse last status", "id", id, "error", err)
+		return "", false, err
+	}
+	if len(docs) == 0 {
+		return "", false, nil
+	}
+	return docs[0].Status, true, nil
+}
+
+func encodeHistoryCursor(sort []interface{}) (string, error) {
+	raw, err := json.Marshal(sort)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode history cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeHistoryCursor(cursor string) ([]interface{}, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHistoryCursor, err)
+	}
+
+	var sort []interface{}
+	if err := json.Unmarshal(raw, &sort); err != nil || len(sort) != len(statusHistorySort("asc")) {
+		return nil, ErrInvalidHistoryCursor
+	}
+	return sort, nil
+}
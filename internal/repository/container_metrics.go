@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"thanhnt208/container-adm-service/internal/dto"
+	"time"
+)
+
+// AddContainerMetrics stores a stats sample in the container_metrics index.
+// Like status documents, the ID is derived from the sample so a retried
+// write does not add a duplicate.
+func (r *containerRepository) AddContainerMetrics(ctx context.Context, id uint, stats *dto.ContainerStats) error {
+	doc := map[string]interface{}{
+		"id":                 id,
+		"timestamp":          stats.Time.UTC().Format(time.RFC3339Nano),
+		"cpu_percent":        stats.CPUPercent,
+		"online_cpus":        stats.OnlineCPUs,
+		"memory_usage_bytes": stats.MemoryUsage,
+		"memory_limit_bytes": stats.MemoryLimit,
+		"memory_percent":     stats.MemoryPercent,
+		"network_rx_bytes":   stats.NetworkRxBytes,
+		"network_tx_bytes":   stats.NetworkTxBytes,
+		"block_read_bytes":   stats.BlockReadBytes,
+		"block_write_bytes":  stats.BlockWriteBytes,
+		"pids":               stats.PIDs,
+	}
+
+	docID := fmt.Sprintf("%d-%d", id, stats.Time.UnixNano())
+	if err := r.indexDocument(ctx, containerMetricsIndex, docID, doc); err != nil {
+		return err
+	}
+
+	r.logger.Debug("Container metrics added successfully", "id", id)
+	return nil
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"thanhnt208/container-adm-service/internal/dto"
 	"thanhnt208/container-adm-service/internal/model"
@@ -13,29 +15,91 @@ import (
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Container statuses. Stopped containers were stopped on purpose, while
+// exited ones ended on their own or were killed. Missing containers no
+// longer exist in Docker. Only time spent running counts as uptime.
 const (
-	StatusRunning = "running"
-	StatusStopped = "stopped"
+	StatusRunning    = "running"
+	StatusPaused     = "paused"
+	StatusRestarting = "restarting"
+	StatusStopped    = "stopped"
+	StatusExited     = "exited"
+	StatusDead       = "dead"
+	StatusMissing    = "missing"
+
+	containerStatusIndex  = "container_status"
+	containerMetricsIndex = "container_metrics"
 )
 
+// Statuses lists every valid container status.
+var Statuses = []string{
+	StatusRunning,
+	StatusPaused,
+	StatusRestarting,
+	StatusStopped,
+	StatusExited,
+	StatusDead,
+	StatusMissing,
+}
+
+// ActiveStatuses are the statuses in which a container holds its resources.
+// Quotas count containers in any of them as running.
+var ActiveStatuses = []string{
+	StatusRunning,
+	StatusPaused,
+	StatusRestarting,
+}
+
+func IsValidStatus(status string) bool {
+	return slices.Contains(Statuses, status)
+}
+
+func IsActiveStatus(status string) bool {
+	return slices.Contains(ActiveStatuses, status)
+}
+
+// IsStaleStatus reports whether a status observed at the given time must not
+// overwrite the current status of container. A report is stale when it is
+// older than the last applied one, or when its producer already delivered a
+// report with the same or a higher sequence number; the latter also orders
+// reports with equal timestamps.
+func IsStaleStatus(container *model.Container, at time.Time, origin dto.StatusOrigin) bool {
+	if container.LastStatusAt != nil && at.Before(*container.LastStatusAt) {
+		return true
+	}
+	return origin.Ordered() && origin.Producer == container.LastStatusProducer && origin.Sequence <= container.LastStatusSequence
+}
+
 type IContainerRepository interface {
 	CreateContainer(ctx context.Context, container *model.Container) (int, error)
 	CreateManyContainers(ctx context.Context, containers []model.Container) ([]model.Container, []model.Container, error)
 	ViewAllContainers(ctx context.Context, containerFilter *dto.ContainerFilter, from, to int, sortBy string, sortOrder string) (int64, []model.Container, error)
 	UpdateContainer(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Container, error)
+	UpdateContainerStatus(ctx context.Context, id uint, status string, at time.Time, origin dto.StatusOrigin) (*model.Container, bool, error)
+	UpdateObservedContainer(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Container, error)
+	UpdateObservedStatus(ctx context.Context, id uint, status string, at time.Time, origin dto.StatusOrigin) (*model.Container, bool, error)
 	DeleteContainer(ctx context.Context, id uint) error
 	GetContainerByID(ctx context.Context, id uint) (*model.Container, error)
+	GetContainerByContainerID(ctx context.Context, containerID string) (*model.Container, error)
+	ListAllContainers(ctx context.Context) ([]model.Container, error)
 
 	GetContainerInfo(ctx context.Context) ([]dto.ContainerName, error)
 
-	AddContainerStatus(ctx context.Context, id uint, status string) error
+	AddContainerStatus(ctx context.Context, id uint, status string, timestamp time.Time) error
+	RecordStatusDrift(ctx context.Context, id uint, previousStatus, status, reason string) error
+	AddContainerMetrics(ctx context.Context, id uint, stats *dto.ContainerStats) error
 	GetNumContainers(ctx context.Context) (int64, error)
 	GetNumRunningContainers(ctx context.Context) (int64, error)
-	GetContainerUptimeRatio(ctx context.Context, startTime, endTime time.Time) (float64, error)
-	GetContainerUptimeDuration(ctx context.Context, startTime, endTime time.Time) (*dto.UptimeDetails, error)
+	GetContainerUptimeRatio(ctx context.Context, startTime, endTime time.Time, mode string) (float64, error)
+	GetContainerUptimeDuration(ctx context.Context, startTime, endTime time.Time, mode string) (*dto.UptimeDetails, error)
+	GetContainerStatusHistory(ctx context.Context, id uint, from, to time.Time, status string, size int, cursor string) (*dto.StatusHistory, error)
+
+	CheckQuota(ctx context.Context, ownerID uint, ownerRole string, add dto.QuotaUsage) error
 }
 
 type containerRepository struct {
@@ -67,12 +131,30 @@ func (r *containerRepository) CreateContainer(ctx context.Context, container *mo
 		}
 	}()
 
+	if err := enforceQuota(tx, container.OwnerID, container.OwnerRole, QuotaForCreate(container.Spec)); err != nil {
+		tx.Rollback()
+		r.logger.Warn("Container creation exceeds quota", "ownerID", container.OwnerID, "error", err)
+		return 0, err
+	}
+
 	if err := tx.Create(container).Error; err != nil {
 		tx.Rollback()
 		r.logger.Error("Failed to create container", "error", err)
 		return 0, fmt.Errorf("failed to create container: %w", err)
 	}
 
+	if err := writeOutbox(ctx, tx, dto.EventContainerCreated, nil, container); err != nil {
+		tx.Rollback()
+		r.logger.Error("Failed to write container event", "error", err)
+		return 0, err
+	}
+
+	if err := writeAudit(ctx, tx, dto.EventContainerCreated, nil, container); err != nil {
+		tx.Rollback()
+		r.logger.Error("Failed to write audit event", "error", err)
+		return 0, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		r.logger.Error("Failed to commit transaction", "error", err)
 		return 0, fmt.Errorf("failed to commit transaction: %w", err)
@@ -101,11 +183,34 @@ func (r *containerRepository) CreateManyContainers(ctx context.Context, containe
 	var failedContainers []model.Container
 
 	for _, container := range containers {
+		// A failed statement aborts the whole Postgres transaction, so each row
+		// gets its own savepoint to roll back to.
+		tx.SavePoint("import_row")
+		if err := enforceQuota(tx, container.OwnerID, container.OwnerRole, QuotaForCreate(container.Spec)); err != nil {
+			tx.RollbackTo("import_row")
+			failedContainers = append(failedContainers, container)
+			r.logger.Warn("Imported container exceeds quota", "ownerID", container.OwnerID, "container_id", container.ContainerID, "error", err)
+			continue
+		}
 		if err := tx.Create(&container).Error; err != nil {
+			tx.RollbackTo("import_row")
 			failedContainers = append(failedContainers, container)
 			r.logger.Error("Failed to create container", "error", err, "container_id", container.ContainerID)
 			continue
 		}
+		if err := writeOutbox(ctx, tx, dto.EventContainerImported, nil, &container); err != nil {
+			tx.RollbackTo("import_row")
+			failedContainers = append(failedContainers, container)
+			r.logger.Error("Failed to write container event", "error", err, "container_id", container.ContainerID)
+			continue
+		}
+
+		if err := writeAudit(ctx, tx, dto.EventContainerImported, nil, &container); err != nil {
+			tx.RollbackTo("import_row")
+			failedContainers = append(failedContainers, container)
+			r.logger.Error("Failed to write audit event", "error", err, "container_id", container.ContainerID)
+			continue
+		}
 		createdContainers = append(createdContainers, container)
 	}
 
@@ -125,7 +230,7 @@ func (r *containerRepository) CreateManyContainers(ctx context.Context, containe
 }
 
 func (r *containerRepository) ViewAllContainers(ctx context.Context, containerFilter *dto.ContainerFilter, from, to int, sortBy string, sortOrder string) (int64, []model.Container, error) {
-	query := r.db.WithContext(ctx).Model(&model.Container{})
+	query := scopePolicy(ctx, scopeContainers(ctx, r.db.WithContext(ctx).Model(&model.Container{})))
 
 	if containerFilter != nil {
 		if containerFilter.ContainerID != "" {
@@ -191,7 +296,20 @@ func (r *containerRepository) ViewAllContainers(ctx context.Context, containerFi
 	return totalContainers, containers, nil
 }
 
+// UpdateContainer applies a requested change. Starting an inactive container
+// is checked against the owner's quota.
 func (r *containerRepository) UpdateContainer(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Container, error) {
+	return r.updateContainer(ctx, id, updateData, true)
+}
+
+// UpdateObservedContainer records a change Docker reports, such as a
+// container started outside the service. It already happened, so it is not
+// checked against the quota.
+func (r *containerRepository) UpdateObservedContainer(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Container, error) {
+	return r.updateContainer(ctx, id, updateData, false)
+}
+
+func (r *containerRepository) updateContainer(ctx context.Context, id uint, updateData map[string]interface{}, requested bool) (*model.Container, error) {
 	tx := r.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		r.logger.Error("Failed to begin transaction", "error", tx.Error)
@@ -206,19 +324,47 @@ func (r *containerRepository) UpdateContainer(ctx context.Context, id uint, upda
 		}
 	}()
 
+	// The row is locked before the quota, in the same order as
+	// UpdateContainerStatus, so the two cannot deadlock.
 	var container model.Container
-	if err := tx.First(&container, id).Error; err != nil {
+	if err := scopeContainers(ctx, tx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&container, id).Error; err != nil {
 		tx.Rollback()
 		r.logger.Error("Container not found", "id", id, "error", err)
 		return nil, fmt.Errorf("container not found: %w", err)
 	}
 
+	// A new image recreates and starts the container, whatever status it
+	// ends up in.
+	status, _ := updateData["status"].(string)
+	image, _ := updateData["image_name"].(string)
+	starts := IsActiveStatus(status) || (image != "" && image != container.ImageName)
+	if requested && starts && !IsActiveStatus(container.Status) {
+		if err := enforceQuota(tx, container.OwnerID, container.OwnerRole, QuotaForStart(container.Spec)); err != nil {
+			tx.Rollback()
+			r.logger.Warn("Starting container exceeds quota", "id", id, "ownerID", container.OwnerID, "error", err)
+			return nil, err
+		}
+	}
+
+	before := container
 	if err := tx.Model(&container).Updates(updateData).Error; err != nil {
 		tx.Rollback()
 		r.logger.Error("Failed to update container", "id", id, "error", err)
 		return nil, fmt.Errorf("failed to update container: %w", err)
 	}
 
+	if err := writeOutbox(ctx, tx, updateEventType(&before, &container), &before, &container); err != nil {
+		tx.Rollback()
+		r.logger.Error("Failed to write container event", "id", id, "error", err)
+		return nil, err
+	}
+
+	if err := writeAudit(ctx, tx, updateEventType(&before, &container), &before, &container); err != nil {
+		tx.Rollback()
+		r.logger.Error("Failed to write audit event", "id", id, "error", err)
+		return nil, err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		r.logger.Error("Failed to commit transaction", "error", err)
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
@@ -228,6 +374,111 @@ func (r *containerRepository) UpdateContainer(ctx context.Context, id uint, upda
 	return &container, nil
 }
 
+// UpdateContainerStatus sets the status of a container observed at the given
+// time. The update is skipped when a newer status has already been applied,
+// so a delayed message cannot overwrite a more recent state; see
+// IsStaleStatus. The returned bool reports whether the status was applied.
+// The status is a requested one, so activating an inactive container is
+// checked against the owner's quota under the same lock as other starts.
+func (r *containerRepository) UpdateContainerStatus(ctx context.Context, id uint, status string, at time.Time, origin dto.StatusOrigin) (*model.Container, bool, error) {
+	return r.updateContainerStatus(ctx, id, status, at, origin, true)
+}
+
+// UpdateObservedStatus is UpdateContainerStatus for a status Docker reports.
+// The container is already in that state, so the quota is not checked.
+func (r *containerRepository) UpdateObservedStatus(ctx context.Context, id uint, status string, at time.Time, origin dto.StatusOrigin) (*model.Container, bool, error) {
+	return r.updateContainerStatus(ctx, id, status, at, origin, false)
+}
+
+func (r *containerRepository) updateContainerStatus(ctx context.Context, id uint, status string, at time.Time, origin dto.StatusOrigin, requested bool) (*model.Container, bool, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		r.logger.Error("Failed to begin transaction", "error", tx.Error)
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			tx.Rollback()
+			r.logger.Error("Recovered from panic in UpdateContainerStatus", "error", rec)
+			panic(rec)
+		}
+	}()
+
+	var container model.Container
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&container, id).Error; err != nil {
+		tx.Rollback()
+		r.logger.Error("Container not found", "id", id, "error", err)
+		return nil, false, fmt.Errorf("container not found: %w", err)
+	}
+
+	at = at.UTC()
+	if IsStaleStatus(&container, at, origin) {
+		tx.Rollback()
+		r.logger.Info("Ignoring stale container status", "id", id, "status", status, "at", at, "lastStatusAt", container.LastStatusAt,
+			"producer", origin.Producer, "sequence", origin.Sequence, "lastSequence", container.LastStatusSequence)
+		return &container, false, nil
+	}
+
+	if requested && IsActiveStatus(status) && !IsActiveStatus(container.Status) {
+		if err := enforceQuota(tx, container.OwnerID, container.OwnerRole, QuotaForStart(container.Spec)); err != nil {
+			tx.Rollback()
+			r.logger.Warn("Starting container exceeds quota", "id", id, "ownerID", container.OwnerID, "error", err)
+			return nil, false, err
+		}
+	}
+
+	before := container
+	updateData := map[string]interface{}{
+		"status":         status,
+		"last_status_at": at,
+	}
+	if origin.Ordered() {
+		updateData["last_status_producer"] = origin.Producer
+		updateData["last_status_sequence"] = origin.Sequence
+	}
+	if err := tx.Model(&container).Updates(updateData).Error; err != nil {
+		tx.Rollback()
+		r.logger.Error("Failed to update container status", "id", id, "error", err)
+		return nil, false, fmt.Errorf("failed to update container status: %w", err)
+	}
+
+	if before.Status != container.Status {
+		if err := writeOutbox(ctx, tx, dto.EventContainerStatusChanged, &before, &container); err != nil {
+			tx.Rollback()
+			r.logger.Error("Failed to write container event", "id", id, "error", err)
+			return nil, false, err
+		}
+
+		if err := writeAudit(ctx, tx, dto.EventContainerStatusChanged, &before, &container); err != nil {
+			tx.Rollback()
+			r.logger.Error("Failed to write audit event", "id", id, "error", err)
+			return nil, false, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		r.logger.Error("Failed to commit transaction", "error", err)
+		return nil, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.logger.Info("Container status updated successfully", "id", id, "status", status, "at", at)
+	return &container, true, nil
+}
+
+// CheckQuota reports whether add still fits the quota of ownerID, or else of
+// ownerRole. It does not reserve anything; the write that follows enforces
+// the quota again.
+func (r *containerRepository) CheckQuota(ctx context.Context, ownerID uint, ownerRole string, add dto.QuotaUsage) error {
+	if err := checkQuota(r.db.WithContext(ctx), ownerID, ownerRole, add); err != nil {
+		if !errors.Is(err, ErrQuotaExceeded) {
+			r.logger.Error("Failed to check quota", "ownerID", ownerID, "error", err)
+		}
+		return err
+	}
+	return nil
+}
+
 func (r *containerRepository) DeleteContainer(ctx context.Context, id uint) error {
 	tx := r.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
@@ -244,7 +495,7 @@ func (r *containerRepository) DeleteContainer(ctx context.Context, id uint) erro
 	}()
 
 	var container model.Container
-	if err := tx.First(&container, id).Error; err != nil {
+	if err := scopeContainers(ctx, tx).First(&container, id).Error; err != nil {
 		tx.Rollback()
 		r.logger.Error("Container not found", "id", id, "error", err)
 		return fmt.Errorf("container not found: %w", err)
@@ -256,6 +507,18 @@ func (r *containerRepository) DeleteContainer(ctx context.Context, id uint) erro
 		return fmt.Errorf("failed to delete container: %w", err)
 	}
 
+	if err := writeOutbox(ctx, tx, dto.EventContainerDeleted, &container, nil); err != nil {
+		tx.Rollback()
+		r.logger.Error("Failed to write container event", "id", id, "error", err)
+		return err
+	}
+
+	if err := writeAudit(ctx, tx, dto.EventContainerDeleted, &container, nil); err != nil {
+		tx.Rollback()
+		r.logger.Error("Failed to write audit event", "id", id, "error", err)
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		r.logger.Error("Failed to commit transaction", "error", err)
 		return fmt.Errorf("failed to commit transaction: %w", err)
@@ -265,9 +528,15 @@ func (r *containerRepository) DeleteContainer(ctx context.Context, id uint) erro
 	return nil
 }
 
+// IsNotFound reports whether err means that a container does not exist or
+// is not visible to the caller.
+func IsNotFound(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
 func (r *containerRepository) GetContainerByID(ctx context.Context, id uint) (*model.Container, error) {
 	var container model.Container
-	if err := r.db.WithContext(ctx).First(&container, id).Error; err != nil {
+	if err := scopeContainers(ctx, r.db.WithContext(ctx)).First(&container, id).Error; err != nil {
 		r.logger.Error("Container not found", "id", id, "error", err)
 		return nil, fmt.Errorf("container not found: %w", err)
 	}
@@ -276,8 +545,28 @@ func (r *containerRepository) GetContainerByID(ctx context.Context, id uint) (*m
 	return &container, nil
 }
 
+func (r *containerRepository) GetContainerByContainerID(ctx context.Context, containerID string) (*model.Container, error) {
+	var container model.Container
+	if err := r.db.WithContext(ctx).Where("container_id = ?", containerID).First(&container).Error; err != nil {
+		r.logger.Error("Container not found", "containerID", containerID, "error", err)
+		return nil, fmt.Errorf("container not found: %w", err)
+	}
+
+	return &container, nil
+}
+
+func (r *containerRepository) ListAllContainers(ctx context.Context) ([]model.Container, error) {
+	var containers []model.Container
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&containers).Error; err != nil {
+		r.logger.Error("Failed to list containers", "error", err)
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	return containers, nil
+}
+
 func (r *containerRepository) GetContainerInfo(ctx context.Context) ([]dto.ContainerName, error) {
-	query := r.db.WithContext(ctx).Model(&model.Container{}).Select("id, container_name")
+	query := scopePolicy(ctx, scopeContainers(ctx, r.db.WithContext(ctx).Model(&model.Container{}))).Select("id, container_name")
 
 	var containerNames []dto.ContainerName
 	if err := query.Find(&containerNames).Error; err != nil {
@@ -289,8 +578,8 @@ func (r *containerRepository) GetContainerInfo(ctx context.Context) ([]dto.Conta
 	return containerNames, nil
 }
 
-func (r *containerRepository) AddContainerStatus(ctx context.Context, id uint, status string) error {
-	if status != StatusRunning && status != StatusStopped {
+func (r *containerRepository) AddContainerStatus(ctx context.Context, id uint, status string, timestamp time.Time) error {
+	if !IsValidStatus(status) {
 		r.logger.Error("Invalid status provided", "status", status)
 		return fmt.Errorf("invalid status: %s", status)
 	}
@@ -298,20 +587,56 @@ func (r *containerRepository) AddContainerStatus(ctx context.Context, id uint, s
 	doc := map[string]interface{}{
 		"id":        id,
 		"status":    status,
-		"timestamp": time.Now().UTC().Format(time.RFC3339),
+		"timestamp": timestamp.UTC().Format(time.RFC3339Nano),
+	}
+
+	// The document ID is derived from the observation itself, so replaying
+	// the same status update overwrites the earlier document instead of
+	// adding a duplicate that would be counted twice.
+	docID := fmt.Sprintf("%d-%s-%d", id, status, timestamp.UnixNano())
+	if err := r.indexStatusDocument(ctx, docID, doc); err != nil {
+		return err
+	}
+
+	r.logger.Info("Container status added successfully", "id", id, "status", status)
+	return nil
+}
+
+func (r *containerRepository) RecordStatusDrift(ctx context.Context, id uint, previousStatus, status, reason string) error {
+	doc := map[string]interface{}{
+		"id":              id,
+		"status":          status,
+		"previous_status": previousStatus,
+		"event":           "drift",
+		"reason":          reason,
+		"timestamp":       time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if err := r.indexStatusDocument(ctx, "", doc); err != nil {
+		return err
 	}
 
+	r.logger.Info("Container status drift recorded", "id", id, "previousStatus", previousStatus, "status", status)
+	return nil
+}
+
+func (r *containerRepository) indexStatusDocument(ctx context.Context, docID string, doc map[string]interface{}) error {
+	return r.indexDocument(ctx, containerStatusIndex, docID, doc)
+}
+
+func (r *containerRepository) indexDocument(ctx context.Context, index, docID string, doc map[string]interface{}) error {
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(doc); err != nil {
 		r.logger.Error("Failed to encode document for Elasticsearch", "error", err)
 		return fmt.Errorf("failed to encode document for Elasticsearch: %w", err)
 	}
 
-	res, err := r.es.Index(
-		"container_status",
-		&buf,
-		r.es.Index.WithContext(ctx),
-	)
+	opts := []func(*esapi.IndexRequest){r.es.Index.WithContext(ctx)}
+	if docID != "" {
+		opts = append(opts, r.es.Index.WithDocumentID(docID))
+	}
+
+	res, err := r.es.Index(index, &buf, opts...)
 	if err != nil {
 		r.logger.Error("Failed to index document in Elasticsearch", "error", err)
 		return fmt.Errorf("failed to index document in Elasticsearch: %w", err)
@@ -324,13 +649,12 @@ func (r *containerRepository) AddContainerStatus(ctx context.Context, id uint, s
 		return fmt.Errorf("elasticsearch response error: %s", res.Status())
 	}
 
-	r.logger.Info("Container status added successfully", "id", id, "status", status)
 	return nil
 }
 
 func (r *containerRepository) GetNumContainers(ctx context.Context) (int64, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&model.Container{}).Count(&count).Error; err != nil {
+	if err := scopePolicy(ctx, scopeContainers(ctx, r.db.WithContext(ctx).Model(&model.Container{}))).Count(&count).Error; err != nil {
 		r.logger.Error("Failed to count containers", "error", err)
 		return 0, fmt.Errorf("failed to count containers: %w", err)
 	}
@@ -341,7 +665,7 @@ func (r *containerRepository) GetNumContainers(ctx context.Context) (int64, erro
 
 func (r *containerRepository) GetNumRunningContainers(ctx context.Context) (int64, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&model.Container{}).Where("status = ?", StatusRunning).Count(&count).Error; err != nil {
+	if err := scopePolicy(ctx, scopeContainers(ctx, r.db.WithContext(ctx).Model(&model.Container{}))).Where("status = ?", StatusRunning).Count(&count).Error; err != nil {
 		r.logger.Error("Failed to count running containers", "error", err)
 		return 0, fmt.Errorf("failed to count running containers: %w", err)
 	}
@@ -350,12 +674,25 @@ func (r *containerRepository) GetNumRunningContainers(ctx context.Context) (int6
 	return count, nil
 }
 
-func (r *containerRepository) GetContainerUptimeRatio(ctx context.Context, startTime, endTime time.Time) (float64, error) {
+func (r *containerRepository) GetContainerUptimeRatio(ctx context.Context, startTime, endTime time.Time, mode string) (float64, error) {
 	if startTime.After(endTime) {
 		r.logger.Error("Start time cannot be after end time", "startTime", startTime, "endTime", endTime)
 		return 0, fmt.Errorf("start time cannot be after end time")
 	}
 
+	switch mode {
+	case dto.UptimeModeSample:
+		return r.getSampleUptimeRatio(ctx, startTime, endTime)
+	case dto.UptimeModeTransition, "":
+		return r.getTransitionUptimeRatio(ctx, startTime, endTime)
+	default:
+		return 0, fmt.Errorf("invalid uptime mode: %s", mode)
+	}
+}
+
+// getSampleUptimeRatio treats every status document as one equally weighted
+// sample and averages the share of running samples per container.
+func (r *containerRepository) getSampleUptimeRatio(ctx context.Context, startTime, endTime time.Time) (float64, error) {
 	query := map[string]interface{}{
 		"size": 0,
 		"query": map[string]interface{}{
@@ -404,6 +741,10 @@ func (r *containerRepository) GetContainerUptimeRatio(ctx context.Context, start
 		},
 	}
 
+	if err := r.scopeStatusQuery(ctx, query); err != nil {
+		return 0, err
+	}
+
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(query); err != nil {
 		r.logger.Error("Failed to encode query for Elasticsearch", "error", err, "startTime", startTime, "endTime", endTime)
@@ -412,7 +753,7 @@ func (r *containerRepository) GetContainerUptimeRatio(ctx context.Context, start
 
 	res, err := r.es.Search(
 		r.es.Search.WithContext(ctx),
-		r.es.Search.WithIndex("container_status"),
+		r.es.Search.WithIndex(containerStatusIndex),
 		r.es.Search.WithBody(&buf),
 		r.es.Search.WithTrackTotalHits(true),
 	)
@@ -459,12 +800,25 @@ func (r *containerRepository) GetContainerUptimeRatio(ctx context.Context, start
 	return ratio, nil
 }
 
-func (r *containerRepository) GetContainerUptimeDuration(ctx context.Context, startTime, endTime time.Time) (*dto.UptimeDetails, error) {
+func (r *containerRepository) GetContainerUptimeDuration(ctx context.Context, startTime, endTime time.Time, mode string) (*dto.UptimeDetails, error) {
 	if startTime.After(endTime) {
 		r.logger.Error("Start time cannot be after end time", "startTime", startTime, "endTime", endTime)
 		return nil, fmt.Errorf("start time cannot be after end time")
 	}
 
+	switch mode {
+	case dto.UptimeModeSample:
+		return r.getSampleUptimeDuration(ctx, startTime, endTime)
+	case dto.UptimeModeTransition, "":
+		return r.getTransitionUptimeDuration(ctx, startTime, endTime)
+	default:
+		return nil, fmt.Errorf("invalid uptime mode: %s", mode)
+	}
+}
+
+// getSampleUptimeDuration assumes the status reporter sends one running
+// document per minute and converts the count of running documents to time.
+func (r *containerRepository) getSampleUptimeDuration(ctx context.Context, startTime, endTime time.Time) (*dto.UptimeDetails, error) {
 	query := map[string]interface{}{
 		"size": 0,
 		"query": map[string]interface{}{
@@ -503,6 +857,10 @@ func (r *containerRepository) GetContainerUptimeDuration(ctx context.Context, st
 		},
 	}
 
+	if err := r.scopeStatusQuery(ctx, query); err != nil {
+		return nil, err
+	}
+
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(query); err != nil {
 		r.logger.Error("Failed to encode query for Elasticsearch", "error", err)
@@ -511,7 +869,7 @@ func (r *containerRepository) GetContainerUptimeDuration(ctx context.Context, st
 
 	res, err := r.es.Search(
 		r.es.Search.WithContext(ctx),
-		r.es.Search.WithIndex("container_status"),
+		r.es.Search.WithIndex(containerStatusIndex),
 		r.es.Search.WithBody(&buf),
 		r.es.Search.WithTrackTotalHits(true),
 	)
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"testing"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"time"
+)
+
+func TestIsStaleStatus(t *testing.T) {
+	last := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	container := &model.Container{
+		LastStatusAt:       &last,
+		LastStatusProducer: "agent-1",
+		LastStatusSequence: 42,
+	}
+
+	tests := []struct {
+		name   string
+		at     time.Time
+		origin dto.StatusOrigin
+		want   bool
+	}{
+		{"older report", last.Add(-time.Second), dto.StatusOrigin{}, true},
+		{"newer report", last.Add(time.Second), dto.StatusOrigin{}, false},
+		{"same time without sequence", last, dto.StatusOrigin{}, false},
+		{"same time, next sequence", last, dto.StatusOrigin{Producer: "agent-1", Sequence: 43}, false},
+		{"same time, same sequence", last, dto.StatusOrigin{Producer: "agent-1", Sequence: 42}, true},
+		{"newer time, lower sequence", last.Add(time.Second), dto.StatusOrigin{Producer: "agent-1", Sequence: 41}, true},
+		{"other producer, lower sequence", last.Add(time.Second), dto.StatusOrigin{Producer: "agent-2", Sequence: 1}, false},
+		{"older report, higher sequence", last.Add(-time.Second), dto.StatusOrigin{Producer: "agent-1", Sequence: 43}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsStaleStatus(container, tt.at, tt.origin); got != tt.want {
+				t.Errorf("IsStaleStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if IsStaleStatus(&model.Container{}, last, dto.StatusOrigin{Producer: "agent-1", Sequence: 1}) {
+		t.Error("first report of a container is stale")
+	}
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/pkg/authz"
+	"thanhnt208/container-adm-service/utils"
+
+	"gorm.io/gorm"
+)
+
+// scopeContainers limits a container query to the rows the caller in ctx may
+// access. Members of a tenant share its containers; users without a tenant
+// only see the containers they own. Admins and internal callers without
+// claims, such as the Kafka worker, are not restricted.
+func scopeContainers(ctx context.Context, db *gorm.DB) *gorm.DB {
+	claims, ok := utils.ClaimsFromContext(ctx)
+	if !ok || utils.IsAdmin(claims) {
+		return db
+	}
+	if claims.TenantID != "" {
+		return db.Where("tenant_id = ?", claims.TenantID)
+	}
+	return db.Where("owner_id = ?", claims.UserID)
+}
+
+// scopePolicy limits a container query of a collection action to the policy
+// scope it was authorized for, when ctx carries one; see
+// authz.Engine.EvaluateCollection.
+func scopePolicy(ctx context.Context, db *gorm.DB) *gorm.DB {
+	scope, ok := authz.ScopeFromContext(ctx)
+	if !ok || scope.Unrestricted() {
+		return db
+	}
+	if !scope.All {
+		query, args := anyConditionsSQL(scope.Allow, scope.Subject)
+		db = db.Where(query, args...)
+	}
+	if len(scope.Deny) > 0 {
+		query, args := anyConditionsSQL(scope.Deny, scope.Subject)
+		db = db.Where("NOT "+query, args...)
+	}
+	return db
+}
+
+// anyConditionsSQL is the SQL counterpart of authz.Conditions: rows meeting
+// all conditions of any of the sets.
+func anyConditionsSQL(sets []authz.Conditions, subject authz.Subject) (string, []interface{}) {
+	if len(sets) == 0 {
+		return "FALSE", nil
+	}
+
+	var clauses []string
+	var args []interface{}
+	for _, conditions := range sets {
+		var parts []string
+		if conditions.Owner == authz.Self {
+			parts = append(parts, "owner_id = ?")
+			args = append(args, subject.UserID)
+		}
+		if conditions.Tenant == authz.Self {
+			if subject.TenantID == "" {
+				parts = append(parts, "FALSE")
+			} else {
+				parts = append(parts, "tenant_id = ?")
+				args = append(args, subject.TenantID)
+			}
+		}
+		keys := make([]string, 0, len(conditions.Labels))
+		for key := range conditions.Labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			// A missing label compares as empty, as in authz.
+			parts = append(parts, "COALESCE(spec->'labels'->>?, '') = ?")
+			args = append(args, key, conditions.Labels[key])
+		}
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+// accessibleContainerIDs returns the IDs the caller may access, or nil and
+// false when the caller is not restricted.
+func (r *containerRepository) accessibleContainerIDs(ctx context.Context) ([]uint, bool, error) {
+	claims, ok := utils.ClaimsFromContext(ctx)
+	scope, scoped := authz.ScopeFromContext(ctx)
+	if (!ok || utils.IsAdmin(claims)) && (!scoped || scope.Unrestricted()) {
+		return nil, false, nil
+	}
+
+	ids := []uint{}
+	query := scopePolicy(ctx, scopeContainers(ctx, r.db.WithContext(ctx).Model(&model.Container{})))
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		r.logger.Error("Failed to list accessible containers", "error", err)
+		return nil, false, fmt.Errorf("failed to list accessible containers: %w", err)
+	}
+	return ids, true, nil
+}
+
+// scopeStatusQuery restricts a container_status search to the containers
+// the caller may access.
+func (r *containerRepository) scopeStatusQuery(ctx context.Context, query map[string]interface{}) error {
+	ids, scoped, err := r.accessibleContainerIDs(ctx)
+	if err != nil || !scoped {
+		return err
+	}
+
+	filters := []interface{}{
+		map[string]interface{}{"terms": map[string]interface{}{"id": ids}},
+	}
+	if original, ok := query["query"]; ok {
+		filters = append(filters, original)
+	}
+	query["query"] = map[string]interface{}{
+		"bool": map[string]interface{}{"filter": filters},
+	}
+	return nil
+}
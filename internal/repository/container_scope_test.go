@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"thanhnt208/container-adm-service/pkg/authz"
+)
+
+func TestAnyConditionsSQL(t *testing.T) {
+	subject := authz.Subject{UserID: 7, TenantID: "acme"}
+
+	tests := []struct {
+		name     string
+		sets     []authz.Conditions
+		subject  authz.Subject
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:    "no sets",
+			subject: subject,
+			wantSQL: "FALSE",
+		},
+		{
+			name:     "owner and tenant",
+			sets:     []authz.Conditions{{Owner: authz.Self, Tenant: authz.Self}},
+			subject:  subject,
+			wantSQL:  "((owner_id = ? AND tenant_id = ?))",
+			wantArgs: []interface{}{uint(7), "acme"},
+		},
+		{
+			name:     "tenant without a subject tenant",
+			sets:     []authz.Conditions{{Tenant: authz.Self}},
+			subject:  authz.Subject{UserID: 7},
+			wantSQL:  "((FALSE))",
+			wantArgs: nil,
+		},
+		{
+			name: "labels in key order, sets joined",
+			sets: []authz.Conditions{
+				{Labels: map[string]string{"tier": "web", "env": "dev"}},
+				{Owner: authz.Self},
+			},
+			subject:  subject,
+			wantSQL:  "((COALESCE(spec->'labels'->>?, '') = ? AND COALESCE(spec->'labels'->>?, '') = ?) OR (owner_id = ?))",
+			wantArgs: []interface{}{"env", "dev", "tier", "web", uint(7)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := anyConditionsSQL(tt.sets, tt.subject)
+			if sql != tt.wantSQL {
+				t.Errorf("sql = %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"thanhnt208/container-adm-service/internal/dto"
+	"time"
+)
+
+const statusHistoryPageSize = 1000
+
+// statusDoc is a single document of the container_status index.
+type statusDoc struct {
+	ID        uint
+	Status    string
+	Timestamp time.Time
+
+	sort []interface{}
+}
+
+// uptimeSpan is the running time of a container and the part of the window
+// during which its state was known at all.
+type uptimeSpan struct {
+	Up       time.Duration
+	Observed time.Duration
+}
+
+func (r *containerRepository) getTransitionUptimeRatio(ctx context.Context, startTime, endTime time.Time) (float64, error) {
+	spans, err := r.getTransitionUptimeSpans(ctx, startTime, endTime)
+	if err != nil {
+		return 0, err
+	}
+
+	var sum float64
+	var count int
+	for _, span := range spans {
+		if span.Observed <= 0 {
+			continue
+		}
+		sum += float64(span.Up) / float64(span.Observed)
+		count++
+	}
+
+	if count == 0 {
+		r.logger.Warn("No status history found for uptime ratio", "startTime", startTime, "endTime", endTime)
+		return 0, fmt.Errorf("no status history found between %s and %s", startTime.UTC().Format(time.RFC3339), endTime.UTC().Format(time.RFC3339))
+	}
+
+	return sum / float64(count), nil
+}
+
+func (r *containerRepository) getTransitionUptimeDuration(ctx context.Context, startTime, endTime time.Time) (*dto.UptimeDetails, error) {
+	spans, err := r.getTransitionUptimeSpans(ctx, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	perContainerUptime := make(map[string]time.Duration)
+	var totalUptime time.Duration
+	for id, span := range spans {
+		if span.Up > 0 {
+			perContainerUptime[strconv.FormatUint(uint64(id), 10)] = span.Up
+			totalUptime += span.Up
+		}
+	}
+
+	r.logger.Info("Container uptime calculated successfully using transition method",
+		"startTime", startTime,
+		"endTime", endTime,
+		"totalUptime", totalUptime,
+		"containerCount", len(perContainerUptime))
+
+	return &dto.UptimeDetails{
+		TotalUptime:        totalUptime,
+		PerContainerUptime: perContainerUptime,
+	}, nil
+}
+
+func (r *containerRepository) getTransitionUptimeSpans(ctx context.Context, startTime, endTime time.Time) (map[uint]uptimeSpan, error) {
+	initial, err := r.getStatusesBefore(ctx, startTime)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := r.getStatusDocsBetween(ctx, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	// Time after "now" has not happened yet and must not count as uptime.
+	if now := time.Now(); endTime.After(now) {
+		endTime = now
+	}
+
+	return computeTransitionUptime(initial, docs, startTime, endTime), nil
+}
+
+// computeTransitionUptime walks the status transitions of every container in
+// timestamp order and sums the intervals spent running, clipped to
+// [start, end]. initial holds the last status each container had before start,
+// which is carried into the window. docs must be sorted by timestamp.
+func computeTransitionUptime(initial map[uint]string, docs []statusDoc, start, end time.Time) map[uint]uptimeSpan {
+	type cursor struct {
+		status string
+		since  time.Time
+		known  time.Time
+	}
+
+	cursors := make(map[uint]*cursor, len(initial))
+	for id, status := range initial {
+		cursors[id] = &cursor{status: status, since: start, known: start}
+	}
+
+	spans := make(map[uint]uptimeSpan)
+	for _, doc := range docs {
+		ts := doc.Timestamp
+		if ts.Before(start) {
+			ts = start
+		}
+		if ts.After(end) {
+			continue
+		}
+
+		c, ok := cursors[doc.ID]
+		if !ok {
+			cursors[doc.ID] = &cursor{status: doc.Status, since: ts, known: ts}
+			continue
+		}
+
+		if c.status == StatusRunning {
+			span := spans[doc.ID]
+			span.Up += ts.Sub(c.since)
+			spans[doc.ID] = span
+		}
+		c.status = doc.Status
+		c.since = ts
+	}
+
+	for id, c := range cursors {
+		span := spans[id]
+		if end.After(c.since) && c.status == StatusRunning {
+			span.Up += end.Sub(c.since)
+		}
+		if end.After(c.known) {
+			span.Observed = end.Sub(c.known)
+		}
+		spans[id] = span
+	}
+
+	return spans
+}
+
+// getStatusesBefore returns the most recent status of every container
+// recorded strictly before t.
+func (r *containerRepository) getStatusesBefore(ctx context.Context, t time.Time) (map[uint]string, error) {
+	query := map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"range": map[string]interface{}{
+				"timestamp": map[string]interface{}{
+					"lt": t.UTC().Format(time.RFC3339),
+				},
+			},
+		},
+		"aggs": map[string]interface{}{
+			"per_container": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": "id",
+					"size":  10000,
+				},
+				"aggs": map[string]interface{}{
+					"last_status": map[string]interface{}{
+						"top_hits": map[string]interface{}{
+							"size": 1,
+							"sort": []map[string]interface{}{
+								{"timestamp": map[string]interface{}{"order": "desc"}},
+							},
+							"_source": []string{"id", "status", "timestamp"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	body, err := r.searchStatusIndex(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses, err := parseLastStatuses(body)
+	if err != nil {
+		r.logger.Error("Failed to parse last statuses", "error", err)
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+// getStatusDocsBetween pages through every status document in [start, end]
+// in timestamp order using search_after.
+func (r *containerRepository) getStatusDocsBetween(ctx context.Context, startTime, endTime time.Time) ([]statusDoc, error) {
+	var docs []statusDoc
+	var searchAfter []interface{}
+
+	for {
+		query := map[string]interface{}{
+			"size": statusHistoryPageSize,
+			"query": map[string]interface{}{
+				"range": map[string]interface{}{
+					"timestamp": map[string]interface{}{
+						"gte": startTime.UTC().Format(time.RFC3339),
+						"lte": endTime.UTC().Format(time.RFC3339),
+					},
+				},
+			},
+			"sort":    statusHistorySort("asc"),
+			"_source": []string{"id", "status", "timestamp"},
+		}
+		if searchAfter != nil {
+			query["search_after"] = searchAfter
+		}
+
+		body, err := r.searchStatusIndex(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		page, lastSort, err := parseStatusHits(body)
+		if err != nil {
+			r.logger.Error("Failed to parse status history", "error", err)
+			return nil, err
+		}
+
+		docs = append(docs, page...)
+		if len(page) < statusHistoryPageSize || lastSort == nil {
+			return docs, nil
+		}
+		searchAfter = lastSort
+	}
+}
+
+// statusHistorySort orders status documents by time. Documents that tie on
+// every key are identical transitions, so skipping one of them at a page
+// boundary does not change the result.
+func statusHistorySort(order string) []map[string]interface{} {
+	return []map[string]interface{}{
+		{"timestamp": map[string]interface{}{"order": order}},
+		{"id": map[string]interface{}{"order": order}},
+		{"status.keyword": map[string]interface{}{"order": order}},
+	}
+}
+
+func (r *containerRepository) searchStatusIndex(ctx context.Context, query map[string]interface{}) ([]byte, error) {
+	if err := r.scopeStatusQuery(ctx, query); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		r.logger.Error("Failed to encode query for Elasticsearch", "error", err)
+		return nil, fmt.Errorf("failed to encode query: %w", err)
+	}
+
+	res, err := r.es.Search(
+		r.es.Search.WithContext(ctx),
+		r.es.Search.WithIndex(containerStatusIndex),
+		r.es.Search.WithBody(&buf),
+	)
+	if err != nil {
+		r.logger.Error("Failed to execute search", "error", err)
+		return nil, fmt.Errorf("failed to execute search: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if res.IsError() {
+		r.logger.Error("Elasticsearch error", "status", res.StatusCode, "body", string(body))
+		return nil, fmt.Errorf("elasticsearch error: %s", res.Status())
+	}
+
+	return body, nil
+}
+
+type statusSource struct {
+	ID        uint   `json:"id"`
+	Status    string `json:"status"`
+	Timestamp string `json:"timestamp"`
+}
+
+func (s statusSource) toDoc() (statusDoc, error) {
+	ts, err := time.Parse(time.RFC3339, s.Timestamp)
+	if err != nil {
+		return statusDoc{}, fmt.Errorf("invalid timestamp %q: %w", s.Timestamp, err)
+	}
+	return statusDoc{ID: s.ID, Status: s.Status, Timestamp: ts}, nil
+}
+
+type statusHit struct {
+	Source statusSource  `json:"_source"`
+	Sort   []interface{} `json:"sort"`
+}
+
+// parseStatusHits decodes a search response into status documents and
+// returns the sort values of the last hit for search_after paging.
+func parseStatusHits(body []byte) ([]statusDoc, []interface{}, error) {
+	var response struct {
+		Hits struct {
+			Hits []statusHit `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	docs := make([]statusDoc, 0, len(response.Hits.Hits))
+	for _, hit := range response.Hits.Hits {
+		doc, err := hit.Source.toDoc()
+		if err != nil {
+			return nil, nil, err
+		}
+		doc.sort = hit.Sort
+		docs = append(docs, doc)
+	}
+
+	var lastSort []interface{}
+	if n := len(response.Hits.Hits); n > 0 {
+		lastSort = response.Hits.Hits[n-1].Sort
+	}
+	return docs, lastSort, nil
+}
+
+// parseLastStatuses decodes the per_container > last_status top_hits
+// aggregation into a map of container ID to status.
+func parseLastStatuses(body []byte) (map[uint]string, error) {
+	var response struct {
+		Aggregations *struct {
+			PerContainer struct {
+				Buckets []struct {
+					LastStatus struct {
+						Hits struct {
+							Hits []statusHit `json:"hits"`
+						} `json:"hits"`
+					} `json:"last_status"`
+				} `json:"buckets"`
+			} `json:"per_container"`
+		} `json:"aggregations"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if response.Aggregations == nil {
+		return nil, fmt.Errorf("invalid response format: missing aggregations")
+	}
+
+	statuses := make(map[uint]string)
+	for _, bucket := range response.Aggregations.PerContainer.Buckets {
+		if len(bucket.LastStatus.Hits.Hits) == 0 {
+			continue
+		}
+		source := bucket.LastStatus.Hits.Hits[0].Source
+		statuses[source.ID] = source.Status
+	}
+	return statuses, nil
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestComputeTransitionUptime(t *testing.T) {
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	tests := []struct {
+		name    string
+		initial map[uint]string
+		docs    []statusDoc
+		want    uptimeSpan
+	}{
+		{
+			name:    "running before the window and unchanged",
+			initial: map[uint]string{1: StatusRunning},
+			want:    uptimeSpan{Up: 2 * time.Hour, Observed: 2 * time.Hour},
+		},
+		{
+			name:    "stopped before the window and unchanged",
+			initial: map[uint]string{1: StatusStopped},
+			want:    uptimeSpan{Up: 0, Observed: 2 * time.Hour},
+		},
+		{
+			name:    "started and stopped inside the window",
+			initial: map[uint]string{1: StatusStopped},
+			docs: []statusDoc{
+				{ID: 1, Status: StatusRunning, Timestamp: at(30)},
+				{ID: 1, Status: StatusStopped, Timestamp: at(60)},
+			},
+			want: uptimeSpan{Up: 30 * time.Minute, Observed: 2 * time.Hour},
+		},
+		{
+			name: "first seen inside the window",
+			docs: []statusDoc{
+				{ID: 1, Status: StatusRunning, Timestamp: at(60)},
+			},
+			want: uptimeSpan{Up: time.Hour, Observed: time.Hour},
+		},
+		{
+			name: "transitions after the window are ignored",
+			docs: []statusDoc{
+				{ID: 1, Status: StatusRunning, Timestamp: at(90)},
+				{ID: 1, Status: StatusStopped, Timestamp: at(150)},
+			},
+			want: uptimeSpan{Up: 30 * time.Minute, Observed: 30 * time.Minute},
+		},
+		{
+			name: "transitions before the window are clipped to its start",
+			docs: []statusDoc{
+				{ID: 1, Status: StatusRunning, Timestamp: at(-60)},
+				{ID: 1, Status: StatusExited, Timestamp: at(45)},
+			},
+			want: uptimeSpan{Up: 45 * time.Minute, Observed: 2 * time.Hour},
+		},
+		{
+			name:    "paused time is not uptime",
+			initial: map[uint]string{1: StatusRunning},
+			docs: []statusDoc{
+				{ID: 1, Status: StatusPaused, Timestamp: at(20)},
+				{ID: 1, Status: StatusRunning, Timestamp: at(80)},
+			},
+			want: uptimeSpan{Up: 60 * time.Minute, Observed: 2 * time.Hour},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spans := computeTransitionUptime(tt.initial, tt.docs, start, end)
+			if got := spans[1]; got != tt.want {
+				t.Errorf("span = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeTransitionUptimeKeepsContainersApart(t *testing.T) {
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	spans := computeTransitionUptime(
+		map[uint]string{1: StatusRunning, 2: StatusStopped},
+		[]statusDoc{
+			{ID: 2, Status: StatusRunning, Timestamp: start.Add(15 * time.Minute)},
+			{ID: 1, Status: StatusStopped, Timestamp: start.Add(30 * time.Minute)},
+		},
+		start, end,
+	)
+
+	want := map[uint]uptimeSpan{
+		1: {Up: 30 * time.Minute, Observed: time.Hour},
+		2: {Up: 45 * time.Minute, Observed: time.Hour},
+	}
+	if !reflect.DeepEqual(spans, want) {
+		t.Errorf("spans = %+v, want %+v", spans, want)
+	}
+}
+
+func TestParseStatusHits(t *testing.T) {
+	body := []byte(`{
+		"took": 3,
+		"hits": {
+			"total": {"value": 2, "relation": "eq"},
+			"hits": [
+				{
+					"_index": "container_status",
+					"_id": "a",
+					"_source": {"id": 1, "status": "running", "timestamp": "2025-06-01T10:00:00Z"},
+					"sort": [1748772000000, 1, "running"]
+				},
+				{
+					"_index": "container_status",
+					"_id": "b",
+					"_source": {"id": 2, "status": "stopped", "timestamp": "2025-06-01T10:05:00Z"},
+					"sort": [1748772300000, 2, "stopped"]
+				}
+			]
+		}
+	}`)
+
+	docs, lastSort, err := parseStatusHits(body)
+	if err != nil {
+		t.Fatalf("parseStatusHits() error = %v", err)
+	}
+
+	want := []statusDoc{
+		{ID: 1, Status: StatusRunning, Timestamp: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)},
+		{ID: 2, Status: StatusStopped, Timestamp: time.Date(2025, 6, 1, 10, 5, 0, 0, time.UTC)},
+	}
+	if len(docs) != len(want) {
+		t.Fatalf("got %d docs, want %d", len(docs), len(want))
+	}
+	for i := range want {
+		if docs[i].ID != want[i].ID || docs[i].Status != want[i].Status || !docs[i].Timestamp.Equal(want[i].Timestamp) {
+			t.Errorf("doc %d = %+v, want %+v", i, docs[i], want[i])
+		}
+	}
+
+	wantSort := []interface{}{float64(1748772300000), float64(2), "stopped"}
+	if !reflect.DeepEqual(lastSort, wantSort) {
+		t.Errorf("lastSort = %v, want %v", lastSort, wantSort)
+	}
+}
+
+func TestParseStatusHitsEmpty(t *testing.T) {
+	docs, lastSort, err := parseStatusHits([]byte(`{"hits": {"total": {"value": 0}, "hits": []}}`))
+	if err != nil {
+		t.Fatalf("parseStatusHits() error = %v", err)
+	}
+	if len(docs) != 0 || lastSort != nil {
+		t.Errorf("got docs %v and lastSort %v, want none", docs, lastSort)
+	}
+}
+
+func TestParseStatusHitsErrors(t *testing.T) {
+	tests := map[string]string{
+		"malformed body":    `{"hits": `,
+		"invalid timestamp": `{"hits": {"hits": [{"_source": {"id": 1, "status": "running", "timestamp": "yesterday"}}]}}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := parseStatusHits([]byte(body)); err == nil {
+				t.Error("parseStatusHits() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestParseLastStatuses(t *testing.T) {
+	body := []byte(`{
+		"hits": {"total": {"value": 42}, "hits": []},
+		"aggregations": {
+			"per_container": {
+				"buckets": [
+					{
+						"key": 1,
+						"doc_count": 30,
+						"last_status": {"hits": {"hits": [
+							{"_source": {"id": 1, "status": "running", "timestamp": "2025-06-01T09:59:00Z"}}
+						]}}
+					},
+					{
+						"key": 2,
+						"doc_count": 12,
+						"last_status": {"hits": {"hits": [
+							{"_source": {"id": 2, "status": "exited", "timestamp": "2025-06-01T08:00:00Z"}}
+						]}}
+					},
+					{
+						"key": 3,
+						"doc_count": 0,
+						"last_status": {"hits": {"hits": []}}
+					}
+				]
+			}
+		}
+	}`)
+
+	statuses, err := parseLastStatuses(body)
+	if err != nil {
+		t.Fatalf("parseLastStatuses() error = %v", err)
+	}
+
+	want := map[uint]string{1: StatusRunning, 2: StatusExited}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+}
+
+func TestParseLastStatusesErrors(t *testing.T) {
+	tests := map[string]string{
+		"malformed body":       `{"aggregations": `,
+		"missing aggregations": `{"hits": {"hits": []}}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseLastStatuses([]byte(body)); err == nil {
+				t.Error("parseLastStatuses() error = nil, want an error")
+			}
+		})
+	}
+}
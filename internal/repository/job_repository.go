@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrJobFinished = errors.New("job already finished")
+
+type IJobRepository interface {
+	CreateJob(ctx context.Context, job *model.Job) error
+	GetJob(ctx context.Context, id string) (*model.Job, error)
+	ClaimJob(ctx context.Context) (*model.Job, error)
+	UpdateJobSteps(ctx context.Context, id string, steps []model.JobStep) (bool, error)
+	FinishJob(ctx context.Context, job *model.Job) error
+	CancelJob(ctx context.Context, id string) (*model.Job, error)
+	FailStaleJobs(ctx context.Context, before time.Time) (int64, error)
+}
+
+type jobRepository struct {
+	db     *gorm.DB
+	logger logger.ILogger
+}
+
+func NewJobRepository(db *gorm.DB, logger logger.ILogger) IJobRepository {
+	return &jobRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *jobRepository) CreateJob(ctx context.Context, job *model.Job) error {
+	if err := r.db.WithContext(ctx).Create(job).Error; err != nil {
+		r.logger.Error("Failed to create job", "type", job.Type, "error", err)
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	r.logger.Info("Job created successfully", "id", job.ID, "type", job.Type)
+	return nil
+}
+
+// GetJob returns the job if the caller in ctx may see it, or nil. Jobs carry
+// the owner and tenant of the caller that submitted them and are scoped like
+// containers.
+func (r *jobRepository) GetJob(ctx context.Context, id string) (*model.Job, error) {
+	var job model.Job
+	err := scopeContainers(ctx, r.db.WithContext(ctx).Model(&model.Job{})).Where("id = ?", id).First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error("Failed to retrieve job", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to retrieve job: %w", err)
+	}
+	return &job, nil
+}
+
+// ClaimJob marks the oldest queued job as running and returns it, or nil
+// when the queue is empty. Rows are locked with SKIP LOCKED so several
+// workers and instances can claim jobs side by side.
+func (r *jobRepository) ClaimJob(ctx context.Context) (*model.Job, error) {
+	var job *model.Job
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var jobs []model.Job
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", model.JobStatusQueued).
+			Order("created_at ASC").
+			Limit(1).
+			Find(&jobs).Error; err != nil {
+			return fmt.Errorf("failed to fetch queued job: %w", err)
+		}
+		if len(jobs) == 0 {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		if err := tx.Model(&model.Job{}).Where("id = ?", jobs[0].ID).Updates(map[string]interface{}{
+			"status":     model.JobStatusRunning,
+			"started_at": now,
+			"updated_at": now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to mark job as running: %w", err)
+		}
+
+		job = &jobs[0]
+		job.Status = model.JobStatusRunning
+		job.StartedAt = &now
+		job.UpdatedAt = now
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to claim job", "error", err)
+		return nil, err
+	}
+	return job, nil
+}
+
+// UpdateJobSteps stores the progress of a running job, which also tells
+// FailStaleJobs that its worker is alive. It reports whether the job was
+// asked to cancel.
+func (r *jobRepository) UpdateJobSteps(ctx context.Context, id string, steps []model.JobStep) (bool, error) {
+	raw, err := json.Marshal(steps)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode job steps: %w", err)
+	}
+
+	// RETURNING scans cancel_requested into job.
+	var job model.Job
+	if err := r.db.WithContext(ctx).Model(&job).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "cancel_requested"}}}).
+		Where("id = ? AND status = ?", id, model.JobStatusRunning).
+		Updates(map[string]interface{}{
+			"steps":      string(raw),
+			"updated_at": time.Now().UTC(),
+		}).Error; err != nil {
+		r.logger.Error("Failed to update job steps", "id", id, "error", err)
+		return false, fmt.Errorf("failed to update job steps: %w", err)
+	}
+
+	return job.CancelRequested, nil
+}
+
+// FinishJob stores the outcome of a running job. A job that was failed as
+// stale in the meantime keeps its stored outcome.
+func (r *jobRepository) FinishJob(ctx context.Context, job *model.Job) error {
+	steps, err := json.Marshal(job.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to encode job steps: %w", err)
+	}
+
+	now := time.Now().UTC()
+	updateData := map[string]interface{}{
+		"status":       job.Status,
+		"steps":        string(steps),
+		"error":        job.Error,
+		"container_id": job.ContainerID,
+		"finished_at":  now,
+		"updated_at":   now,
+	}
+	if job.Result != nil {
+		updateData["result"] = string(job.Result)
+	}
+
+	result := r.db.WithContext(ctx).Model(&model.Job{}).
+		Where("id = ? AND status = ?", job.ID, model.JobStatusRunning).
+		Updates(updateData)
+	if result.Error != nil {
+		r.logger.Error("Failed to finish job", "id", job.ID, "error", result.Error)
+		return fmt.Errorf("failed to finish job: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		r.logger.Warn("Job outcome not stored, job is no longer running", "id", job.ID, "type", job.Type, "status", job.Status)
+		return nil
+	}
+
+	job.FinishedAt = &now
+	job.UpdatedAt = now
+	r.logger.Info("Job finished", "id", job.ID, "type", job.Type, "status", job.Status)
+	return nil
+}
+
+// CancelJob cancels a queued job right away and asks the worker of a running
+// job to stop it. It returns nil when the caller cannot see the job and
+// ErrJobFinished when it already ended.
+func (r *jobRepository) CancelJob(ctx context.Context, id string) (*model.Job, error) {
+	var job *model.Job
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var jobs []model.Job
+		if err := scopeContainers(ctx, tx.Model(&model.Job{})).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			Find(&jobs).Error; err != nil {
+			return fmt.Errorf("failed to retrieve job: %w", err)
+		}
+		if len(jobs) == 0 {
+			return nil
+		}
+		job = &jobs[0]
+		if job.IsFinished() {
+			return ErrJobFinished
+		}
+
+		now := time.Now().UTC()
+		updateData := map[string]interface{}{
+			"cancel_requested": true,
+			"updated_at":       now,
+		}
+		if job.Status == model.JobStatusQueued {
+			updateData["status"] = model.JobStatusCancelled
+			updateData["finished_at"] = now
+			job.Status = model.JobStatusCancelled
+			job.FinishedAt = &now
+		}
+		if err := tx.Model(&model.Job{}).Where("id = ?", id).Updates(updateData).Error; err != nil {
+			return fmt.Errorf("failed to cancel job: %w", err)
+		}
+
+		job.CancelRequested = true
+		job.UpdatedAt = now
+		return nil
+	})
+	if errors.Is(err, ErrJobFinished) {
+		return job, err
+	}
+	if err != nil {
+		r.logger.Error("Failed to cancel job", "id", id, "error", err)
+		return nil, err
+	}
+	if job != nil {
+		r.logger.Info("Job cancellation requested", "id", id, "status", job.Status)
+	}
+	return job, nil
+}
+
+// FailStaleJobs fails running jobs whose worker has not reported since
+// before, such as jobs of an instance that crashed. They are not retried
+// because their steps may have partly happened.
+func (r *jobRepository) FailStaleJobs(ctx context.Context, before time.Time) (int64, error) {
+	now := time.Now().UTC()
+	res := r.db.WithContext(ctx).Model(&model.Job{}).
+		Where("status = ? AND updated_at < ?", model.JobStatusRunning, before.UTC()).
+		Updates(map[string]interface{}{
+			"status":      model.JobStatusFailed,
+			"error":       "job was abandoned by its worker",
+			"finished_at": now,
+			"updated_at":  now,
+		})
+	if res.Error != nil {
+		r.logger.Error("Failed to fail stale jobs", "error", res.Error)
+		return 0, fmt.Errorf("failed to fail stale jobs: %w", res.Error)
+	}
+
+	if res.RowsAffected > 0 {
+		r.logger.Warn("Failed stale jobs", "count", res.RowsAffected)
+	}
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IOutboxRepository interface {
+	PublishPending(ctx context.Context, limit int, publish func(events []model.OutboxEvent) error) (int, error)
+}
+
+type outboxRepository struct {
+	db     *gorm.DB
+	logger logger.ILogger
+}
+
+func NewOutboxRepository(db *gorm.DB, logger logger.ILogger) IOutboxRepository {
+	return &outboxRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// PublishPending locks up to limit unpublished events, hands them to publish
+// and marks them as published when it succeeds. On failure the events stay
+// pending with the error recorded and are retried on the next call. Rows are
+// locked with SKIP LOCKED so several relays can run side by side.
+func (r *outboxRepository) PublishPending(ctx context.Context, limit int, publish func(events []model.OutboxEvent) error) (int, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		r.logger.Error("Failed to begin transaction", "error", tx.Error)
+		return 0, fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			tx.Rollback()
+			r.logger.Error("Recovered from panic in PublishPending", "error", rec)
+			panic(rec)
+		}
+	}()
+
+	var events []model.OutboxEvent
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("published_at IS NULL").
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		tx.Rollback()
+		r.logger.Error("Failed to fetch pending outbox events", "error", err)
+		return 0, fmt.Errorf("failed to fetch pending outbox events: %w", err)
+	}
+
+	if len(events) == 0 {
+		tx.Rollback()
+		return 0, nil
+	}
+
+	ids := make([]uint64, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+
+	publishErr := publish(events)
+
+	var updateData map[string]interface{}
+	if publishErr != nil {
+		updateData = map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": publishErr.Error(),
+		}
+	} else {
+		updateData = map[string]interface{}{
+			"published_at": time.Now().UTC(),
+			"last_error":   "",
+		}
+	}
+
+	if err := tx.Model(&model.OutboxEvent{}).Where("id IN ?", ids).Updates(updateData).Error; err != nil {
+		tx.Rollback()
+		r.logger.Error("Failed to update outbox events", "error", err)
+		return 0, fmt.Errorf("failed to update outbox events: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		r.logger.Error("Failed to commit transaction", "error", err)
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if publishErr != nil {
+		r.logger.Warn("Failed to publish outbox events", "count", len(events), "error", publishErr)
+		return 0, fmt.Errorf("failed to publish outbox events: %w", publishErr)
+	}
+
+	return len(events), nil
+}
+
+// newOutboxEvent builds the lifecycle event for a change of one container.
+// The actor and correlation ID are taken from the request context; changes
+// without a user are attributed to the system.
+func newOutboxEvent(ctx context.Context, eventType string, before, after *model.Container) (*model.OutboxEvent, error) {
+	event := dto.ContainerEvent{
+		SchemaVersion: dto.ContainerEventSchemaVersion,
+		EventID:       uuid.NewString(),
+		EventType:     eventType,
+		OccurredAt:    time.Now().UTC(),
+		CorrelationID: utils.CorrelationIDFromContext(ctx),
+		Actor:         dto.EventActor{Role: "system"},
+		Before:        before,
+		After:         after,
+	}
+
+	if event.CorrelationID == "" {
+		event.CorrelationID = utils.NewCorrelationID()
+	}
+
+	if claims, ok := utils.ClaimsFromContext(ctx); ok {
+		event.Actor = dto.EventActor{
+			UserID:   claims.UserID,
+			Username: claims.Username,
+			Role:     claims.Role,
+		}
+	}
+
+	switch {
+	case after != nil:
+		event.ContainerID = after.ID
+	case before != nil:
+		event.ContainerID = before.ID
+	}
+
+	if before != nil && after != nil {
+		event.ChangedFields = changedContainerFields(before, after)
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode container event: %w", err)
+	}
+
+	return &model.OutboxEvent{
+		EventID:     event.EventID,
+		EventType:   event.EventType,
+		AggregateID: event.ContainerID,
+		Payload:     payload,
+	}, nil
+}
+
+// updateEventType picks the most specific event type for an update.
+func updateEventType(before, after *model.Container) string {
+	switch {
+	case before.ImageName != after.ImageName:
+		return dto.EventContainerImageChanged
+	case before.Status != after.Status:
+		return dto.EventContainerStatusChanged
+	default:
+		return dto.EventContainerUpdated
+	}
+}
+
+func changedContainerFields(before, after *model.Container) []string {
+	var fields []string
+	if before.ContainerID != after.ContainerID {
+		fields = append(fields, "container_id")
+	}
+	if before.ImageName != after.ImageName {
+		fields = append(fields, "image_name")
+	}
+	if before.Status != after.Status {
+		fields = append(fields, "status")
+	}
+	if !reflect.DeepEqual(before.Spec, after.Spec) {
+		fields = append(fields, "spec")
+	}
+	return fields
+}
+
+func writeOutbox(ctx context.Context, tx *gorm.DB, eventType string, before, after *model.Container) error {
+	event, err := newOutboxEvent(ctx, eventType, before, after)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to write outbox event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	ErrQuotaNotFound = errors.New("quota not found")
+)
+
+// quotaLockClass namespaces the advisory locks that serialize quota checks of
+// one owner, so that concurrent requests cannot both pass the same check.
+const quotaLockClass = 0x51554f54
+
+type IQuotaRepository interface {
+	ListQuotas(ctx context.Context) ([]model.Quota, error)
+	GetQuota(ctx context.Context, id uint) (*model.Quota, error)
+	CreateQuota(ctx context.Context, quota *model.Quota) error
+	UpdateQuota(ctx context.Context, id uint, quota *model.Quota) (*model.Quota, error)
+	DeleteQuota(ctx context.Context, id uint) error
+	GetEffectiveQuota(ctx context.Context, userID uint, role string) (*model.Quota, error)
+	GetUsage(ctx context.Context, userID uint) (*dto.QuotaUsage, error)
+}
+
+type quotaRepository struct {
+	db     *gorm.DB
+	logger logger.ILogger
+}
+
+func NewQuotaRepository(db *gorm.DB, logger logger.ILogger) IQuotaRepository {
+	return &quotaRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *quotaRepository) ListQuotas(ctx context.Context) ([]model.Quota, error) {
+	var quotas []model.Quota
+	if err := r.db.WithContext(ctx).Order("subject_type, subject").Find(&quotas).Error; err != nil {
+		r.logger.Error("Failed to list quotas", "error", err)
+		return nil, fmt.Errorf("failed to list quotas: %w", err)
+	}
+	return quotas, nil
+}
+
+func (r *quotaRepository) GetQuota(ctx context.Context, id uint) (*model.Quota, error) {
+	var quota model.Quota
+	err := r.db.WithContext(ctx).First(&quota, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrQuotaNotFound
+	}
+	if err != nil {
+		r.logger.Error("Failed to get quota", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to get quota: %w", err)
+	}
+	return &quota, nil
+}
+
+func (r *quotaRepository) CreateQuota(ctx context.Context, quota *model.Quota) error {
+	if err := r.db.WithContext(ctx).Create(quota).Error; err != nil {
+		r.logger.Error("Failed to create quota", "subjectType", quota.SubjectType, "subject", quota.Subject, "error", err)
+		return fmt.Errorf("failed to create quota: %w", err)
+	}
+	return nil
+}
+
+// UpdateQuota replaces the limits of a quota. Its subject cannot change.
+func (r *quotaRepository) UpdateQuota(ctx context.Context, id uint, quota *model.Quota) (*model.Quota, error) {
+	existing, err := r.GetQuota(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updateData := map[string]interface{}{
+		"max_containers":   quota.MaxContainers,
+		"max_running":      quota.MaxRunning,
+		"max_cpu_shares":   quota.MaxCPUShares,
+		"max_memory_bytes": quota.MaxMemoryBytes,
+	}
+	if err := r.db.WithContext(ctx).Model(existing).Updates(updateData).Error; err != nil {
+		r.logger.Error("Failed to update quota", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to update quota: %w", err)
+	}
+	return existing, nil
+}
+
+func (r *quotaRepository) DeleteQuota(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.Quota{}, id)
+	if result.Error != nil {
+		r.logger.Error("Failed to delete quota", "id", id, "error", result.Error)
+		return fmt.Errorf("failed to delete quota: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrQuotaNotFound
+	}
+	return nil
+}
+
+func (r *quotaRepository) GetEffectiveQuota(ctx context.Context, userID uint, role string) (*model.Quota, error) {
+	quota, err := effectiveQuota(r.db.WithContext(ctx), userID, role)
+	if err != nil {
+		r.logger.Error("Failed to get effective quota", "userID", userID, "error", err)
+		return nil, err
+	}
+	return quota, nil
+}
+
+func (r *quotaRepository) GetUsage(ctx context.Context, userID uint) (*dto.QuotaUsage, error) {
+	usage, err := quotaUsage(r.db.WithContext(ctx), userID)
+	if err != nil {
+		r.logger.Error("Failed to get quota usage", "userID", userID, "error", err)
+		return nil, err
+	}
+	return usage, nil
+}
+
+// effectiveQuota returns the quota of the user, else the quota of the role,
+// or nil when neither exists.
+func effectiveQuota(db *gorm.DB, userID uint, role string) (*model.Quota, error) {
+	query := db.Where("subject_type = ? AND subject = ?", model.QuotaSubjectUser, strconv.FormatUint(uint64(userID), 10))
+	if role != "" {
+		query = query.Or("subject_type = ? AND subject = ?", model.QuotaSubjectRole, role)
+	}
+
+	var quotas []model.Quota
+	if err := query.Find(&quotas).Error; err != nil {
+		return nil, fmt.Errorf("failed to load quotas: %w", err)
+	}
+
+	var effective *model.Quota
+	for i := range quotas {
+		if effective == nil || quotas[i].SubjectType == model.QuotaSubjectUser {
+			effective = &quotas[i]
+		}
+	}
+	return effective, nil
+}
+
+func quotaUsage(db *gorm.DB, userID uint) (*dto.QuotaUsage, error) {
+	var usage dto.QuotaUsage
+	err := db.Raw(`SELECT
+		COUNT(*) AS containers,
+		COUNT(*) FILTER (WHERE status IN ?) AS running,
+		COALESCE(SUM((spec->>'cpu_shares')::bigint) FILTER (WHERE status IN ?), 0) AS cpu_shares,
+		COALESCE(SUM((spec->>'memory_bytes')::bigint) FILTER (WHERE status IN ?), 0) AS memory_bytes
+		FROM containers WHERE owner_id = ?`,
+		ActiveStatuses, ActiveStatuses, ActiveStatuses, userID).Scan(&usage).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute quota usage: %w", err)
+	}
+	return &usage, nil
+}
+
+// checkQuota fails with ErrQuotaExceeded when adding add to the usage of
+// ownerID would exceed its quota, the quota of the user or else of
+// ownerRole. Only limits that add increases are checked, so a user over
+// quota can still stop or delete containers.
+func checkQuota(db *gorm.DB, ownerID uint, ownerRole string, add dto.QuotaUsage) error {
+	if ownerID == 0 {
+		return nil
+	}
+
+	quota, err := effectiveQuota(db, ownerID, ownerRole)
+	if err != nil || quota == nil {
+		return err
+	}
+
+	usage, err := quotaUsage(db, ownerID)
+	if err != nil {
+		return err
+	}
+
+	limits := []struct {
+		name       string
+		used, want int64
+		limit      int64
+	}{
+		{"containers", usage.Containers, add.Containers, quota.MaxContainers},
+		{"running containers", usage.Running, add.Running, quota.MaxRunning},
+		{"cpu shares", usage.CPUShares, add.CPUShares, quota.MaxCPUShares},
+		{"memory bytes", usage.MemoryBytes, add.MemoryBytes, quota.MaxMemoryBytes},
+	}
+	for _, l := range limits {
+		if l.limit > 0 && l.want > 0 && l.used+l.want > l.limit {
+			return fmt.Errorf("%w: %s would be %d, limit is %d", ErrQuotaExceeded, l.name, l.used+l.want, l.limit)
+		}
+	}
+	return nil
+}
+
+// enforceQuota is checkQuota inside tx, holding a per-owner lock until the
+// transaction ends so that the check and the write it guards are atomic.
+func enforceQuota(tx *gorm.DB, ownerID uint, ownerRole string, add dto.QuotaUsage) error {
+	if ownerID == 0 {
+		return nil
+	}
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", quotaLockClass, int32(ownerID)).Error; err != nil {
+		return fmt.Errorf("failed to lock quota: %w", err)
+	}
+	return checkQuota(tx, ownerID, ownerRole, add)
+}
+
+// QuotaForStart is what starting a container adds to its owner's usage.
+func QuotaForStart(spec model.ContainerSpec) dto.QuotaUsage {
+	return dto.QuotaUsage{
+		Running:     1,
+		CPUShares:   spec.CPUShares,
+		MemoryBytes: spec.MemoryBytes,
+	}
+}
+
+// QuotaForCreate is what creating a container adds; new containers run.
+func QuotaForCreate(spec model.ContainerSpec) dto.QuotaUsage {
+	usage := QuotaForStart(spec)
+	usage.Containers = 1
+	return usage
+}
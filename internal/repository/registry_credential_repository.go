@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/pkg/logger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrRegistryCredentialNotFound = errors.New("registry credential not found")
+
+type IRegistryCredentialRepository interface {
+	ListRegistryCredentials(ctx context.Context) ([]model.RegistryCredential, error)
+	GetRegistryCredential(ctx context.Context, registry string) (*model.RegistryCredential, error)
+	UpsertRegistryCredential(ctx context.Context, credential *model.RegistryCredential) error
+	DeleteRegistryCredential(ctx context.Context, registry string) error
+}
+
+type registryCredentialRepository struct {
+	db     *gorm.DB
+	logger logger.ILogger
+}
+
+func NewRegistryCredentialRepository(db *gorm.DB, logger logger.ILogger) IRegistryCredentialRepository {
+	return &registryCredentialRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *registryCredentialRepository) ListRegistryCredentials(ctx context.Context) ([]model.RegistryCredential, error) {
+	var credentials []model.RegistryCredential
+	if err := r.db.WithContext(ctx).Order("registry").Find(&credentials).Error; err != nil {
+		r.logger.Error("Failed to list registry credentials", "error", err)
+		return nil, fmt.Errorf("failed to list registry credentials: %w", err)
+	}
+	return credentials, nil
+}
+
+func (r *registryCredentialRepository) GetRegistryCredential(ctx context.Context, registry string) (*model.RegistryCredential, error) {
+	var credential model.RegistryCredential
+	err := r.db.WithContext(ctx).Where("registry = ?", registry).First(&credential).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRegistryCredentialNotFound
+	}
+	if err != nil {
+		r.logger.Error("Failed to get registry credential", "registry", registry, "error", err)
+		return nil, fmt.Errorf("failed to get registry credential: %w", err)
+	}
+	return &credential, nil
+}
+
+// UpsertRegistryCredential stores the credential of a registry, replacing
+// the one stored before.
+func (r *registryCredentialRepository) UpsertRegistryCredential(ctx context.Context, credential *model.RegistryCredential) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "registry"}},
+		DoUpdates: clause.AssignmentColumns([]string{"username", "encrypted_password", "updated_at"}),
+	}).Create(credential).Error
+	if err != nil {
+		r.logger.Error("Failed to store registry credential", "registry", credential.Registry, "error", err)
+		return fmt.Errorf("failed to store registry credential: %w", err)
+	}
+	return nil
+}
+
+func (r *registryCredentialRepository) DeleteRegistryCredential(ctx context.Context, registry string) error {
+	result := r.db.WithContext(ctx).Where("registry = ?", registry).Delete(&model.RegistryCredential{})
+	if result.Error != nil {
+		r.logger.Error("Failed to delete registry credential", "registry", registry, "error", result.Error)
+		return fmt.Errorf("failed to delete registry credential: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRegistryCredentialNotFound
+	}
+	return nil
+}
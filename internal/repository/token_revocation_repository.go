@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	revokedTokenKeyPrefix = "auth:revoked:jti:"
+	revokedUserKeyPrefix  = "auth:revoked:user:"
+)
+
+// revokeUserScript sets the cut-off in KEYS[1] to ARGV[1] with a TTL of
+// ARGV[2] milliseconds unless a later cut-off is already stored, in one
+// step so that concurrent revocations cannot move the cut-off back.
+var revokeUserScript = redis.NewScript(`
+local current = redis.call("GET", KEYS[1])
+if current and tonumber(current) >= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+return 1
+`)
+
+type ITokenRevocationRepository interface {
+	RevokeToken(ctx context.Context, jti string, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	RevokeUserTokens(ctx context.Context, userID uint, issuedBefore time.Time, ttl time.Duration) error
+	UserRevokedBefore(ctx context.Context, userID uint) (time.Time, bool, error)
+}
+
+type tokenRevocationRepository struct {
+	redis  *redis.Client
+	logger logger.ILogger
+}
+
+func NewTokenRevocationRepository(redis *redis.Client, logger logger.ILogger) ITokenRevocationRepository {
+	return &tokenRevocationRepository{
+		redis:  redis,
+		logger: logger,
+	}
+}
+
+// RevokeToken denylists a single token. The entry expires together with the
+// token, after which the token is rejected as expired anyway.
+func (r *tokenRevocationRepository) RevokeToken(ctx context.Context, jti string, ttl time.Duration) error {
+	if err := r.redis.Set(ctx, revokedTokenKeyPrefix+jti, 1, ttl).Err(); err != nil {
+		r.logger.Error("Failed to revoke token", "jti", jti, "error", err)
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRevocationRepository) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := r.redis.Exists(ctx, revokedTokenKeyPrefix+jti).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	return n > 0, nil
+}
+
+// RevokeUserTokens rejects every token of the user issued before
+// issuedBefore. A later cut-off replaces an earlier one, never the reverse.
+func (r *tokenRevocationRepository) RevokeUserTokens(ctx context.Context, userID uint, issuedBefore time.Time, ttl time.Duration) error {
+	key := revokedUserKeyPrefix + strconv.FormatUint(uint64(userID), 10)
+
+	// A later cut-off also lives longer, so keeping it keeps the longer TTL.
+	if err := revokeUserScript.Run(ctx, r.redis, []string{key}, issuedBefore.Unix(), ttl.Milliseconds()).Err(); err != nil {
+		r.logger.Error("Failed to revoke user tokens", "userID", userID, "error", err)
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	return nil
+}
+
+// UserRevokedBefore returns the cut-off set by RevokeUserTokens, if any.
+func (r *tokenRevocationRepository) UserRevokedBefore(ctx context.Context, userID uint) (time.Time, bool, error) {
+	key := revokedUserKeyPrefix + strconv.FormatUint(uint64(userID), 10)
+
+	value, err := r.redis.Get(ctx, key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to check user token revocation: %w", err)
+	}
+	return time.Unix(value, 0), true, nil
+}
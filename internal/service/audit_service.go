@@ -0,0 +1,154 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	AuditExportXLSX = "xlsx"
+	AuditExportCSV  = "csv"
+
+	// MaxAuditExportRows caps the number of audit events in one export.
+	MaxAuditExportRows = 10000
+)
+
+var auditColumns = []string{
+	"ID", "Time", "Actor User ID", "Actor Username", "Actor Role", "Action", "Container ID",
+	"Changes", "Source IP", "Transport", "Correlation ID", "Result", "Error",
+}
+
+type IAuditService interface {
+	ListAuditEvents(ctx context.Context, filter *dto.AuditFilter, offset, limit int) (int64, []model.AuditEvent, error)
+	ExportAuditEvents(ctx context.Context, filter *dto.AuditFilter, format string) (*dto.ExportData, error)
+}
+
+type auditService struct {
+	repo   repository.IAuditRepository
+	logger logger.ILogger
+}
+
+func NewAuditService(repo repository.IAuditRepository, logger logger.ILogger) IAuditService {
+	return &auditService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// recordAdminChange writes the audit entry of an admin change, failed when
+// cause is set. The change already happened, so the entry does not depend on
+// the request context and failing to write it is only logged.
+func recordAdminChange(ctx context.Context, repo repository.IAuditRepository, logger logger.ILogger, action string, details interface{}, cause error) {
+	if repo == nil {
+		return
+	}
+	if err := repo.RecordEvent(context.WithoutCancel(ctx), action, 0, details, cause); err != nil {
+		logger.Error("Failed to record admin change", "action", action, "error", err)
+	}
+}
+
+func (s *auditService) ListAuditEvents(ctx context.Context, filter *dto.AuditFilter, offset, limit int) (int64, []model.AuditEvent, error) {
+	total, events, err := s.repo.ListAuditEvents(ctx, filter, offset, limit)
+	if err != nil {
+		s.logger.Error("Failed to retrieve audit events", "error", err)
+		return 0, nil, fmt.Errorf("failed to retrieve audit events: %w", err)
+	}
+	return total, events, nil
+}
+
+func (s *auditService) ExportAuditEvents(ctx context.Context, filter *dto.AuditFilter, format string) (*dto.ExportData, error) {
+	_, events, err := s.repo.ListAuditEvents(ctx, filter, 0, MaxAuditExportRows)
+	if err != nil {
+		s.logger.Error("Failed to retrieve audit events for export", "error", err)
+		return nil, fmt.Errorf("failed to retrieve audit events for export: %w", err)
+	}
+
+	rows := make([][]string, 0, len(events)+1)
+	rows = append(rows, auditColumns)
+	for _, event := range events {
+		rows = append(rows, []string{
+			strconv.FormatUint(event.ID, 10),
+			event.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatUint(uint64(event.ActorUserID), 10),
+			event.ActorUsername,
+			event.ActorRole,
+			event.Action,
+			strconv.FormatUint(uint64(event.ContainerID), 10),
+			string(event.Changes),
+			event.SourceIP,
+			event.Transport,
+			event.CorrelationID,
+			event.Result,
+			event.Error,
+		})
+	}
+
+	timestamp := time.Now().Format("2006-01-02_15-04-05")
+	switch format {
+	case AuditExportCSV:
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if err := w.WriteAll(rows); err != nil {
+			s.logger.Error("Failed to write CSV file", "error", err)
+			return nil, fmt.Errorf("failed to write CSV file: %w", err)
+		}
+
+		s.logger.Info("Audit events exported successfully", "count", len(events), "format", format)
+		return &dto.ExportData{
+			FileName:    fmt.Sprintf("audit_%s.csv", timestamp),
+			ContentType: "text/csv",
+			Data:        buf.Bytes(),
+		}, nil
+
+	case AuditExportXLSX:
+		f := excelize.NewFile()
+		defer f.Close()
+
+		sheetName := "audit_events"
+		if err := f.SetSheetName("Sheet1", sheetName); err != nil {
+			s.logger.Error("Failed to set sheet name", "error", err)
+			return nil, fmt.Errorf("failed to set sheet name: %w", err)
+		}
+
+		for i, row := range rows {
+			cell, err := excelize.CoordinatesToCellName(1, i+1)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert coordinates to cell name: %w", err)
+			}
+			values := make([]interface{}, len(row))
+			for j, value := range row {
+				values[j] = value
+			}
+			if err := f.SetSheetRow(sheetName, cell, &values); err != nil {
+				s.logger.Error("Failed to set row values", "error", err, "row", i+1)
+				return nil, fmt.Errorf("failed to set row values: %w", err)
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := f.Write(&buf); err != nil {
+			s.logger.Error("Failed to write Excel file", "error", err)
+			return nil, fmt.Errorf("failed to write Excel file: %w", err)
+		}
+
+		s.logger.Info("Audit events exported successfully", "count", len(events), "format", format)
+		return &dto.ExportData{
+			FileName:    fmt.Sprintf("audit_%s.xlsx", timestamp),
+			ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			Data:        buf.Bytes(),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
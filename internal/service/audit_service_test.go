@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"thanhnt208/container-adm-service/utils"
+	"time"
+)
+
+// fakeQuotaRepository holds a single quota.
+type fakeQuotaRepository struct {
+	repository.IQuotaRepository
+	quota *model.Quota
+}
+
+func (f *fakeQuotaRepository) GetQuota(ctx context.Context, id uint) (*model.Quota, error) {
+	if f.quota == nil || f.quota.ID != id {
+		return nil, ErrQuotaNotFound
+	}
+	quota := *f.quota
+	return &quota, nil
+}
+
+func (f *fakeQuotaRepository) UpdateQuota(ctx context.Context, id uint, quota *model.Quota) (*model.Quota, error) {
+	f.quota.MaxRunning = quota.MaxRunning
+	f.quota.MaxMemoryBytes = quota.MaxMemoryBytes
+	return f.GetQuota(ctx, id)
+}
+
+func (f *fakeQuotaRepository) DeleteQuota(ctx context.Context, id uint) error {
+	f.quota = nil
+	return nil
+}
+
+type failingTokenRevocationRepository struct {
+	repository.ITokenRevocationRepository
+}
+
+func (failingTokenRevocationRepository) RevokeUserTokens(ctx context.Context, userID uint, issuedBefore time.Time, ttl time.Duration) error {
+	return errors.New("redis unavailable")
+}
+
+func TestAdminChangesAreAudited(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      func(audit *fakeAuditRepository) error
+		wantAction  string
+		wantDetails string
+		wantFailed  bool
+	}{
+		{
+			name: "quota update",
+			change: func(audit *fakeAuditRepository) error {
+				repo := &fakeQuotaRepository{quota: &model.Quota{ID: 3, SubjectType: model.QuotaSubjectRole, Subject: "dev", MaxRunning: 2}}
+				_, err := NewQuotaService(repo, audit, nopLogger{}).UpdateQuota(context.Background(), 3, &model.Quota{MaxRunning: 5})
+				return err
+			},
+			wantAction:  dto.AuditQuotaUpdated,
+			wantDetails: "map[changes:map[max_running:{2 5}] id:3 subject:dev subject_type:role]",
+		},
+		{
+			name: "unknown quota delete",
+			change: func(audit *fakeAuditRepository) error {
+				return NewQuotaService(&fakeQuotaRepository{}, audit, nopLogger{}).DeleteQuota(context.Background(), 9)
+			},
+			wantAction:  dto.AuditQuotaDeleted,
+			wantDetails: "map[id:9]",
+			wantFailed:  true,
+		},
+		{
+			name: "registry credential without encryption key",
+			change: func(audit *fakeAuditRepository) error {
+				svc := NewRegistryCredentialService(nil, audit, &utils.SecretCipher{}, nopLogger{})
+				_, err := svc.SetRegistryCredential(context.Background(), "https://Index.Docker.io/", "bot", "hunter2")
+				return err
+			},
+			wantAction:  dto.AuditRegistryCredentialSet,
+			wantDetails: "map[registry:docker.io username:bot]",
+			wantFailed:  true,
+		},
+		{
+			name: "user token revocation",
+			change: func(audit *fakeAuditRepository) error {
+				svc := NewTokenRevocationService(failingTokenRevocationRepository{}, audit, nopLogger{}, time.Hour)
+				return svc.RevokeUserTokens(context.Background(), 7, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+			},
+			wantAction:  dto.AuditUserTokensRevoked,
+			wantDetails: "map[issued_before:2030-01-01 00:00:00 +0000 UTC user_id:7]",
+			wantFailed:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := &fakeAuditRepository{}
+			err := tt.change(audit)
+			if (err != nil) != tt.wantFailed {
+				t.Fatalf("change error = %v, want failed %v", err, tt.wantFailed)
+			}
+
+			if len(audit.actions) != 1 || audit.actions[0] != tt.wantAction {
+				t.Fatalf("audit actions = %v, want %s", audit.actions, tt.wantAction)
+			}
+			if got := fmt.Sprint(audit.details[0]); got != tt.wantDetails {
+				t.Errorf("audit details = %s, want %s", got, tt.wantDetails)
+			}
+			if !errors.Is(audit.causes[0], err) {
+				t.Errorf("audit cause = %v, want %v", audit.causes[0], err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"thanhnt208/container-adm-service/internal/repository"
+	"thanhnt208/container-adm-service/pkg/authz"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/utils"
+)
+
+// IAuthzService decides whether a caller may perform an action, loading the
+// target container when the policy needs its attributes.
+type IAuthzService interface {
+	Authorize(ctx context.Context, subject authz.Subject, action string, containerID uint) (*authz.Decision, error)
+	AuthorizeCollection(subject authz.Subject, action string) (*authz.Decision, *authz.Scope)
+}
+
+type authzService struct {
+	engine *authz.Engine
+	repo   repository.IContainerRepository
+	logger logger.ILogger
+}
+
+func NewAuthzService(engine *authz.Engine, repo repository.IContainerRepository, logger logger.ILogger) IAuthzService {
+	return &authzService{
+		engine: engine,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Authorize evaluates the policy for action on the container with the given
+// ID, or on no container when it is 0. A container the caller cannot see is
+// treated as absent, so only rules without conditions can allow the action.
+func (s *authzService) Authorize(ctx context.Context, subject authz.Subject, action string, containerID uint) (*authz.Decision, error) {
+	var resource *authz.Resource
+	if containerID != 0 {
+		container, err := s.repo.GetContainerByID(ctx, containerID)
+		if err != nil && !repository.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to load container for authorization: %w", err)
+		}
+		if container != nil {
+			resource = &authz.Resource{
+				ID:       container.ID,
+				OwnerID:  container.OwnerID,
+				TenantID: container.TenantID,
+				Labels:   container.Spec.Labels,
+			}
+		}
+	}
+
+	decision := s.engine.Evaluate(subject, action, resource)
+	if !decision.Allowed {
+		s.logger.Warn("Action denied by policy", "action", action, "containerID", containerID, "username", subject.Username, "reason", decision.Reason)
+	}
+	return &decision, nil
+}
+
+// AuthorizeCollection evaluates the policy for action on many containers,
+// such as a list or an aggregate. When allowed, the request must only cover
+// containers in the returned scope; pass it on with authz.ContextWithScope.
+func (s *authzService) AuthorizeCollection(subject authz.Subject, action string) (*authz.Decision, *authz.Scope) {
+	decision, scope := s.engine.EvaluateCollection(subject, action)
+	if !decision.Allowed {
+		s.logger.Warn("Action denied by policy", "action", action, "username", subject.Username, "reason", decision.Reason)
+	}
+	return &decision, scope
+}
+
+// SubjectFromClaims describes the caller of a token for the policy engine.
+func SubjectFromClaims(claims *utils.Claims) authz.Subject {
+	return authz.Subject{
+		UserID:   claims.UserID,
+		Username: claims.Username,
+		Role:     claims.Role,
+		TenantID: claims.TenantID,
+		Scopes:   claims.Scopes,
+	}
+}
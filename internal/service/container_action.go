@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"thanhnt208/container-adm-service/external/client"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"time"
+)
+
+var (
+	ErrInvalidContainerAction = errors.New("invalid container action")
+	ErrContainerStateConflict = client.ErrContainerConflict
+)
+
+// IsReportableStatus reports whether a status producer may report status.
+// Missing is only ever set by the service itself.
+func IsReportableStatus(status string) bool {
+	return repository.IsValidStatus(status) && status != repository.StatusMissing
+}
+
+// RunContainerAction performs action on the Docker container and records
+// the status it leaves the container in.
+func (s *containerService) RunContainerAction(ctx context.Context, id uint, action string, req dto.ContainerActionRequest) (*model.Container, error) {
+	container, err := s.runContainerAction(ctx, id, action, req)
+	if err != nil {
+		s.auditFailure(ctx, dto.EventContainerStatusChanged, id, map[string]interface{}{
+			"action":  action,
+			"timeout": req.Timeout,
+			"signal":  req.Signal,
+		}, err)
+	}
+	return container, err
+}
+
+func (s *containerService) runContainerAction(ctx context.Context, id uint, action string, req dto.ContainerActionRequest) (*model.Container, error) {
+	if !slices.Contains(dto.ContainerActions, action) {
+		s.logger.Warn("Unknown container action", "id", id, "action", action)
+		return nil, fmt.Errorf("%w: %q", ErrInvalidContainerAction, action)
+	}
+
+	container, err := s.repo.GetContainerByID(ctx, id)
+	if repository.IsNotFound(err) || (err == nil && container == nil) {
+		s.logger.Warn("Container not found for action", "id", id, "action", action)
+		return nil, fmt.Errorf("%w: %d", ErrContainerNotFound, id)
+	}
+	if err != nil {
+		s.logger.Error("Failed to retrieve container for action", "id", id, "action", action, "error", err)
+		return nil, fmt.Errorf("failed to retrieve container for action: %w", err)
+	}
+
+	starts := action == dto.ContainerActionStart || action == dto.ContainerActionRestart
+	if starts && !repository.IsActiveStatus(container.Status) {
+		if err := s.repo.CheckQuota(ctx, container.OwnerID, container.OwnerRole, repository.QuotaForStart(container.Spec)); err != nil {
+			s.logger.Warn("Container start rejected by quota", "id", id, "ownerID", container.OwnerID, "error", err)
+			return nil, err
+		}
+	}
+
+	status, err := s.applyDockerAction(ctx, container, action, req)
+	if errors.Is(err, client.ErrInvalidArgument) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidContainerAction, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	updatedContainer, err := s.repo.UpdateContainer(ctx, id, map[string]interface{}{
+		"status":         status,
+		"last_status_at": time.Now().UTC(),
+	})
+	if errors.Is(err, ErrQuotaExceeded) {
+		// Another request used up the quota after our check; undo the start.
+		if stopErr := s.dockerClient.StopContainer(ctx, container.ContainerID); stopErr != nil {
+			s.logger.Error("Failed to stop Docker container after quota rejection", "containerID", container.ContainerID, "error", stopErr)
+		}
+		return nil, err
+	}
+	if err != nil {
+		s.logger.Error("Failed to update container status in repository", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to update container status in repository: %w", err)
+	}
+
+	s.logger.Info("Container action completed", "id", id, "action", action, "status", status)
+	return updatedContainer, nil
+}
+
+// applyDockerAction performs action on the Docker container and returns the
+// status it is expected to be in afterwards.
+func (s *containerService) applyDockerAction(ctx context.Context, container *model.Container, action string, req dto.ContainerActionRequest) (string, error) {
+	var status string
+	var err error
+	switch action {
+	case dto.ContainerActionStart:
+		status, err = repository.StatusRunning, s.dockerClient.StartExistingContainer(ctx, container.ContainerID)
+	case dto.ContainerActionStop:
+		status, err = repository.StatusStopped, s.dockerClient.StopContainer(ctx, container.ContainerID)
+	case dto.ContainerActionRestart:
+		var timeout *int
+		if req.Timeout > 0 {
+			timeout = &req.Timeout
+		}
+		status, err = repository.StatusRunning, s.dockerClient.RestartContainer(ctx, container.ContainerID, timeout)
+	case dto.ContainerActionPause:
+		status, err = repository.StatusPaused, s.dockerClient.PauseContainer(ctx, container.ContainerID)
+	case dto.ContainerActionUnpause:
+		status, err = repository.StatusRunning, s.dockerClient.UnpauseContainer(ctx, container.ContainerID)
+	case dto.ContainerActionKill:
+		if err = s.dockerClient.KillContainer(ctx, container.ContainerID, req.Signal); err == nil {
+			status = s.killedStatus(ctx, container)
+		}
+	}
+	if err != nil {
+		s.logger.Error("Failed to run action on Docker container", "containerID", container.ContainerID, "action", action, "error", err)
+		return "", fmt.Errorf("failed to %s Docker container: %w", action, err)
+	}
+	return status, nil
+}
+
+// killWaitTimeout bounds how long a kill waits for the container to stop
+// before its status is read.
+const killWaitTimeout = 2 * time.Second
+
+// killedStatus asks Docker what a signal did to the container, since not
+// every signal ends it. Docker only delivers the signal, so the container is
+// first given killWaitTimeout to stop. Without an answer the container is
+// assumed to have exited.
+func (s *containerService) killedStatus(ctx context.Context, container *model.Container) string {
+	if _, err := s.dockerClient.WaitContainerStopped(ctx, container.ContainerID, killWaitTimeout); err != nil {
+		s.logger.Warn("Failed to wait for Docker container after kill", "containerID", container.ContainerID, "error", err)
+	}
+
+	state, err := s.dockerClient.InspectContainer(ctx, container.ContainerID)
+	if err != nil {
+		s.logger.Warn("Failed to inspect Docker container after kill", "containerID", container.ContainerID, "error", err)
+		return repository.StatusExited
+	}
+	return statusFromDockerState(state.State)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/repository"
+	"time"
+)
+
+const (
+	// execExitTimeout bounds the exit code lookup after a session ended, which
+	// also runs when the caller has already gone away.
+	execExitTimeout = 5 * time.Second
+
+	// execStopTimeout is how long a command may keep running after its
+	// caller went away and its stdin was closed. Docker cannot kill an exec,
+	// so a command still running then is left to itself.
+	execStopTimeout = 10 * time.Second
+)
+
+var ErrInvalidExecCommand = errors.New("invalid exec command")
+
+// ExecContainer runs a command in a running container the caller can see,
+// wiring its standard streams to streams until the command exits. When ctx
+// is done first, the command is given execStopTimeout to exit on the end of
+// its input. Every session is written to the audit log with the command, its
+// duration and exit code.
+func (s *containerService) ExecContainer(ctx context.Context, id uint, opts dto.ExecOptions, streams dto.ExecIO) (*dto.ExecResult, error) {
+	started := time.Now()
+	result, err := s.execContainer(ctx, id, opts, streams)
+
+	details := map[string]interface{}{
+		"command":    opts.Cmd,
+		"tty":        opts.Tty,
+		"started_at": started.UTC(),
+	}
+	if result != nil {
+		details["exec_id"] = result.ExecID
+		details["exit_code"] = result.ExitCode
+		details["duration_ms"] = result.Duration.Milliseconds()
+	}
+	// The session usually ends because the caller disconnected, so the audit
+	// entry must not depend on the request context.
+	if s.auditRepo != nil {
+		if auditErr := s.auditRepo.RecordEvent(context.WithoutCancel(ctx), dto.AuditContainerExec, id, details, err); auditErr != nil {
+			s.logger.Error("Failed to record exec session", "id", id, "error", auditErr)
+		}
+	}
+
+	return result, err
+}
+
+func (s *containerService) execContainer(ctx context.Context, id uint, opts dto.ExecOptions, streams dto.ExecIO) (*dto.ExecResult, error) {
+	if len(opts.Cmd) == 0 || strings.TrimSpace(opts.Cmd[0]) == "" {
+		return nil, fmt.Errorf("%w: command is required", ErrInvalidExecCommand)
+	}
+
+	container, err := s.repo.GetContainerByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to retrieve container for exec", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to retrieve container for exec: %w", err)
+	}
+	if container == nil {
+		s.logger.Warn("Container not found for exec", "id", id)
+		return nil, fmt.Errorf("container with ID %d not found", id)
+	}
+	if container.Status != repository.StatusRunning {
+		s.logger.Warn("Exec requested for container that is not running", "id", id, "status", container.Status)
+		return nil, fmt.Errorf("%w: status is %s", ErrContainerNotRunning, container.Status)
+	}
+
+	session, err := s.dockerClient.Exec(ctx, container.ContainerID, opts)
+	if err != nil {
+		s.logger.Error("Failed to start exec", "id", id, "containerID", container.ContainerID, "error", err)
+		return nil, fmt.Errorf("failed to start exec: %w", err)
+	}
+	defer session.Close()
+
+	started := time.Now()
+	s.logger.Info("Exec session started", "id", id, "containerID", container.ContainerID, "execID", session.ID(), "command", opts.Cmd, "tty", opts.Tty)
+
+	// The caller is gone once ctx is done or its output cannot be written.
+	// The command then gets the end of its input and execStopTimeout to
+	// exit; closing the session afterwards unblocks CopyOutput.
+	gone, leave := context.WithCancel(ctx)
+	defer leave()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-gone.Done():
+			session.CloseStdin()
+			select {
+			case <-time.After(execStopTimeout):
+				s.logger.Warn("Exec still running after caller left, detaching", "id", id, "execID", session.ID())
+				session.Close()
+			case <-done:
+			}
+		case <-done:
+		}
+	}()
+
+	if streams.Stdin != nil {
+		go func() {
+			if _, err := io.Copy(session, streams.Stdin); err != nil {
+				s.logger.Debug("Exec stdin closed", "execID", session.ID(), "error", err)
+			}
+			session.CloseStdin()
+		}()
+	}
+
+	if streams.Resize != nil {
+		go func() {
+			for {
+				select {
+				case size, ok := <-streams.Resize:
+					if !ok {
+						return
+					}
+					if err := session.Resize(ctx, size.Width, size.Height); err != nil {
+						s.logger.Warn("Failed to resize exec", "execID", session.ID(), "error", err)
+					}
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	stdout, stderr := streams.Stdout, streams.Stderr
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	copyErr := session.CopyOutput(
+		&execOutput{ctx: gone, leave: leave, w: stdout},
+		&execOutput{ctx: gone, leave: leave, w: stderr},
+	)
+
+	result := &dto.ExecResult{
+		ExecID:   session.ID(),
+		ExitCode: -1,
+		Duration: time.Since(started),
+	}
+
+	callerLeft := gone.Err() != nil
+	if copyErr != nil && !callerLeft {
+		s.logger.Error("Exec session failed", "id", id, "execID", result.ExecID, "error", copyErr)
+		return result, copyErr
+	}
+
+	exitCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), execExitTimeout)
+	defer cancel()
+	if result.ExitCode, err = session.ExitCode(exitCtx); err != nil {
+		s.logger.Warn("Failed to get exec exit code", "id", id, "execID", result.ExecID, "error", err)
+	}
+
+	if callerLeft {
+		s.logger.Info("Exec session closed by caller", "id", id, "execID", result.ExecID, "exitCode", result.ExitCode, "duration", result.Duration)
+		return result, nil
+	}
+	s.logger.Info("Exec session ended", "id", id, "execID", result.ExecID, "exitCode", result.ExitCode, "duration", result.Duration)
+	return result, nil
+}
+
+// execOutput passes a command's output on to its caller. Once the caller is
+// gone the output is discarded, so the command is not blocked on writing it
+// while it gets the chance to exit.
+type execOutput struct {
+	ctx   context.Context
+	leave context.CancelFunc
+	w     io.Writer
+}
+
+func (o *execOutput) Write(p []byte) (int, error) {
+	if o.ctx.Err() != nil {
+		return len(p), nil
+	}
+	if _, err := o.w.Write(p); err != nil {
+		o.leave()
+	}
+	return len(p), nil
+}
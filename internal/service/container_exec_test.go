@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"thanhnt208/container-adm-service/external/client"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"time"
+)
+
+// fakeExecSession is a command that prints output and exits with exitCode
+// once its stdin is closed, like a shell reading commands.
+type fakeExecSession struct {
+	output   []byte
+	exitCode int
+
+	mu        sync.Mutex
+	stdinOnce sync.Once
+	stdin     chan struct{}
+	closed    bool
+}
+
+func newFakeExecSession(output string, exitCode int) *fakeExecSession {
+	return &fakeExecSession{output: []byte(output), exitCode: exitCode, stdin: make(chan struct{})}
+}
+
+func (f *fakeExecSession) ID() string { return "exec-1" }
+
+func (f *fakeExecSession) Write(p []byte) (int, error) { return len(p), nil }
+
+func (f *fakeExecSession) CloseStdin() error {
+	f.stdinOnce.Do(func() { close(f.stdin) })
+	return nil
+}
+
+func (f *fakeExecSession) CopyOutput(stdout, stderr io.Writer) error {
+	if _, err := stdout.Write(f.output); err != nil {
+		return err
+	}
+	<-f.stdin
+	// Output written while exiting must not block on a caller that left.
+	if _, err := stdout.Write(f.output); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (f *fakeExecSession) Resize(ctx context.Context, width, height uint) error { return nil }
+
+func (f *fakeExecSession) ExitCode(ctx context.Context) (int, error) {
+	select {
+	case <-f.stdin:
+		return f.exitCode, nil
+	default:
+		return -1, errors.New("still running")
+	}
+}
+
+func (f *fakeExecSession) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+type fakeExecDockerClient struct {
+	client.IDockerClient
+	session *fakeExecSession
+}
+
+func (f *fakeExecDockerClient) Exec(ctx context.Context, containerID string, opts dto.ExecOptions) (client.IExecSession, error) {
+	return f.session, nil
+}
+
+// fakeAuditRepository records the action, details and cause of every event.
+type fakeAuditRepository struct {
+	repository.IAuditRepository
+
+	mu      sync.Mutex
+	actions []string
+	details []map[string]interface{}
+	causes  []error
+}
+
+func (f *fakeAuditRepository) RecordEvent(ctx context.Context, action string, containerID uint, details interface{}, cause error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	recorded, _ := details.(map[string]interface{})
+	f.actions = append(f.actions, action)
+	f.details = append(f.details, recorded)
+	f.causes = append(f.causes, cause)
+	return nil
+}
+
+// failingWriter fails every write, like a WebSocket whose peer went away.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("connection closed") }
+
+func newExecTestService(session *fakeExecSession, audit *fakeAuditRepository) *containerService {
+	return &containerService{
+		repo:         newFakeContainerRepository(model.Container{ID: 1, ContainerID: "abc", Status: repository.StatusRunning}),
+		auditRepo:    audit,
+		logger:       nopLogger{},
+		dockerClient: &fakeExecDockerClient{session: session},
+	}
+}
+
+func TestExecContainerRecordsExitCodeWhenCallerLeaves(t *testing.T) {
+	tests := []struct {
+		name   string
+		leave  func(cancel context.CancelFunc)
+		stdout io.Writer
+	}{
+		{"context cancelled", func(cancel context.CancelFunc) { cancel() }, io.Discard},
+		{"output cannot be written", func(context.CancelFunc) {}, failingWriter{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := newFakeExecSession("$ ", 3)
+			audit := &fakeAuditRepository{}
+			svc := newExecTestService(session, audit)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			// An interactive session never closes stdin on its own.
+			stdin, stdinWriter := io.Pipe()
+			defer stdinWriter.Close()
+
+			type outcome struct {
+				result *dto.ExecResult
+				err    error
+			}
+			done := make(chan outcome, 1)
+			go func() {
+				result, err := svc.ExecContainer(ctx, 1, dto.ExecOptions{Cmd: []string{"/bin/sh"}}, dto.ExecIO{Stdin: stdin, Stdout: tt.stdout})
+				done <- outcome{result, err}
+			}()
+			tt.leave(cancel)
+
+			var got outcome
+			select {
+			case got = <-done:
+			case <-time.After(execStopTimeout / 2):
+				t.Fatal("ExecContainer did not return after the caller left")
+			}
+			if got.err != nil {
+				t.Fatalf("ExecContainer() error = %v", got.err)
+			}
+			if got.result.ExitCode != 3 {
+				t.Errorf("ExitCode = %d, want 3", got.result.ExitCode)
+			}
+			if len(audit.details) != 1 || audit.details[0]["exit_code"] != 3 {
+				t.Errorf("audit details = %v, want exit_code 3", audit.details)
+			}
+		})
+	}
+}
+
+func TestExecContainerEndsNormally(t *testing.T) {
+	session := newFakeExecSession("ok\n", 0)
+	audit := &fakeAuditRepository{}
+	svc := newExecTestService(session, audit)
+
+	// The client sent eof.
+	stdin, stdinWriter := io.Pipe()
+	stdinWriter.Close()
+
+	result, err := svc.ExecContainer(context.Background(), 1, dto.ExecOptions{Cmd: []string{"cat"}}, dto.ExecIO{Stdin: stdin, Stdout: io.Discard})
+	if err != nil {
+		t.Fatalf("ExecContainer() error = %v", err)
+	}
+	if result.ExitCode != 0 || result.ExecID != "exec-1" {
+		t.Errorf("result = %+v, want exec-1 with exit code 0", result)
+	}
+	if !session.closed {
+		t.Error("session was not closed")
+	}
+}
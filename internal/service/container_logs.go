@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/repository"
+)
+
+// StreamContainerLogs reads the log of a container the caller can see. The
+// returned channels behave like those of IDockerClient.Logs: the line
+// channel is closed when the log ends or ctx is done and the reason is sent
+// on the error channel.
+func (s *containerService) StreamContainerLogs(ctx context.Context, id uint, opts dto.ContainerLogOptions) (<-chan dto.ContainerLogLine, <-chan error, error) {
+	container, err := s.repo.GetContainerByID(ctx, id)
+	if repository.IsNotFound(err) || (err == nil && container == nil) {
+		s.logger.Warn("Container not found for logs", "id", id)
+		return nil, nil, fmt.Errorf("%w: %d", ErrContainerNotFound, id)
+	}
+	if err != nil {
+		s.logger.Error("Failed to retrieve container for logs", "id", id, "error", err)
+		return nil, nil, fmt.Errorf("failed to retrieve container for logs: %w", err)
+	}
+
+	if !opts.Stdout && !opts.Stderr {
+		opts.Stdout, opts.Stderr = true, true
+	}
+
+	s.logger.Info("Streaming container logs", "id", id, "containerID", container.ContainerID, "follow", opts.Follow, "tail", opts.Tail)
+	lines, errs := s.dockerClient.Logs(ctx, container.ContainerID, opts)
+	return lines, errs, nil
+}
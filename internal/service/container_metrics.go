@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/pkg/metrics"
+	"time"
+)
+
+// IContainerMetrics refreshes the container count gauges.
+type IContainerMetrics interface {
+	Run(ctx context.Context)
+}
+
+type containerMetrics struct {
+	service  IContainerService
+	logger   logger.ILogger
+	interval time.Duration
+}
+
+func NewContainerMetrics(service IContainerService, logger logger.ILogger, interval time.Duration) IContainerMetrics {
+	return &containerMetrics{
+		service:  service,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// Run updates the gauges immediately and then on every tick until ctx is
+// cancelled. Containers that are not running are counted as stopped.
+func (m *containerMetrics) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *containerMetrics) refresh(ctx context.Context) {
+	total, err := m.service.GetNumContainers(ctx)
+	if err != nil {
+		m.logger.Warn("Failed to refresh container metrics", "error", err)
+		return
+	}
+
+	running, err := m.service.GetNumRunningContainers(ctx)
+	if err != nil {
+		m.logger.Warn("Failed to refresh container metrics", "error", err)
+		return
+	}
+
+	metrics.Containers.WithLabelValues("total").Set(float64(total))
+	metrics.Containers.WithLabelValues("running").Set(float64(running))
+	metrics.Containers.WithLabelValues("stopped").Set(float64(total - running))
+}
@@ -3,50 +3,181 @@ package service
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"thanhnt208/container-adm-service/external/client"
 	"thanhnt208/container-adm-service/internal/dto"
 	"thanhnt208/container-adm-service/internal/model"
 	"thanhnt208/container-adm-service/internal/repository"
 	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/utils"
 	"time"
 
 	"github.com/xuri/excelize/v2"
 )
 
+var (
+	// ErrContainerNotFound is returned when a container does not exist or is
+	// not visible to the caller.
+	ErrContainerNotFound    = errors.New("container not found")
+	ErrInvalidStatus        = errors.New("invalid container status")
+	ErrInvalidHistoryCursor = repository.ErrInvalidHistoryCursor
+)
+
 type IContainerService interface {
-	CreateContainer(ctx context.Context, containerName, imageName string) (int, error)
+	CreateContainer(ctx context.Context, containerName, imageName string, spec *model.ContainerSpec) (int, error)
 	ViewAllContainers(ctx context.Context, containerFilter *dto.ContainerFilter, from, to int, sortBy string, sortOrder string) (int64, []model.Container, error)
 	UpdateContainer(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Container, error)
 	DeleteContainer(ctx context.Context, id uint) error
 	ImportContainers(ctx context.Context, buf []byte) (*dto.ImportResult, error)
+	ValidateCreateContainer(ctx context.Context, containerName, imageName string, spec *model.ContainerSpec) error
+	ValidateUpdateContainer(ctx context.Context, id uint, updateData map[string]interface{}) error
 	ExportContainers(ctx context.Context, containerFilter *dto.ContainerFilter, from, to int, sortBy, sortOrder string) (*dto.ExportData, error)
 
+	RunContainerAction(ctx context.Context, id uint, action string, req dto.ContainerActionRequest) (*model.Container, error)
+	StreamContainerLogs(ctx context.Context, id uint, opts dto.ContainerLogOptions) (<-chan dto.ContainerLogLine, <-chan error, error)
+	GetContainerStats(ctx context.Context, id uint) (*dto.ContainerStats, error)
+	StreamContainerStats(ctx context.Context, id uint) (<-chan dto.ContainerStats, <-chan error, error)
+	ExecContainer(ctx context.Context, id uint, opts dto.ExecOptions, streams dto.ExecIO) (*dto.ExecResult, error)
+
 	GetAllContainers(ctx context.Context) ([]dto.ContainerName, error)
 
-	AddContainerStatus(ctx context.Context, id uint, status string) error
+	ApplyContainerStatus(ctx context.Context, id uint, status string, at time.Time, origin dto.StatusOrigin) (bool, error)
+	AddContainerStatus(ctx context.Context, id uint, status string, timestamp time.Time) error
 	GetNumContainers(ctx context.Context) (int64, error)
 	GetNumRunningContainers(ctx context.Context) (int64, error)
-	GetContainerUptimeRatio(ctx context.Context, startTime, endTime time.Time) (float64, error)
-	GetContainerUptimeDuration(ctx context.Context, startTime, endTime time.Time) (*dto.UptimeDetails, error)
+	GetContainerUptimeRatio(ctx context.Context, startTime, endTime time.Time, mode string) (float64, error)
+	GetContainerUptimeDuration(ctx context.Context, startTime, endTime time.Time, mode string) (*dto.UptimeDetails, error)
+	GetContainerStatusHistory(ctx context.Context, id uint, from, to time.Time, status string, size int, cursor string) (*dto.StatusHistory, error)
 }
 
 type containerService struct {
 	repo         repository.IContainerRepository
+	auditRepo    repository.IAuditRepository
 	logger       logger.ILogger
 	dockerClient client.IDockerClient
+	imagePolicy  IImagePolicy
 }
 
-func NewContainerService(repo repository.IContainerRepository, logger logger.ILogger, dockerClient client.IDockerClient) IContainerService {
+func NewContainerService(repo repository.IContainerRepository, auditRepo repository.IAuditRepository, logger logger.ILogger, dockerClient client.IDockerClient, imagePolicy IImagePolicy) IContainerService {
 	return &containerService{
 		repo:         repo,
+		auditRepo:    auditRepo,
 		logger:       logger,
 		dockerClient: dockerClient,
+		imagePolicy:  imagePolicy,
+	}
+}
+
+// The mutating methods below record failed attempts in the audit log.
+// Successful changes are audited by the repository in their transaction.
+
+func (s *containerService) CreateContainer(ctx context.Context, containerName, imageName string, spec *model.ContainerSpec) (int, error) {
+	id, err := s.createContainer(ctx, containerName, imageName, spec)
+	if err != nil {
+		s.auditFailure(ctx, dto.EventContainerCreated, 0, map[string]interface{}{
+			"container_name": containerName,
+			"image_name":     imageName,
+			"spec":           spec,
+		}, err)
+	}
+	return id, err
+}
+
+func (s *containerService) UpdateContainer(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Container, error) {
+	container, err := s.updateContainer(ctx, id, updateData)
+	if err != nil {
+		s.auditFailure(ctx, dto.EventContainerUpdated, id, updateData, err)
 	}
+	return container, err
 }
 
-func (s *containerService) CreateContainer(ctx context.Context, containerName, imageName string) (int, error) {
-	containerID, err := s.dockerClient.StartContainer(ctx, containerName, imageName)
+func (s *containerService) DeleteContainer(ctx context.Context, id uint) error {
+	err := s.deleteContainer(ctx, id)
+	if err != nil {
+		s.auditFailure(ctx, dto.EventContainerDeleted, id, nil, err)
+	}
+	return err
+}
+
+func (s *containerService) ImportContainers(ctx context.Context, buf []byte) (*dto.ImportResult, error) {
+	result, err := s.importContainers(ctx, buf)
+	if err != nil {
+		s.auditFailure(ctx, dto.EventContainerImported, 0, nil, err)
+	}
+	return result, err
+}
+
+func (s *containerService) auditFailure(ctx context.Context, action string, id uint, request interface{}, cause error) {
+	if s.auditRepo == nil {
+		return
+	}
+	if err := s.auditRepo.RecordFailure(ctx, action, id, request, cause); err != nil {
+		s.logger.Error("Failed to record audit failure", "action", action, "id", id, "error", err)
+	}
+}
+
+// checkImagePolicy rejects images the policy does not allow before they are
+// pulled.
+func (s *containerService) checkImagePolicy(imageName string) error {
+	if s.imagePolicy == nil {
+		return nil
+	}
+	if err := s.imagePolicy.Check(imageName); err != nil {
+		s.logger.Warn("Image rejected by policy", "image", imageName, "error", err)
+		return err
+	}
+	return nil
+}
+
+// ValidateCreateContainer runs the checks of CreateContainer that do not
+// touch Docker, so a request can be rejected before it is queued as a job.
+func (s *containerService) ValidateCreateContainer(ctx context.Context, containerName, imageName string, spec *model.ContainerSpec) error {
+	if spec == nil {
+		spec = &model.ContainerSpec{}
+	}
+	return s.checkCreate(ctx, containerName, imageName, spec)
+}
+
+// ValidateUpdateContainer runs the checks of UpdateContainer that do not
+// touch Docker, so a request can be rejected before it is queued as a job.
+func (s *containerService) ValidateUpdateContainer(ctx context.Context, id uint, updateData map[string]interface{}) error {
+	_, err := s.checkUpdate(ctx, id, updateData)
+	return err
+}
+
+func (s *containerService) checkCreate(ctx context.Context, containerName, imageName string, spec *model.ContainerSpec) error {
+	if err := spec.Validate(); err != nil {
+		s.logger.Warn("Invalid container spec", "containerName", containerName, "error", err)
+		return fmt.Errorf("invalid container spec: %w", err)
+	}
+
+	if err := s.checkImagePolicy(imageName); err != nil {
+		return err
+	}
+
+	ownerID, ownerRole, _ := ownerFromContext(ctx)
+	if err := s.repo.CheckQuota(ctx, ownerID, ownerRole, repository.QuotaForCreate(*spec)); err != nil {
+		s.logger.Warn("Container creation rejected by quota", "containerName", containerName, "ownerID", ownerID, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (s *containerService) createContainer(ctx context.Context, containerName, imageName string, spec *model.ContainerSpec) (int, error) {
+	if spec == nil {
+		spec = &model.ContainerSpec{}
+	}
+	if err := s.checkCreate(ctx, containerName, imageName, spec); err != nil {
+		return 0, err
+	}
+
+	ownerID, ownerRole, tenantID := ownerFromContext(ctx)
+	if err := s.dockerClient.PullImage(ctx, imageName); err != nil {
+		s.logger.Error("Failed to pull image", "image", imageName, "error", err)
+		return 0, fmt.Errorf("failed to pull image: %w", err)
+	}
+	containerID, err := s.dockerClient.StartContainer(ctx, containerName, imageName, spec)
 	if err != nil {
 		s.logger.Error("Failed to start Docker container", "error", err)
 		return 0, fmt.Errorf("failed to start Docker container: %w", err)
@@ -57,16 +188,25 @@ func (s *containerService) CreateContainer(ctx context.Context, containerName, i
 		ContainerName: containerName,
 		ImageName:     imageName,
 		Status:        "running",
+		Spec:          *spec,
+		OwnerID:       ownerID,
+		OwnerRole:     ownerRole,
+		TenantID:      tenantID,
 	}
 
+	utils.ProgressFromContext(ctx).Step(model.JobStepPersist, containerName)
 	id, err := s.repo.CreateContainer(ctx, container)
 	if err != nil {
 		s.logger.Error("Failed to create container in repository", "error", err, "container", container)
-		if stopErr := s.dockerClient.StopContainer(ctx, containerID); stopErr != nil {
+		// The container must be stopped even when the request was cancelled.
+		if stopErr := s.dockerClient.StopContainer(context.WithoutCancel(ctx), containerID); stopErr != nil {
 			s.logger.Error("Failed to stop Docker container after repository creation failure", "containerID", containerID, "error", stopErr)
 		} else {
 			s.logger.Info("Stopped Docker container after repository creation failure", "containerID", containerID)
 		}
+		if errors.Is(err, ErrQuotaExceeded) {
+			return 0, err
+		}
 		return 0, fmt.Errorf("failed to create container in repository: %w", err)
 	}
 
@@ -85,12 +225,22 @@ func (s *containerService) ViewAllContainers(ctx context.Context, containerFilte
 	return count, containers, nil
 }
 
-func (s *containerService) UpdateContainer(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Container, error) {
+// checkUpdate validates an update and returns the container it applies to.
+func (s *containerService) checkUpdate(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Container, error) {
 	if _, exists := updateData["container_name"]; exists {
 		s.logger.Warn("Container name update is not allowed", "id", id)
 		return nil, fmt.Errorf("updating container name is not allowed")
 	}
 
+	if claims, ok := utils.ClaimsFromContext(ctx); ok && !utils.IsAdmin(claims) {
+		for _, field := range []string{"owner_id", "owner_role", "tenant_id"} {
+			if _, exists := updateData[field]; exists {
+				s.logger.Warn("Ownership update is only allowed for admins", "id", id, "field", field)
+				return nil, fmt.Errorf("updating %s is not allowed", field)
+			}
+		}
+	}
+
 	container, err := s.repo.GetContainerByID(ctx, id)
 	if err != nil {
 		s.logger.Error("Failed to retrieve container for update", "id", id, "error", err)
@@ -101,38 +251,91 @@ func (s *containerService) UpdateContainer(ctx context.Context, id uint, updateD
 		return nil, fmt.Errorf("container with ID %d not found", id)
 	}
 
-	if image, ok := updateData["image_name"].(string); ok && image != "" && image != container.ImageName {
+	image, _ := updateData["image_name"].(string)
+	changeImage := image != "" && image != container.ImageName
+	status, _ := updateData["status"].(string)
+	if !repository.IsActiveStatus(container.Status) && (changeImage || status == repository.StatusRunning) {
+		if err := s.repo.CheckQuota(ctx, container.OwnerID, container.OwnerRole, repository.QuotaForStart(container.Spec)); err != nil {
+			s.logger.Warn("Container start rejected by quota", "id", id, "ownerID", container.OwnerID, "error", err)
+			return nil, err
+		}
+	}
+
+	if changeImage {
+		if err := s.checkImagePolicy(image); err != nil {
+			return nil, err
+		}
+	}
+	return container, nil
+}
+
+func (s *containerService) updateContainer(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Container, error) {
+	container, err := s.checkUpdate(ctx, id, updateData)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := updateData["owner_id"]; ok {
+		if _, ok := updateData["owner_role"]; !ok {
+			// The stored role belongs to the previous owner.
+			updateData["owner_role"] = ""
+		}
+	}
+
+	image, _ := updateData["image_name"].(string)
+	changeImage := image != "" && image != container.ImageName
+	if changeImage {
+		// Pull first, so a failed or cancelled pull leaves the old container
+		// in place.
+		if err := s.dockerClient.PullImage(ctx, image); err != nil {
+			s.logger.Error("Failed to pull image for update", "containerID", container.ContainerID, "image", image, "error", err)
+			return nil, fmt.Errorf("failed to pull image: %w", err)
+		}
+		progress := utils.ProgressFromContext(ctx)
+		progress.Step(model.JobStepStop, container.ContainerName)
 		if err := s.dockerClient.StopContainer(ctx, container.ContainerID); err != nil {
 			s.logger.Error("Failed to update Docker container image", "containerID", container.ContainerID, "error", err)
 			return nil, fmt.Errorf("failed to update Docker container image: %w", err)
 		}
+		progress.Step(model.JobStepRemove, container.ContainerName)
 		if err := s.dockerClient.RemoveContainer(ctx, container.ContainerID); err != nil {
 			s.logger.Error("Failed to remove Docker container before updating image", "containerID", container.ContainerID, "error", err)
 			return nil, fmt.Errorf("failed to remove Docker container before updating image: %w", err)
 		}
-		newContainerID, err := s.dockerClient.StartContainer(ctx, container.ContainerName, image)
+		// The old container is gone, so the new one must be created and
+		// stored even if the request is cancelled now.
+		ctx = context.WithoutCancel(ctx)
+		newContainerID, err := s.dockerClient.StartContainer(ctx, container.ContainerName, image, &container.Spec)
 		if err != nil {
 			s.logger.Error("Failed to start Docker container with new image", "containerName", container.ContainerName, "image", image, "error", err)
 			return nil, fmt.Errorf("failed to start Docker container with new image: %w", err)
 		}
 		updateData["ContainerID"] = newContainerID
+		// The recreated container is running; let a requested status apply on top of that.
+		container.ContainerID = newContainerID
+		container.Status = repository.StatusRunning
+		if _, ok := updateData["status"]; !ok {
+			updateData["status"] = repository.StatusRunning
+		}
 	}
 
-	if status, ok := updateData["status"].(string); ok && status != "" && status != container.Status {
-		if status == "running" && container.Status != "running" {
-			if err := s.dockerClient.StartExistingContainer(ctx, container.ContainerID); err != nil {
-				s.logger.Error("Failed to start Docker container", "containerID", container.ContainerID, "error", err)
-				return nil, fmt.Errorf("failed to start Docker container: %w", err)
-			}
-		} else if status == "stopped" && container.Status != "stopped" {
-			if err := s.dockerClient.StopContainer(ctx, container.ContainerID); err != nil {
-				s.logger.Error("Failed to stop Docker container", "containerID", container.ContainerID, "error", err)
-				return nil, fmt.Errorf("failed to stop Docker container: %w", err)
-			}
+	if status, ok := updateData["status"].(string); ok && status != "" {
+		if err := s.syncDockerStatus(ctx, container, status); err != nil {
+			return nil, err
 		}
+		updateData["last_status_at"] = time.Now().UTC()
 	}
 
+	if changeImage {
+		utils.ProgressFromContext(ctx).Step(model.JobStepPersist, container.ContainerName)
+	}
 	updatedContainer, err := s.repo.UpdateContainer(ctx, id, updateData)
+	if errors.Is(err, ErrQuotaExceeded) {
+		// Another request used up the quota after our check; undo the start.
+		if stopErr := s.dockerClient.StopContainer(context.WithoutCancel(ctx), container.ContainerID); stopErr != nil {
+			s.logger.Error("Failed to stop Docker container after quota rejection", "containerID", container.ContainerID, "error", stopErr)
+		}
+		return nil, err
+	}
 	if err != nil {
 		s.logger.Error("Failed to update container in repository", "id", id, "error", err)
 		return nil, fmt.Errorf("failed to update container in repository: %w", err)
@@ -142,7 +345,83 @@ func (s *containerService) UpdateContainer(ctx context.Context, id uint, updateD
 	return updatedContainer, nil
 }
 
-func (s *containerService) DeleteContainer(ctx context.Context, id uint) error {
+// ApplyContainerStatus applies a status observed at the given time. Reports
+// older than the last applied status, or not after it in the sequence of
+// their producer, are ignored and false is returned.
+func (s *containerService) ApplyContainerStatus(ctx context.Context, id uint, status string, at time.Time, origin dto.StatusOrigin) (bool, error) {
+	container, err := s.repo.GetContainerByID(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to retrieve container for status update", "id", id, "error", err)
+		return false, fmt.Errorf("failed to retrieve container for status update: %w", err)
+	}
+
+	if repository.IsStaleStatus(container, at, origin) {
+		s.logger.Info("Ignoring stale container status", "id", id, "status", status, "at", at, "lastStatusAt", container.LastStatusAt,
+			"producer", origin.Producer, "sequence", origin.Sequence, "lastSequence", container.LastStatusSequence)
+		return false, nil
+	}
+
+	if status == repository.StatusRunning && !repository.IsActiveStatus(container.Status) {
+		if err := s.repo.CheckQuota(ctx, container.OwnerID, container.OwnerRole, repository.QuotaForStart(container.Spec)); err != nil {
+			s.logger.Warn("Container start rejected by quota", "id", id, "ownerID", container.OwnerID, "error", err)
+			return false, err
+		}
+	}
+
+	if err := s.syncDockerStatus(ctx, container, status); err != nil {
+		return false, err
+	}
+
+	_, applied, err := s.repo.UpdateContainerStatus(ctx, id, status, at, origin)
+	if errors.Is(err, ErrQuotaExceeded) {
+		// Another request used up the quota after our check; undo the start.
+		if stopErr := s.dockerClient.StopContainer(context.WithoutCancel(ctx), container.ContainerID); stopErr != nil {
+			s.logger.Error("Failed to stop Docker container after quota rejection", "containerID", container.ContainerID, "error", stopErr)
+		}
+		return false, err
+	}
+	if err != nil {
+		s.logger.Error("Failed to update container status in repository", "id", id, "error", err)
+		return false, fmt.Errorf("failed to update container status in repository: %w", err)
+	}
+
+	return applied, nil
+}
+
+// syncDockerStatus starts, stops, pauses or unpauses the Docker container so
+// that it matches the requested status. Statuses that can only be observed,
+// such as exited, leave the container alone.
+func (s *containerService) syncDockerStatus(ctx context.Context, container *model.Container, status string) error {
+	if status == container.Status {
+		return nil
+	}
+
+	switch {
+	case status == repository.StatusRunning && container.Status == repository.StatusPaused:
+		if err := s.dockerClient.UnpauseContainer(ctx, container.ContainerID); err != nil {
+			s.logger.Error("Failed to unpause Docker container", "containerID", container.ContainerID, "error", err)
+			return fmt.Errorf("failed to unpause Docker container: %w", err)
+		}
+	case status == repository.StatusRunning:
+		if err := s.dockerClient.StartExistingContainer(ctx, container.ContainerID); err != nil {
+			s.logger.Error("Failed to start Docker container", "containerID", container.ContainerID, "error", err)
+			return fmt.Errorf("failed to start Docker container: %w", err)
+		}
+	case status == repository.StatusPaused:
+		if err := s.dockerClient.PauseContainer(ctx, container.ContainerID); err != nil {
+			s.logger.Error("Failed to pause Docker container", "containerID", container.ContainerID, "error", err)
+			return fmt.Errorf("failed to pause Docker container: %w", err)
+		}
+	case status == repository.StatusStopped:
+		if err := s.dockerClient.StopContainer(ctx, container.ContainerID); err != nil {
+			s.logger.Error("Failed to stop Docker container", "containerID", container.ContainerID, "error", err)
+			return fmt.Errorf("failed to stop Docker container: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *containerService) deleteContainer(ctx context.Context, id uint) error {
 	container, err := s.repo.GetContainerByID(ctx, id)
 	if err != nil {
 		s.logger.Error("Failed to retrieve container for deletion", "id", id, "error", err)
@@ -173,7 +452,7 @@ func (s *containerService) DeleteContainer(ctx context.Context, id uint) error {
 	return nil
 }
 
-func (s *containerService) ImportContainers(ctx context.Context, buf []byte) (*dto.ImportResult, error) {
+func (s *containerService) importContainers(ctx context.Context, buf []byte) (*dto.ImportResult, error) {
 	f, err := excelize.OpenReader(bytes.NewReader(buf))
 	if err != nil {
 		s.logger.Error("Failed to open Excel file", "error", err)
@@ -192,10 +471,17 @@ func (s *containerService) ImportContainers(ctx context.Context, buf []byte) (*d
 		return &dto.ImportResult{SuccessfulCount: 0, FailedCount: 0}, nil
 	}
 
+	ownerID, ownerRole, tenantID := ownerFromContext(ctx)
 	var containersToCreate []model.Container
 	var parsingErrors []string
+	var pending dto.QuotaUsage
 
 	for i, row := range rows[1:] {
+		if err := ctx.Err(); err != nil {
+			s.stopImportedContainers(ctx, containersToCreate)
+			return nil, fmt.Errorf("import cancelled: %w", err)
+		}
+
 		rowNum := i + 2
 		if len(row) < 2 {
 			parsingErrors = append(parsingErrors, fmt.Sprintf("Row %d: Not enough columns", rowNum))
@@ -210,7 +496,36 @@ func (s *containerService) ImportContainers(ctx context.Context, buf []byte) (*d
 			continue
 		}
 
-		containerID, err := s.dockerClient.StartContainer(ctx, containerName, imageName)
+		spec, err := parseSpecCells(row[2:])
+		if err != nil {
+			parsingErrors = append(parsingErrors, fmt.Sprintf("Row %d: Invalid container spec - %s", rowNum, err.Error()))
+			continue
+		}
+
+		if err := s.checkImagePolicy(imageName); err != nil {
+			parsingErrors = append(parsingErrors, fmt.Sprintf("Row %d: %s", rowNum, err.Error()))
+			continue
+		}
+
+		// Earlier rows are not stored yet, so count them against the quota too.
+		add := repository.QuotaForCreate(*spec)
+		withPending := dto.QuotaUsage{
+			Containers:  pending.Containers + add.Containers,
+			Running:     pending.Running + add.Running,
+			CPUShares:   pending.CPUShares + add.CPUShares,
+			MemoryBytes: pending.MemoryBytes + add.MemoryBytes,
+		}
+		if err := s.repo.CheckQuota(ctx, ownerID, ownerRole, withPending); err != nil {
+			parsingErrors = append(parsingErrors, fmt.Sprintf("Row %d: %s", rowNum, err.Error()))
+			continue
+		}
+
+		if err := s.dockerClient.PullImage(ctx, imageName); err != nil {
+			s.logger.Error("Failed to pull image", "image", imageName, "error", err)
+			parsingErrors = append(parsingErrors, fmt.Sprintf("Row %d: Failed to pull image - %s", rowNum, err.Error()))
+			continue
+		}
+		containerID, err := s.dockerClient.StartContainer(ctx, containerName, imageName, spec)
 		if err != nil {
 			s.logger.Error("Failed to start Docker container", "error", err)
 			parsingErrors = append(parsingErrors, fmt.Sprintf("Row %d: Failed to start container - %s", rowNum, err.Error()))
@@ -222,8 +537,13 @@ func (s *containerService) ImportContainers(ctx context.Context, buf []byte) (*d
 			ContainerName: containerName,
 			ImageName:     imageName,
 			Status:        "running",
+			Spec:          *spec,
+			OwnerID:       ownerID,
+			OwnerRole:     ownerRole,
+			TenantID:      tenantID,
 		}
 		containersToCreate = append(containersToCreate, container)
+		pending = withPending
 	}
 
 	if len(containersToCreate) == 0 && len(parsingErrors) > 0 {
@@ -240,9 +560,11 @@ func (s *containerService) ImportContainers(ctx context.Context, buf []byte) (*d
 		return &dto.ImportResult{SuccessfulCount: 0, FailedCount: 0}, nil
 	}
 
+	utils.ProgressFromContext(ctx).Step(model.JobStepPersist, "")
 	successfulRepoImports, failedRepoImports, err := s.repo.CreateManyContainers(ctx, containersToCreate)
 	if err != nil {
 		s.logger.Error("Repository failed during CreateManyContainers", "error", err)
+		s.stopImportedContainers(ctx, containersToCreate)
 		failedItemsFromRepo := make([]string, len(containersToCreate))
 		for i, ctn := range containersToCreate {
 			failedItemsFromRepo[i] = fmt.Sprintf("%s (repository error)", ctn.ContainerName)
@@ -267,11 +589,22 @@ func (s *containerService) ImportContainers(ctx context.Context, buf []byte) (*d
 	for _, ctn := range failedRepoImports {
 		result.FailedItems = append(result.FailedItems, fmt.Sprintf("%s (repository error)", ctn.ContainerName))
 	}
+	s.stopImportedContainers(ctx, failedRepoImports)
 
 	s.logger.Info("Containers imported successfully", "successfulCount", result.SuccessfulCount, "failedCount", result.FailedCount)
 	return result, nil
 }
 
+// stopImportedContainers stops the Docker containers of an import that will
+// not be stored, also when the import was cancelled.
+func (s *containerService) stopImportedContainers(ctx context.Context, containers []model.Container) {
+	for _, ctn := range containers {
+		if err := s.dockerClient.StopContainer(context.WithoutCancel(ctx), ctn.ContainerID); err != nil {
+			s.logger.Error("Failed to stop Docker container of failed import", "containerID", ctn.ContainerID, "error", err)
+		}
+	}
+}
+
 func (s *containerService) ExportContainers(ctx context.Context, containerFilter *dto.ContainerFilter, from, to int, sortBy, sortOrder string) (*dto.ExportData, error) {
 	_, containers, err := s.repo.ViewAllContainers(ctx, containerFilter, from, to, sortBy, sortOrder)
 	if err != nil {
@@ -287,7 +620,7 @@ func (s *containerService) ExportContainers(ctx context.Context, containerFilter
 		return nil, fmt.Errorf("failed to set sheet name: %w", err)
 	}
 
-	cols := []string{"ID", "Container ID", "Container Name", "Image Name", "Status"}
+	cols := append([]string{"ID", "Container ID", "Container Name", "Image Name", "Status"}, specColumns...)
 	for i, col := range cols {
 		cell, err := excelize.CoordinatesToCellName(i+1, 1)
 		if err != nil {
@@ -309,6 +642,7 @@ func (s *containerService) ExportContainers(ctx context.Context, containerFilter
 			container.ImageName,
 			container.Status,
 		}
+		values = append(values, formatSpecCells(container.Spec)...)
 
 		for colIdx, value := range values {
 			cell := fmt.Sprintf("%c%d", 'A'+colIdx, rowNum)
@@ -346,8 +680,8 @@ func (s *containerService) GetAllContainers(ctx context.Context) ([]dto.Containe
 	return containers, nil
 }
 
-func (s *containerService) AddContainerStatus(ctx context.Context, id uint, status string) error {
-	if err := s.repo.AddContainerStatus(ctx, id, status); err != nil {
+func (s *containerService) AddContainerStatus(ctx context.Context, id uint, status string, timestamp time.Time) error {
+	if err := s.repo.AddContainerStatus(ctx, id, status, timestamp); err != nil {
 		s.logger.Error("Failed to add container status", "id", id, "status", status, "error", err)
 		return fmt.Errorf("failed to add container status: %w", err)
 	}
@@ -378,8 +712,8 @@ func (s *containerService) GetNumRunningContainers(ctx context.Context) (int64,
 	return numOnContainers, nil
 }
 
-func (s *containerService) GetContainerUptimeRatio(ctx context.Context, startTime, endTime time.Time) (float64, error) {
-	uptimeRatio, err := s.repo.GetContainerUptimeRatio(ctx, startTime, endTime)
+func (s *containerService) GetContainerUptimeRatio(ctx context.Context, startTime, endTime time.Time, mode string) (float64, error) {
+	uptimeRatio, err := s.repo.GetContainerUptimeRatio(ctx, startTime, endTime, mode)
 	if err != nil {
 		s.logger.Error("Failed to get container uptime ratio", "startTime", startTime, "endTime", endTime, "error", err)
 		return 0, fmt.Errorf("failed to get container uptime ratio: %w", err)
@@ -389,8 +723,8 @@ func (s *containerService) GetContainerUptimeRatio(ctx context.Context, startTim
 	return uptimeRatio, nil
 }
 
-func (s *containerService) GetContainerUptimeDuration(ctx context.Context, startTime, endTime time.Time) (*dto.UptimeDetails, error) {
-	uptimeDetails, err := s.repo.GetContainerUptimeDuration(ctx, startTime, endTime)
+func (s *containerService) GetContainerUptimeDuration(ctx context.Context, startTime, endTime time.Time, mode string) (*dto.UptimeDetails, error) {
+	uptimeDetails, err := s.repo.GetContainerUptimeDuration(ctx, startTime, endTime, mode)
 	if err != nil {
 		s.logger.Error("Failed to get container uptime duration", "startTime", startTime, "endTime", endTime, "error", err)
 		return nil, fmt.Errorf("failed to get container uptime duration: %w", err)
@@ -399,3 +733,39 @@ func (s *containerService) GetContainerUptimeDuration(ctx context.Context, start
 	s.logger.Info("Container uptime duration retrieved successfully", "totalUptime", uptimeDetails.TotalUptime)
 	return uptimeDetails, nil
 }
+
+func (s *containerService) GetContainerStatusHistory(ctx context.Context, id uint, from, to time.Time, status string, size int, cursor string) (*dto.StatusHistory, error) {
+	if status != "" && !repository.IsValidStatus(status) {
+		s.logger.Warn("Invalid status filter for status history", "id", id, "status", status)
+		return nil, fmt.Errorf("%w: %q", ErrInvalidStatus, status)
+	}
+
+	if _, err := s.repo.GetContainerByID(ctx, id); err != nil {
+		if repository.IsNotFound(err) {
+			s.logger.Warn("Container not found for status history", "id", id)
+			return nil, fmt.Errorf("%w: %d", ErrContainerNotFound, id)
+		}
+		s.logger.Error("Failed to retrieve container for status history", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to retrieve container for status history: %w", err)
+	}
+
+	history, err := s.repo.GetContainerStatusHistory(ctx, id, from, to, status, size, cursor)
+	if err != nil {
+		s.logger.Error("Failed to get container status history", "id", id, "from", from, "to", to, "error", err)
+		return nil, fmt.Errorf("failed to get container status history: %w", err)
+	}
+
+	s.logger.Info("Container status history retrieved successfully", "id", id, "spans", len(history.Spans))
+	return history, nil
+}
+
+// ownerFromContext returns the user, role and tenant that new containers
+// belong to. The role is kept on the container so that its quota applies
+// whoever starts it later.
+func ownerFromContext(ctx context.Context) (uint, string, string) {
+	claims, ok := utils.ClaimsFromContext(ctx)
+	if !ok {
+		return 0, "", ""
+	}
+	return claims.UserID, claims.Role, claims.TenantID
+}
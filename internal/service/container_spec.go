@@ -0,0 +1,221 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"thanhnt208/container-adm-service/internal/model"
+)
+
+// Spreadsheet layout used by import/export for the container spec:
+//
+//	Ports          8080:80,127.0.0.1:5353:53/udp
+//	Env            KEY=value;OTHER=value
+//	Volumes        /srv/data:/data,cache:/cache:ro
+//	Labels         team=core;env=dev
+//	Command        python app.py --port 8080 (split on whitespace)
+//	Restart Policy no | always | on-failure | unless-stopped
+//	CPU Shares     1024
+//	Memory Bytes   536870912
+var specColumns = []string{"Ports", "Env", "Volumes", "Labels", "Command", "Restart Policy", "CPU Shares", "Memory Bytes"}
+
+func cellAt(row []string, idx int) string {
+	if idx < len(row) {
+		return strings.TrimSpace(row[idx])
+	}
+	return ""
+}
+
+// parseSpecCells builds a container spec from the spec columns of an import row.
+func parseSpecCells(cells []string) (*model.ContainerSpec, error) {
+	spec := &model.ContainerSpec{}
+
+	if raw := cellAt(cells, 0); raw != "" {
+		for _, item := range splitList(raw, ",") {
+			port, err := parsePortBinding(item)
+			if err != nil {
+				return nil, err
+			}
+			spec.Ports = append(spec.Ports, port)
+		}
+	}
+
+	if raw := cellAt(cells, 1); raw != "" {
+		env, err := parseKeyValues(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid env: %w", err)
+		}
+		spec.Env = env
+	}
+
+	if raw := cellAt(cells, 2); raw != "" {
+		for _, item := range splitList(raw, ",") {
+			volume, err := parseVolumeMount(item)
+			if err != nil {
+				return nil, err
+			}
+			spec.Volumes = append(spec.Volumes, volume)
+		}
+	}
+
+	if raw := cellAt(cells, 3); raw != "" {
+		labels, err := parseKeyValues(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid labels: %w", err)
+		}
+		spec.Labels = labels
+	}
+
+	if raw := cellAt(cells, 4); raw != "" {
+		spec.Command = strings.Fields(raw)
+	}
+
+	spec.RestartPolicy = cellAt(cells, 5)
+
+	if raw := cellAt(cells, 6); raw != "" {
+		shares, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpu shares: %s", raw)
+		}
+		spec.CPUShares = shares
+	}
+
+	if raw := cellAt(cells, 7); raw != "" {
+		memory, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory bytes: %s", raw)
+		}
+		spec.MemoryBytes = memory
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// formatSpecCells is the inverse of parseSpecCells, used when exporting.
+func formatSpecCells(spec model.ContainerSpec) []interface{} {
+	ports := make([]string, 0, len(spec.Ports))
+	for _, p := range spec.Ports {
+		value := strconv.Itoa(p.ContainerPort)
+		if p.HostPort > 0 {
+			value = strconv.Itoa(p.HostPort) + ":" + value
+			if p.HostIP != "" {
+				value = p.HostIP + ":" + value
+			}
+		}
+		if p.Protocol != "" && p.Protocol != "tcp" {
+			value += "/" + p.Protocol
+		}
+		ports = append(ports, value)
+	}
+
+	volumes := make([]string, 0, len(spec.Volumes))
+	for _, v := range spec.Volumes {
+		value := v.Source + ":" + v.Target
+		if v.ReadOnly {
+			value += ":ro"
+		}
+		volumes = append(volumes, value)
+	}
+
+	return []interface{}{
+		strings.Join(ports, ","),
+		formatKeyValues(spec.Env),
+		strings.Join(volumes, ","),
+		formatKeyValues(spec.Labels),
+		strings.Join(spec.Command, " "),
+		spec.RestartPolicy,
+		formatResource(spec.CPUShares),
+		formatResource(spec.MemoryBytes),
+	}
+}
+
+func formatResource(value int64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatInt(value, 10)
+}
+
+func splitList(raw, sep string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parsePortBinding(raw string) (model.PortBinding, error) {
+	var binding model.PortBinding
+
+	if slash := strings.LastIndex(raw, "/"); slash >= 0 {
+		binding.Protocol = strings.ToLower(raw[slash+1:])
+		raw = raw[:slash]
+	}
+
+	parts := strings.Split(raw, ":")
+	var err error
+	switch len(parts) {
+	case 1:
+		binding.ContainerPort, err = strconv.Atoi(parts[0])
+	case 2:
+		if binding.HostPort, err = strconv.Atoi(parts[0]); err == nil {
+			binding.ContainerPort, err = strconv.Atoi(parts[1])
+		}
+	case 3:
+		binding.HostIP = parts[0]
+		if binding.HostPort, err = strconv.Atoi(parts[1]); err == nil {
+			binding.ContainerPort, err = strconv.Atoi(parts[2])
+		}
+	default:
+		return binding, fmt.Errorf("invalid port mapping: %s", raw)
+	}
+	if err != nil {
+		return binding, fmt.Errorf("invalid port mapping %s: %w", raw, err)
+	}
+
+	return binding, nil
+}
+
+func parseVolumeMount(raw string) (model.VolumeMount, error) {
+	parts := strings.Split(raw, ":")
+	switch {
+	case len(parts) == 2:
+		return model.VolumeMount{Source: parts[0], Target: parts[1]}, nil
+	case len(parts) == 3 && (parts[2] == "ro" || parts[2] == "rw"):
+		return model.VolumeMount{Source: parts[0], Target: parts[1], ReadOnly: parts[2] == "ro"}, nil
+	default:
+		return model.VolumeMount{}, fmt.Errorf("invalid volume mapping: %s", raw)
+	}
+}
+
+func parseKeyValues(raw string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, item := range splitList(raw, ";") {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("expected KEY=VALUE, got %q", item)
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	return values, nil
+}
+
+func formatKeyValues(values map[string]string) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := make([]string, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, key+"="+values[key])
+	}
+	return strings.Join(items, ";")
+}
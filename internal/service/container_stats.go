@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+)
+
+var ErrContainerNotRunning = errors.New("container is not running")
+
+// GetContainerStats returns a stats snapshot of a container the caller can
+// see. Docker reports nothing useful for a container that is not running,
+// so those are rejected with ErrContainerNotRunning.
+func (s *containerService) GetContainerStats(ctx context.Context, id uint) (*dto.ContainerStats, error) {
+	container, err := s.statsContainer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.dockerClient.Stats(ctx, container.ContainerID)
+	if err != nil {
+		s.logger.Error("Failed to get container stats", "id", id, "containerID", container.ContainerID, "error", err)
+		return nil, fmt.Errorf("failed to get container stats: %w", err)
+	}
+	return stats, nil
+}
+
+// StreamContainerStats streams stats snapshots of a container the caller can
+// see. The returned channels behave like those of IDockerClient.StreamStats.
+func (s *containerService) StreamContainerStats(ctx context.Context, id uint) (<-chan dto.ContainerStats, <-chan error, error) {
+	container, err := s.statsContainer(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.logger.Info("Streaming container stats", "id", id, "containerID", container.ContainerID)
+	stats, errs := s.dockerClient.StreamStats(ctx, container.ContainerID)
+	return stats, errs, nil
+}
+
+func (s *containerService) statsContainer(ctx context.Context, id uint) (*model.Container, error) {
+	container, err := s.repo.GetContainerByID(ctx, id)
+	if repository.IsNotFound(err) || (err == nil && container == nil) {
+		s.logger.Warn("Container not found for stats", "id", id)
+		return nil, fmt.Errorf("%w: %d", ErrContainerNotFound, id)
+	}
+	if err != nil {
+		s.logger.Error("Failed to retrieve container for stats", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to retrieve container for stats: %w", err)
+	}
+	if !repository.IsActiveStatus(container.Status) {
+		s.logger.Warn("Stats requested for container that is not running", "id", id, "status", container.Status)
+		return nil, fmt.Errorf("%w: status is %s", ErrContainerNotRunning, container.Status)
+	}
+	return container, nil
+}
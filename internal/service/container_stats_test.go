@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+)
+
+func TestGetContainerStatsRejectsUnavailableContainers(t *testing.T) {
+	tests := []struct {
+		name string
+		id   uint
+		want error
+	}{
+		{"unknown container", 9, ErrContainerNotFound},
+		{"stopped container", 1, ErrContainerNotRunning},
+	}
+
+	svc := &containerService{
+		repo:   newFakeContainerRepository(model.Container{ID: 1, ContainerID: "abc", Status: repository.StatusStopped}),
+		logger: nopLogger{},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.GetContainerStats(context.Background(), tt.id); !errors.Is(err, tt.want) {
+				t.Errorf("GetContainerStats() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
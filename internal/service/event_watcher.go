@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"thanhnt208/container-adm-service/external/client"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
+)
+
+const (
+	eventWatcherMinBackoff = time.Second
+	eventWatcherMaxBackoff = 30 * time.Second
+
+	// eventWatcherCheckpoint names the checkpoint of the Docker events stream.
+	eventWatcherCheckpoint = "docker_events"
+)
+
+// IEventWatcher turns the Docker events stream into status updates and
+// status history, so uptime does not depend on an external reporter.
+type IEventWatcher interface {
+	Run(ctx context.Context)
+}
+
+type eventWatcher struct {
+	repo         repository.IContainerRepository
+	checkpoints  repository.ICheckpointRepository
+	logger       logger.ILogger
+	dockerClient client.IDockerClient
+
+	lastSeen    time.Time
+	lastEventID string
+}
+
+func NewEventWatcher(repo repository.IContainerRepository, checkpoints repository.ICheckpointRepository, logger logger.ILogger, dockerClient client.IDockerClient) IEventWatcher {
+	return &eventWatcher{
+		repo:         repo,
+		checkpoints:  checkpoints,
+		logger:       logger,
+		dockerClient: dockerClient,
+	}
+}
+
+// Run subscribes to Docker events until ctx is cancelled. When the stream
+// drops it reconnects with exponential backoff and resumes from the last
+// event it handled, so nothing that happened in between is lost. The last
+// event is checkpointed in Postgres, so a restarted watcher resumes from
+// there as well, within the events the daemon still keeps.
+func (w *eventWatcher) Run(ctx context.Context) {
+	w.loadCheckpoint(ctx)
+	backoff := eventWatcherMinBackoff
+
+	for {
+		since := w.lastSeen
+		if since.IsZero() {
+			since = time.Now().UTC()
+		}
+
+		w.logger.Info("Subscribing to Docker events", "since", since)
+		handled, err := w.consume(ctx, since)
+		if ctx.Err() != nil {
+			w.logger.Info("Stopping Docker event watcher due to context cancellation")
+			return
+		}
+		if handled > 0 {
+			backoff = eventWatcherMinBackoff
+		}
+
+		w.logger.Warn("Docker events stream interrupted, reconnecting", "error", err, "retryIn", backoff)
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Stopping Docker event watcher due to context cancellation")
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > eventWatcherMaxBackoff {
+			backoff = eventWatcherMaxBackoff
+		}
+	}
+}
+
+func (w *eventWatcher) consume(ctx context.Context, since time.Time) (int, error) {
+	events, errs := w.dockerClient.Events(ctx, since)
+
+	handled := 0
+	for event := range events {
+		// Resuming from lastSeen replays the event we stopped on; skip it.
+		eventID := event.ContainerID + "/" + event.Action + "/" + event.Time.String()
+		if eventID == w.lastEventID {
+			continue
+		}
+
+		w.handleEvent(ctx, event)
+		w.lastSeen = event.Time
+		w.lastEventID = eventID
+		w.saveCheckpoint(ctx)
+		handled++
+	}
+
+	return handled, <-errs
+}
+
+func (w *eventWatcher) loadCheckpoint(ctx context.Context) {
+	checkpoint, err := w.checkpoints.GetCheckpoint(ctx, eventWatcherCheckpoint)
+	if err != nil {
+		w.logger.Warn("Failed to load Docker events checkpoint, starting from now", "error", err)
+		return
+	}
+	if checkpoint == nil {
+		return
+	}
+
+	w.lastSeen = checkpoint.LastSeen
+	w.lastEventID = checkpoint.LastEventID
+	w.logger.Info("Resuming Docker events from checkpoint", "since", w.lastSeen)
+}
+
+func (w *eventWatcher) saveCheckpoint(ctx context.Context) {
+	// The event was handled, so record it even when shutdown began meanwhile.
+	err := w.checkpoints.SaveCheckpoint(context.WithoutCancel(ctx), &model.EventCheckpoint{
+		Source:      eventWatcherCheckpoint,
+		LastSeen:    w.lastSeen,
+		LastEventID: w.lastEventID,
+	})
+	if err != nil {
+		w.logger.Warn("Failed to save Docker events checkpoint", "error", err)
+	}
+}
+
+func (w *eventWatcher) handleEvent(ctx context.Context, event client.ContainerEvent) {
+	status, ok := statusFromDockerEvent(event.Action)
+	if !ok {
+		w.logger.Debug("Ignoring Docker event", "containerID", event.ContainerID, "action", event.Action)
+		return
+	}
+
+	container, err := w.repo.GetContainerByContainerID(ctx, event.ContainerID)
+	if err != nil {
+		w.logger.Debug("Docker event for unmanaged container", "containerID", event.ContainerID, "action", event.Action)
+		return
+	}
+
+	if _, _, err := w.repo.UpdateObservedStatus(ctx, container.ID, status, event.Time, dto.StatusOrigin{}); err != nil {
+		w.logger.Error("Failed to update container status from Docker event", "id", container.ID, "action", event.Action, "error", err)
+		return
+	}
+
+	if err := w.repo.AddContainerStatus(ctx, container.ID, status, event.Time); err != nil {
+		w.logger.Error("Failed to add container status from Docker event", "id", container.ID, "action", event.Action, "error", err)
+		return
+	}
+
+	w.logger.Info("Container status updated from Docker event", "id", container.ID, "action", event.Action, "status", status, "eventTime", event.Time)
+}
+
+func statusFromDockerEvent(action string) (string, bool) {
+	switch action {
+	case "start", "unpause", "restart":
+		return repository.StatusRunning, true
+	case "pause":
+		return repository.StatusPaused, true
+	case "die", "oom":
+		return repository.StatusExited, true
+	case "stop":
+		// A stop is preceded by a die; it marks the exit as intended.
+		return repository.StatusStopped, true
+	case "destroy":
+		return repository.StatusMissing, true
+	case "health_status: healthy", "health_status: unhealthy":
+		// Health checks only run while the container is up, so they confirm the run state.
+		return repository.StatusRunning, true
+	}
+	return "", false
+}
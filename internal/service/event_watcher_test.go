@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"thanhnt208/container-adm-service/external/client"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"time"
+)
+
+func TestHandleEventAppliesObservedStartOverQuota(t *testing.T) {
+	repo := newFakeContainerRepository(
+		model.Container{ID: 1, ContainerID: "aaa", Status: repository.StatusStopped},
+	)
+	repo.overQuota = true
+	w := &eventWatcher{repo: repo, logger: nopLogger{}}
+
+	// Started by hand with docker start.
+	w.handleEvent(context.Background(), client.ContainerEvent{ContainerID: "aaa", Action: "start", Time: time.Now()})
+
+	if got := repo.containers[1].Status; got != repository.StatusRunning {
+		t.Errorf("status = %q, want %q", got, repository.StatusRunning)
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"thanhnt208/container-adm-service/external/client"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// nopLogger discards everything logged by the code under test.
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+func (nopLogger) Fatal(string, ...interface{}) {}
+func (nopLogger) Sync() error                  { return nil }
+
+// fakeDockerClient serves containers from a map keyed by Docker ID. Methods
+// a test does not set up panic through the embedded nil interface.
+type fakeDockerClient struct {
+	client.IDockerClient
+
+	mu         sync.Mutex
+	containers map[string]client.ContainerState
+}
+
+func (f *fakeDockerClient) ListContainers(ctx context.Context) ([]client.ContainerState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	states := make([]client.ContainerState, 0, len(f.containers))
+	for _, state := range f.containers {
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+func (f *fakeDockerClient) InspectContainer(ctx context.Context, containerID string) (*client.ContainerState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	state, ok := f.containers[containerID]
+	if !ok {
+		return nil, client.ErrContainerNotFound
+	}
+	return &state, nil
+}
+
+// statusDrift is a drift recorded through RecordStatusDrift.
+type statusDrift struct {
+	id               uint
+	previous, status string
+}
+
+// fakeContainerRepository keeps containers in memory and records the
+// updates and drifts the code under test makes. With overQuota set,
+// requested starts fail as the real repository's quota check would.
+type fakeContainerRepository struct {
+	repository.IContainerRepository
+
+	mu         sync.Mutex
+	containers map[uint]model.Container
+	updates    map[uint]map[string]interface{}
+	drifts     []statusDrift
+	overQuota  bool
+}
+
+func newFakeContainerRepository(containers ...model.Container) *fakeContainerRepository {
+	f := &fakeContainerRepository{
+		containers: make(map[uint]model.Container),
+		updates:    make(map[uint]map[string]interface{}),
+	}
+	for _, ctn := range containers {
+		f.containers[ctn.ID] = ctn
+	}
+	return f
+}
+
+func (f *fakeContainerRepository) ListAllContainers(ctx context.Context) ([]model.Container, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	containers := make([]model.Container, 0, len(f.containers))
+	for _, ctn := range f.containers {
+		containers = append(containers, ctn)
+	}
+	return containers, nil
+}
+
+func (f *fakeContainerRepository) GetContainerByID(ctx context.Context, id uint) (*model.Container, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ctn, ok := f.containers[id]
+	if !ok {
+		return nil, nil
+	}
+	return &ctn, nil
+}
+
+func (f *fakeContainerRepository) GetContainerByContainerID(ctx context.Context, containerID string) (*model.Container, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, ctn := range f.containers {
+		if ctn.ContainerID == containerID {
+			return &ctn, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeContainerRepository) UpdateContainer(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Container, error) {
+	status, _ := updateData["status"].(string)
+	if err := f.checkStart(id, status); err != nil {
+		return nil, err
+	}
+	return f.UpdateObservedContainer(ctx, id, updateData)
+}
+
+func (f *fakeContainerRepository) UpdateObservedContainer(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Container, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ctn := f.containers[id]
+	if status, ok := updateData["status"].(string); ok {
+		ctn.Status = status
+	}
+	if containerID, ok := updateData["container_id"].(string); ok {
+		ctn.ContainerID = containerID
+	}
+	f.containers[id] = ctn
+	f.updates[id] = updateData
+	return &ctn, nil
+}
+
+func (f *fakeContainerRepository) UpdateContainerStatus(ctx context.Context, id uint, status string, at time.Time, origin dto.StatusOrigin) (*model.Container, bool, error) {
+	if err := f.checkStart(id, status); err != nil {
+		return nil, false, err
+	}
+	return f.UpdateObservedStatus(ctx, id, status, at, origin)
+}
+
+func (f *fakeContainerRepository) UpdateObservedStatus(ctx context.Context, id uint, status string, at time.Time, origin dto.StatusOrigin) (*model.Container, bool, error) {
+	ctn, err := f.UpdateObservedContainer(ctx, id, map[string]interface{}{"status": status, "last_status_at": at})
+	return ctn, err == nil, err
+}
+
+func (f *fakeContainerRepository) checkStart(id uint, status string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.overQuota && repository.IsActiveStatus(status) && !repository.IsActiveStatus(f.containers[id].Status) {
+		return repository.ErrQuotaExceeded
+	}
+	return nil
+}
+
+func (f *fakeContainerRepository) RecordStatusDrift(ctx context.Context, id uint, previousStatus, status, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.drifts = append(f.drifts, statusDrift{id: id, previous: previousStatus, status: status})
+	return nil
+}
+
+func (f *fakeContainerRepository) AddContainerStatus(ctx context.Context, id uint, status string, timestamp time.Time) error {
+	return nil
+}
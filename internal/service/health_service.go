@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
+)
+
+// HealthCheck reports whether a dependency is reachable.
+type HealthCheck func(ctx context.Context) error
+
+type IHealthService interface {
+	Liveness() *dto.HealthReport
+	Readiness(ctx context.Context) *dto.HealthReport
+}
+
+type healthService struct {
+	logger  logger.ILogger
+	checks  map[string]HealthCheck
+	timeout time.Duration
+}
+
+func NewHealthService(logger logger.ILogger, checks map[string]HealthCheck, timeout time.Duration) IHealthService {
+	return &healthService{
+		logger:  logger,
+		checks:  checks,
+		timeout: timeout,
+	}
+}
+
+// Liveness only reports that the process is able to serve requests. It does
+// not look at dependencies, so an outage elsewhere does not get it restarted.
+func (s *healthService) Liveness() *dto.HealthReport {
+	return &dto.HealthReport{Status: dto.HealthStatusUp}
+}
+
+// Readiness runs every dependency check concurrently, each bounded by the
+// configured timeout. The service is ready only when all checks pass.
+func (s *healthService) Readiness(ctx context.Context) *dto.HealthReport {
+	report := &dto.HealthReport{
+		Status: dto.HealthStatusUp,
+		Checks: make(map[string]dto.DependencyHealth, len(s.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range s.checks {
+		wg.Add(1)
+		go func(name string, check HealthCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check(checkCtx)
+			result := dto.DependencyHealth{
+				Status:    dto.HealthStatusUp,
+				LatencyMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				result.Status = dto.HealthStatusDown
+				result.Error = err.Error()
+				s.logger.Warn("Health check failed", "dependency", name, "error", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if err != nil {
+				report.Status = dto.HealthStatusDown
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	return report
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync/atomic"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
+
+	"github.com/distribution/reference"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrImagePolicyViolation = errors.New("image rejected by policy")
+
+// ImagePolicyRules is the YAML policy file:
+//
+//	allowed_registries: [docker.io, ghcr.io]
+//	allow: ["library/*", "myorg/**"]
+//	deny: ["*/*miner*"]
+//	deny_latest: true
+//	require_digest: false
+//
+// Registries are matched against the registry host of the image, and allow
+// and deny patterns against its repository path, e.g. "library/nginx" for
+// "nginx:1.27". Patterns use path.Match syntax, so "*" does not cross a
+// "/"; a trailing "/**" matches everything below a prefix. Deny wins over
+// allow, and an empty list allows everything.
+type ImagePolicyRules struct {
+	AllowedRegistries []string `yaml:"allowed_registries"`
+	Allow             []string `yaml:"allow"`
+	Deny              []string `yaml:"deny"`
+	DenyLatest        bool     `yaml:"deny_latest"`
+	RequireDigest     bool     `yaml:"require_digest"`
+}
+
+type IImagePolicy interface {
+	Check(imageName string) error
+	Watch(ctx context.Context, interval time.Duration)
+}
+
+type imagePolicy struct {
+	file    string
+	logger  logger.ILogger
+	rules   atomic.Pointer[ImagePolicyRules]
+	modTime time.Time
+}
+
+// NewImagePolicy loads the policy from file. Without a file every image is
+// allowed.
+func NewImagePolicy(file string, logger logger.ILogger) (IImagePolicy, error) {
+	p := &imagePolicy{
+		file:   file,
+		logger: logger,
+	}
+	p.rules.Store(&ImagePolicyRules{})
+
+	if file == "" {
+		return p, nil
+	}
+	if _, err := p.reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Check validates an image reference before it is pulled. Violations wrap
+// ErrImagePolicyViolation.
+func (p *imagePolicy) Check(imageName string) error {
+	rules := p.rules.Load()
+
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return fmt.Errorf("%w: invalid image reference %q: %v", ErrImagePolicyViolation, imageName, err)
+	}
+
+	if registry := reference.Domain(named); len(rules.AllowedRegistries) > 0 && !containsFold(rules.AllowedRegistries, registry) {
+		return fmt.Errorf("%w: registry %q of %q is not allowed", ErrImagePolicyViolation, registry, imageName)
+	}
+
+	repository := reference.Path(named)
+	if pattern, ok := matchRepository(rules.Deny, repository); ok {
+		return fmt.Errorf("%w: repository %q matches deny pattern %q", ErrImagePolicyViolation, repository, pattern)
+	}
+	if _, ok := matchRepository(rules.Allow, repository); len(rules.Allow) > 0 && !ok {
+		return fmt.Errorf("%w: repository %q is not in the allow-list", ErrImagePolicyViolation, repository)
+	}
+
+	_, digested := named.(reference.Digested)
+	if rules.RequireDigest && !digested {
+		return fmt.Errorf("%w: %q must be pinned to a digest", ErrImagePolicyViolation, imageName)
+	}
+
+	if rules.DenyLatest && !digested {
+		tagged, ok := named.(reference.Tagged)
+		if !ok || tagged.Tag() == "latest" {
+			return fmt.Errorf("%w: %q uses the latest tag, pin a version", ErrImagePolicyViolation, imageName)
+		}
+	}
+
+	return nil
+}
+
+// Watch reloads the policy whenever the file changes. An invalid file is
+// logged and the previous rules stay in force. A non-positive interval
+// disables hot reload.
+func (p *imagePolicy) Watch(ctx context.Context, interval time.Duration) {
+	if p.file == "" {
+		return
+	}
+	if interval <= 0 {
+		p.logger.Info("Image policy hot reload disabled", "file", p.file)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := p.reload()
+			if err != nil {
+				p.logger.Error("Failed to reload image policy, keeping previous rules", "file", p.file, "error", err)
+				continue
+			}
+			if changed {
+				p.logger.Info("Image policy reloaded", "file", p.file)
+			}
+		}
+	}
+}
+
+func (p *imagePolicy) reload() (bool, error) {
+	info, err := os.Stat(p.file)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat image policy file: %w", err)
+	}
+	if info.ModTime().Equal(p.modTime) {
+		return false, nil
+	}
+
+	raw, err := os.ReadFile(p.file)
+	if err != nil {
+		return false, fmt.Errorf("failed to read image policy file: %w", err)
+	}
+
+	var rules ImagePolicyRules
+	if err := yaml.Unmarshal(raw, &rules); err != nil {
+		return false, fmt.Errorf("failed to decode image policy file: %w", err)
+	}
+	patterns := append(append([]string{}, rules.Allow...), rules.Deny...)
+	for _, pattern := range patterns {
+		if _, err := path.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+			return false, fmt.Errorf("invalid repository pattern %q: %w", pattern, err)
+		}
+	}
+
+	p.rules.Store(&rules)
+	p.modTime = info.ModTime()
+	return true, nil
+}
+
+func matchRepository(patterns []string, repository string) (string, bool) {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+			if strings.HasPrefix(repository, prefix+"/") {
+				return pattern, true
+			}
+			continue
+		}
+		if matched, _ := path.Match(pattern, repository); matched {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writePolicy(t *testing.T, file, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write policy: %v", err)
+	}
+	if err := os.Chtimes(file, modTime, modTime); err != nil {
+		t.Fatalf("failed to set policy mtime: %v", err)
+	}
+}
+
+func newTestImagePolicy(t *testing.T, content string) *imagePolicy {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	writePolicy(t, file, content, time.Now())
+	p, err := NewImagePolicy(file, nopLogger{})
+	if err != nil {
+		t.Fatalf("NewImagePolicy() error = %v", err)
+	}
+	return p.(*imagePolicy)
+}
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestImagePolicyCheck(t *testing.T) {
+	p := newTestImagePolicy(t, `
+allowed_registries: [docker.io, ghcr.io]
+allow: ["library/*", "myorg/**"]
+deny: ["*/*miner*"]
+deny_latest: true
+`)
+
+	tests := []struct {
+		image   string
+		allowed bool
+	}{
+		{"nginx:1.27", true},
+		{"docker.io/library/redis:7", true},
+		{"ghcr.io/myorg/team/api:v2", true},
+		{"myorg/tool@" + testDigest, true},
+		{"quay.io/library/nginx:1.27", false},
+		{"someone/app:1.0", false},
+		{"library/xmrig-miner:1.0", false},
+		{"myorg/cryptominer:1.0", false},
+		{"nginx", false},
+		{"nginx:latest", false},
+		{"Not A Reference", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			err := p.Check(tt.image)
+			if tt.allowed && err != nil {
+				t.Errorf("Check() error = %v, want allowed", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrImagePolicyViolation) {
+				t.Errorf("Check() error = %v, want %v", err, ErrImagePolicyViolation)
+			}
+		})
+	}
+}
+
+func TestImagePolicyRequireDigest(t *testing.T) {
+	p := newTestImagePolicy(t, "require_digest: true\n")
+
+	if err := p.Check("nginx@" + testDigest); err != nil {
+		t.Errorf("Check() of a digest-pinned image error = %v", err)
+	}
+	if err := p.Check("nginx:1.27@" + testDigest); err != nil {
+		t.Errorf("Check() of a tagged, digest-pinned image error = %v", err)
+	}
+	if err := p.Check("nginx:1.27"); !errors.Is(err, ErrImagePolicyViolation) {
+		t.Errorf("Check() of a tag-only image error = %v, want %v", err, ErrImagePolicyViolation)
+	}
+}
+
+func TestImagePolicyWithoutFileAllowsEverything(t *testing.T) {
+	p, err := NewImagePolicy("", nopLogger{})
+	if err != nil {
+		t.Fatalf("NewImagePolicy() error = %v", err)
+	}
+	if err := p.Check("quay.io/anyone/anything:latest"); err != nil {
+		t.Errorf("Check() error = %v, want allowed", err)
+	}
+}
+
+func TestImagePolicyRejectsInvalidFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	writePolicy(t, file, `allow: ["[unclosed"]`, time.Now())
+	if _, err := NewImagePolicy(file, nopLogger{}); err == nil {
+		t.Error("NewImagePolicy() error = nil, want an invalid pattern error")
+	}
+}
+
+func TestImagePolicyReload(t *testing.T) {
+	p := newTestImagePolicy(t, `deny: ["library/redis"]`)
+	modTime := p.modTime
+
+	if err := p.Check("redis:7"); err == nil {
+		t.Fatal("Check() allowed a denied image")
+	}
+
+	changed, err := p.reload()
+	if err != nil || changed {
+		t.Fatalf("reload() of an unchanged file = %v, %v, want false, nil", changed, err)
+	}
+
+	modTime = modTime.Add(time.Minute)
+	writePolicy(t, p.file, `deny: ["library/nginx"]`, modTime)
+	changed, err = p.reload()
+	if err != nil || !changed {
+		t.Fatalf("reload() of a changed file = %v, %v, want true, nil", changed, err)
+	}
+	if err := p.Check("redis:7"); err != nil {
+		t.Errorf("Check() after reload error = %v, want allowed", err)
+	}
+	if err := p.Check("nginx:1.27"); err == nil {
+		t.Error("Check() after reload allowed a denied image")
+	}
+
+	// A broken file keeps the previous rules in force.
+	writePolicy(t, p.file, "deny: [", modTime.Add(time.Minute))
+	if _, err := p.reload(); err == nil {
+		t.Fatal("reload() of a broken file error = nil")
+	}
+	if err := p.Check("nginx:1.27"); err == nil {
+		t.Error("Check() after a failed reload allowed a denied image")
+	}
+}
+
+func TestImagePolicyWatchDisabled(t *testing.T) {
+	p := newTestImagePolicy(t, `deny: ["library/redis"]`)
+
+	done := make(chan struct{})
+	go func() {
+		p.Watch(context.Background(), 0)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Watch() with a zero interval did not return")
+	}
+}
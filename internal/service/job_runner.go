@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/utils"
+	"time"
+)
+
+const (
+	// JobProgressInterval is how often a running job stores its progress
+	// and checks whether it was cancelled.
+	JobProgressInterval = time.Second
+
+	// JobStaleAfter is how long a running job may go without storing
+	// progress before it is considered abandoned by its worker.
+	JobStaleAfter = 2 * time.Minute
+)
+
+// IJobRunner runs queued jobs on a pool of workers. Workers of several
+// instances can share the queue.
+type IJobRunner interface {
+	Run(ctx context.Context)
+}
+
+type jobRunner struct {
+	repo             repository.IJobRepository
+	containerService IContainerService
+	logger           logger.ILogger
+	workers          int
+	pollInterval     time.Duration
+}
+
+func NewJobRunner(repo repository.IJobRepository, containerService IContainerService, logger logger.ILogger, workers int, pollInterval time.Duration) IJobRunner {
+	return &jobRunner{
+		repo:             repo,
+		containerService: containerService,
+		logger:           logger,
+		workers:          workers,
+		pollInterval:     pollInterval,
+	}
+}
+
+// Run starts the workers and blocks until ctx is cancelled and they are
+// done. A non-positive number of workers disables the runner.
+func (r *jobRunner) Run(ctx context.Context) {
+	if r.workers <= 0 {
+		r.logger.Info("Job runner disabled")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < r.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.work(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(JobStaleAfter / 2)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.repo.FailStaleJobs(ctx, time.Now().Add(-JobStaleAfter)); err != nil {
+			r.logger.Error("Failed to fail stale jobs", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Stopping job runner due to context cancellation")
+			wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *jobRunner) work(ctx context.Context) {
+	for {
+		job, err := r.repo.ClaimJob(ctx)
+		if err != nil {
+			r.logger.Error("Failed to claim job", "error", err)
+		}
+		if job != nil {
+			r.runJob(ctx, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.pollInterval):
+		}
+	}
+}
+
+func (r *jobRunner) runJob(ctx context.Context, job *model.Job) {
+	r.logger.Info("Running job", "id", job.ID, "type", job.Type)
+
+	progress := &jobProgress{}
+	jobCtx, cancel := context.WithCancel(r.jobContext(ctx, job, progress))
+	defer cancel()
+
+	// Store progress while the job runs; the stored time also shows that
+	// this worker is alive.
+	var cancelled atomic.Bool
+	done := make(chan struct{})
+	reported := make(chan struct{})
+	go func() {
+		defer close(reported)
+		ticker := time.NewTicker(JobProgressInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			cancelRequested, err := r.repo.UpdateJobSteps(ctx, job.ID, progress.snapshot())
+			if err != nil {
+				continue
+			}
+			if cancelRequested && !cancelled.Swap(true) {
+				r.logger.Info("Cancelling job", "id", job.ID)
+				cancel()
+			}
+		}
+	}()
+
+	result, containerID, err := r.execute(jobCtx, job)
+	close(done)
+	<-reported
+
+	switch {
+	case err == nil:
+		job.Status = model.JobStatusSucceeded
+		progress.finish(model.JobStepSucceeded)
+	case cancelled.Load() && jobCtx.Err() != nil:
+		job.Status = model.JobStatusCancelled
+		job.Error = "job was cancelled"
+		progress.finish(model.JobStepFailed)
+	case ctx.Err() != nil:
+		job.Status = model.JobStatusFailed
+		job.Error = "job was interrupted because the service stopped"
+		progress.finish(model.JobStepFailed)
+	default:
+		job.Status = model.JobStatusFailed
+		job.Error = err.Error()
+		progress.finish(model.JobStepFailed)
+	}
+	if containerID != 0 {
+		job.ContainerID = containerID
+	}
+	if result != nil {
+		if job.Result, err = json.Marshal(result); err != nil {
+			r.logger.Error("Failed to encode job result", "id", job.ID, "error", err)
+		}
+	}
+	job.Steps = progress.snapshot()
+
+	// The outcome is stored even when the runner is shutting down.
+	if err := r.repo.FinishJob(context.WithoutCancel(ctx), job); err != nil {
+		r.logger.Error("Failed to store job outcome", "id", job.ID, "status", job.Status, "error", err)
+	}
+}
+
+// jobContext restores the caller that submitted the job, so the operation
+// is scoped, checked against quotas and audited as if it ran in the request.
+func (r *jobRunner) jobContext(ctx context.Context, job *model.Job, progress utils.ProgressReporter) context.Context {
+	if job.Actor.UserID != 0 || job.Actor.Username != "" {
+		ctx = utils.ContextWithClaims(ctx, &utils.Claims{
+			UserID:   job.Actor.UserID,
+			Username: job.Actor.Username,
+			Role:     job.Actor.Role,
+			TenantID: job.Actor.TenantID,
+			Scopes:   job.Actor.Scopes,
+		})
+	}
+	ctx = utils.ContextWithRequestMeta(ctx, utils.RequestMeta{
+		SourceIP:  job.Actor.SourceIP,
+		Transport: job.Actor.Transport,
+	})
+	if job.CorrelationID != "" {
+		ctx = utils.ContextWithCorrelationID(ctx, job.CorrelationID)
+	}
+	return utils.ContextWithProgress(ctx, progress)
+}
+
+// execute runs the operation of the job and returns its result and the
+// container it created or changed.
+func (r *jobRunner) execute(ctx context.Context, job *model.Job) (interface{}, uint, error) {
+	switch job.Type {
+	case model.JobTypeContainerCreate:
+		var req dto.CreateContainerRequest
+		if err := json.Unmarshal(job.Request, &req); err != nil {
+			return nil, 0, fmt.Errorf("failed to decode job request: %w", err)
+		}
+		id, err := r.containerService.CreateContainer(ctx, req.ContainerName, req.ImageName, &req.ContainerSpec)
+		if err != nil {
+			return nil, 0, err
+		}
+		return dto.CreateContainerJobResult{ID: id}, uint(id), nil
+
+	case model.JobTypeContainerImport:
+		var req dto.ImportContainersJobRequest
+		if err := json.Unmarshal(job.Request, &req); err != nil {
+			return nil, 0, fmt.Errorf("failed to decode job request: %w", err)
+		}
+		result, err := r.containerService.ImportContainers(ctx, req.File)
+		if result == nil {
+			return nil, 0, err
+		}
+		return result, 0, err
+
+	case model.JobTypeContainerUpdate:
+		var req dto.UpdateContainerJobRequest
+		if err := json.Unmarshal(job.Request, &req); err != nil {
+			return nil, 0, fmt.Errorf("failed to decode job request: %w", err)
+		}
+		container, err := r.containerService.UpdateContainer(ctx, req.ID, req.UpdateData)
+		if err != nil {
+			return nil, req.ID, err
+		}
+		return container, req.ID, nil
+
+	default:
+		return nil, 0, fmt.Errorf("unknown job type %q", job.Type)
+	}
+}
+
+// jobProgress collects the steps a job reports through its context.
+type jobProgress struct {
+	mu    sync.Mutex
+	steps []model.JobStep
+}
+
+func (p *jobProgress) Step(name, target string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.finishLocked(model.JobStepSucceeded)
+	p.steps = append(p.steps, model.JobStep{
+		Name:      name,
+		Target:    target,
+		Status:    model.JobStepRunning,
+		StartedAt: time.Now().UTC(),
+	})
+}
+
+func (p *jobProgress) Layer(id, status string, current, total int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.steps) == 0 {
+		return
+	}
+	step := &p.steps[len(p.steps)-1]
+	if step.Layers == nil {
+		step.Layers = make(map[string]model.JobLayer)
+	}
+	step.Layers[id] = model.JobLayer{Status: status, Current: current, Total: total}
+}
+
+// finish ends the current step with status.
+func (p *jobProgress) finish(status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.finishLocked(status)
+}
+
+func (p *jobProgress) finishLocked(status string) {
+	if len(p.steps) == 0 {
+		return
+	}
+	step := &p.steps[len(p.steps)-1]
+	if step.Status != model.JobStepRunning {
+		return
+	}
+	now := time.Now().UTC()
+	step.Status = status
+	step.FinishedAt = &now
+}
+
+// snapshot copies the steps so they can be stored while the job goes on.
+func (p *jobProgress) snapshot() []model.JobStep {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	steps := make([]model.JobStep, len(p.steps))
+	for i, step := range p.steps {
+		step.Layers = maps.Clone(step.Layers)
+		steps[i] = step
+	}
+	return steps
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"thanhnt208/container-adm-service/utils"
+	"time"
+)
+
+func TestJobProgress(t *testing.T) {
+	progress := &jobProgress{}
+	progress.Layer("ignored", "Downloading", 1, 2)
+	progress.finish(model.JobStepFailed)
+	if steps := progress.snapshot(); len(steps) != 0 {
+		t.Fatalf("steps = %+v before the first step, want none", steps)
+	}
+
+	progress.Step(model.JobStepPull, "nginx:1.27")
+	progress.Layer("a1", "Downloading", 10, 100)
+	progress.Layer("a1", "Download complete", 100, 100)
+
+	// A snapshot is not changed by later reports.
+	before := progress.snapshot()
+	progress.Layer("b2", "Waiting", 0, 0)
+	if _, ok := before[0].Layers["b2"]; ok {
+		t.Error("snapshot shares layers with the progress")
+	}
+
+	progress.Step(model.JobStepCreate, "web")
+
+	steps := progress.snapshot()
+	if len(steps) != 2 {
+		t.Fatalf("got %d steps, want 2", len(steps))
+	}
+	pull, create := steps[0], steps[1]
+	if pull.Status != model.JobStepSucceeded || pull.FinishedAt == nil {
+		t.Errorf("pull step = %+v, want succeeded once the next step started", pull)
+	}
+	if layer := pull.Layers["a1"]; layer.Status != "Download complete" || layer.Current != 100 {
+		t.Errorf("layer a1 = %+v, want the latest report", layer)
+	}
+	if create.Status != model.JobStepRunning || create.Target != "web" || create.FinishedAt != nil {
+		t.Errorf("create step = %+v, want running on web", create)
+	}
+
+	progress.finish(model.JobStepFailed)
+	progress.finish(model.JobStepSucceeded)
+	steps = progress.snapshot()
+	if steps[1].Status != model.JobStepFailed || steps[1].FinishedAt == nil {
+		t.Errorf("create step = %+v, want failed by the first finish", steps[1])
+	}
+	if steps[0].Status != model.JobStepSucceeded {
+		t.Errorf("pull step = %+v, want it left alone", steps[0])
+	}
+}
+
+// fakeJobRepository stores the outcome of finished jobs and asks the runner
+// to cancel when cancelRequested is set.
+type fakeJobRepository struct {
+	repository.IJobRepository
+
+	mu              sync.Mutex
+	cancelRequested bool
+	finished        []model.Job
+}
+
+func (f *fakeJobRepository) UpdateJobSteps(ctx context.Context, id string, steps []model.JobStep) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cancelRequested, nil
+}
+
+func (f *fakeJobRepository) FinishJob(ctx context.Context, job *model.Job) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.finished = append(f.finished, *job)
+	return nil
+}
+
+// fakeContainerService runs container updates through update.
+type fakeContainerService struct {
+	IContainerService
+	update func(ctx context.Context) (*model.Container, error)
+}
+
+func (f *fakeContainerService) UpdateContainer(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Container, error) {
+	utils.ProgressFromContext(ctx).Step(model.JobStepPull, "nginx:1.27")
+	return f.update(ctx)
+}
+
+func waitForCancel(ctx context.Context) (*model.Container, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(10 * JobProgressInterval):
+		return nil, errors.New("job was not cancelled")
+	}
+}
+
+func TestRunJobOutcome(t *testing.T) {
+	tests := []struct {
+		name            string
+		update          func(ctx context.Context) (*model.Container, error)
+		cancelRequested bool
+		stopService     bool
+		wantStatus      string
+		wantError       string
+		wantStep        string
+	}{
+		{
+			name: "succeeded",
+			update: func(context.Context) (*model.Container, error) {
+				return &model.Container{ID: 5, ImageName: "nginx:1.27"}, nil
+			},
+			wantStatus: model.JobStatusSucceeded,
+			wantStep:   model.JobStepSucceeded,
+		},
+		{
+			name:       "failed",
+			update:     func(context.Context) (*model.Container, error) { return nil, errors.New("pull denied") },
+			wantStatus: model.JobStatusFailed,
+			wantError:  "pull denied",
+			wantStep:   model.JobStepFailed,
+		},
+		{
+			name:            "cancelled",
+			update:          waitForCancel,
+			cancelRequested: true,
+			wantStatus:      model.JobStatusCancelled,
+			wantError:       "job was cancelled",
+			wantStep:        model.JobStepFailed,
+		},
+		{
+			name:        "interrupted",
+			update:      waitForCancel,
+			stopService: true,
+			wantStatus:  model.JobStatusFailed,
+			wantError:   "job was interrupted because the service stopped",
+			wantStep:    model.JobStepFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeJobRepository{cancelRequested: tt.cancelRequested}
+			runner := &jobRunner{
+				repo:             repo,
+				containerService: &fakeContainerService{update: tt.update},
+				logger:           nopLogger{},
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.stopService {
+				time.AfterFunc(50*time.Millisecond, cancel)
+			}
+
+			request, _ := json.Marshal(dto.UpdateContainerJobRequest{ID: 5, UpdateData: map[string]interface{}{"image_name": "nginx:1.27"}})
+			runner.runJob(ctx, &model.Job{ID: "job-1", Type: model.JobTypeContainerUpdate, Status: model.JobStatusRunning, Request: request})
+
+			if len(repo.finished) != 1 {
+				t.Fatalf("finished %d jobs, want 1", len(repo.finished))
+			}
+			job := repo.finished[0]
+			if job.Status != tt.wantStatus || job.Error != tt.wantError {
+				t.Errorf("job finished as %q with error %q, want %q with %q", job.Status, job.Error, tt.wantStatus, tt.wantError)
+			}
+			if job.ContainerID != 5 {
+				t.Errorf("ContainerID = %d, want 5", job.ContainerID)
+			}
+			if len(job.Steps) != 1 || job.Steps[0].Status != tt.wantStep {
+				t.Errorf("steps = %+v, want one %s step", job.Steps, tt.wantStep)
+			}
+			if (job.Result != nil) != (tt.wantStatus == model.JobStatusSucceeded) {
+				t.Errorf("result = %s, want one only on success", job.Result)
+			}
+		})
+	}
+}
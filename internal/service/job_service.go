@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobWatchInterval is how often WatchJob checks a job for progress.
+const JobWatchInterval = 500 * time.Millisecond
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobFinished = repository.ErrJobFinished
+)
+
+// IJobService queues slow container operations as jobs and reports on them.
+// The jobs are run by IJobRunner.
+type IJobService interface {
+	SubmitCreateContainer(ctx context.Context, req dto.CreateContainerRequest) (*model.Job, error)
+	SubmitImportContainers(ctx context.Context, buf []byte) (*model.Job, error)
+	SubmitUpdateContainer(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Job, error)
+	GetJob(ctx context.Context, id string) (*model.Job, error)
+	WatchJob(ctx context.Context, id string) (<-chan model.Job, <-chan error, error)
+	CancelJob(ctx context.Context, id string) (*model.Job, error)
+}
+
+type jobService struct {
+	repo             repository.IJobRepository
+	containerService IContainerService
+	logger           logger.ILogger
+}
+
+func NewJobService(repo repository.IJobRepository, containerService IContainerService, logger logger.ILogger) IJobService {
+	return &jobService{
+		repo:             repo,
+		containerService: containerService,
+		logger:           logger,
+	}
+}
+
+// SubmitCreateContainer validates the request and queues the creation. The
+// checks that do not need Docker, such as quota and image policy, fail here
+// instead of in the job.
+func (s *jobService) SubmitCreateContainer(ctx context.Context, req dto.CreateContainerRequest) (*model.Job, error) {
+	if err := s.containerService.ValidateCreateContainer(ctx, req.ContainerName, req.ImageName, &req.ContainerSpec); err != nil {
+		return nil, err
+	}
+	return s.submit(ctx, model.JobTypeContainerCreate, 0, req)
+}
+
+func (s *jobService) SubmitImportContainers(ctx context.Context, buf []byte) (*model.Job, error) {
+	return s.submit(ctx, model.JobTypeContainerImport, 0, dto.ImportContainersJobRequest{File: buf})
+}
+
+func (s *jobService) SubmitUpdateContainer(ctx context.Context, id uint, updateData map[string]interface{}) (*model.Job, error) {
+	if err := s.containerService.ValidateUpdateContainer(ctx, id, updateData); err != nil {
+		return nil, err
+	}
+	return s.submit(ctx, model.JobTypeContainerUpdate, id, dto.UpdateContainerJobRequest{ID: id, UpdateData: updateData})
+}
+
+func (s *jobService) submit(ctx context.Context, jobType string, containerID uint, request interface{}) (*model.Job, error) {
+	raw, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job request: %w", err)
+	}
+
+	meta := utils.RequestMetaFromContext(ctx)
+	job := &model.Job{
+		ID:            uuid.NewString(),
+		Type:          jobType,
+		Status:        model.JobStatusQueued,
+		Steps:         []model.JobStep{},
+		Request:       raw,
+		ContainerID:   containerID,
+		Actor:         model.JobActor{Role: "system", SourceIP: meta.SourceIP, Transport: meta.Transport},
+		CorrelationID: utils.CorrelationIDFromContext(ctx),
+	}
+	if claims, ok := utils.ClaimsFromContext(ctx); ok {
+		job.Actor.UserID = claims.UserID
+		job.Actor.Username = claims.Username
+		job.Actor.Role = claims.Role
+		job.Actor.TenantID = claims.TenantID
+		job.Actor.Scopes = claims.Scopes
+		job.OwnerID = claims.UserID
+		job.TenantID = claims.TenantID
+	}
+
+	if err := s.repo.CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Job submitted", "id", job.ID, "type", job.Type, "containerID", containerID)
+	return job, nil
+}
+
+func (s *jobService) GetJob(ctx context.Context, id string) (*model.Job, error) {
+	job, err := s.repo.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		s.logger.Warn("Job not found", "id", id)
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return job, nil
+}
+
+// WatchJob sends the job whenever it changes, starting with its current
+// state. The job channel is closed once the job finished or ctx is done and
+// the reason is sent on the error channel, nil for a finished job.
+func (s *jobService) WatchJob(ctx context.Context, id string) (<-chan model.Job, <-chan error, error) {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make(chan model.Job)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(out)
+		defer close(errs)
+
+		ticker := time.NewTicker(JobWatchInterval)
+		defer ticker.Stop()
+
+		var lastUpdate time.Time
+		for {
+			if !job.UpdatedAt.Equal(lastUpdate) {
+				lastUpdate = job.UpdatedAt
+				select {
+				case out <- *job:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+			if job.IsFinished() {
+				errs <- nil
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			case <-ticker.C:
+			}
+
+			if job, err = s.GetJob(ctx, id); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+
+	return out, errs, nil
+}
+
+// CancelJob cancels a queued job or asks its worker to stop a running one.
+// A running job is interrupted in its current step and cleans up the way
+// the failed operation would.
+func (s *jobService) CancelJob(ctx context.Context, id string) (*model.Job, error) {
+	job, err := s.repo.CancelJob(ctx, id)
+	if err != nil {
+		return job, err
+	}
+	if job == nil {
+		s.logger.Warn("Job not found for cancellation", "id", id)
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return job, nil
+}
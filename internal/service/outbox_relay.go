@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"thanhnt208/container-adm-service/infrastructure"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const outboxBatchSize = 100
+
+// IOutboxRelay publishes container lifecycle events from the outbox table to
+// Kafka. Because events are committed together with the change they describe,
+// nothing is lost while Kafka is unavailable; it is only delivered later.
+type IOutboxRelay interface {
+	Run(ctx context.Context)
+}
+
+type outboxRelay struct {
+	repo     repository.IOutboxRepository
+	logger   logger.ILogger
+	writer   infrastructure.MessageWriter
+	topic    string
+	interval time.Duration
+}
+
+func NewOutboxRelay(repo repository.IOutboxRepository, logger logger.ILogger, writer infrastructure.MessageWriter, topic string, interval time.Duration) IOutboxRelay {
+	return &outboxRelay{
+		repo:     repo,
+		logger:   logger,
+		writer:   writer,
+		topic:    topic,
+		interval: interval,
+	}
+}
+
+func (r *outboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		// Drain full batches right away, otherwise wait for the next tick.
+		published, err := r.repo.PublishPending(ctx, outboxBatchSize, r.publish)
+		if err != nil {
+			r.logger.Error("Failed to relay outbox events", "error", err)
+		} else if published > 0 {
+			r.logger.Info("Relayed outbox events", "count", published, "topic", r.topic)
+		}
+		if err == nil && published == outboxBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Stopping outbox relay due to context cancellation")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *outboxRelay) publish(events []model.OutboxEvent) error {
+	messages := make([]kafka.Message, len(events))
+	for i, event := range events {
+		messages[i] = kafka.Message{
+			Topic: r.topic,
+			// Keyed by container so all events of a container land on one partition in order.
+			Key:   []byte(strconv.FormatUint(uint64(event.AggregateID), 10)),
+			Value: event.Payload,
+			Headers: []kafka.Header{
+				{Key: "event_id", Value: []byte(event.EventID)},
+				{Key: "event_type", Value: []byte(event.EventType)},
+				{Key: "schema_version", Value: []byte(strconv.Itoa(dto.ContainerEventSchemaVersion))},
+			},
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return r.writer.WriteMessages(ctx, messages...)
+}
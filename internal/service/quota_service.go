@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/utils"
+)
+
+var (
+	ErrQuotaExceeded = repository.ErrQuotaExceeded
+	ErrQuotaNotFound = repository.ErrQuotaNotFound
+)
+
+type IQuotaService interface {
+	GetMyQuota(ctx context.Context) (*dto.QuotaStatus, error)
+	ListQuotas(ctx context.Context) ([]model.Quota, error)
+	GetQuota(ctx context.Context, id uint) (*model.Quota, error)
+	CreateQuota(ctx context.Context, quota *model.Quota) error
+	UpdateQuota(ctx context.Context, id uint, quota *model.Quota) (*model.Quota, error)
+	DeleteQuota(ctx context.Context, id uint) error
+}
+
+type quotaService struct {
+	repo      repository.IQuotaRepository
+	auditRepo repository.IAuditRepository
+	logger    logger.ILogger
+}
+
+func NewQuotaService(repo repository.IQuotaRepository, auditRepo repository.IAuditRepository, logger logger.ILogger) IQuotaService {
+	return &quotaService{
+		repo:      repo,
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// GetMyQuota returns the quota of the caller and what the caller uses.
+func (s *quotaService) GetMyQuota(ctx context.Context) (*dto.QuotaStatus, error) {
+	claims, ok := utils.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("no claims in context")
+	}
+
+	quota, err := s.repo.GetEffectiveQuota(ctx, claims.UserID, claims.Role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota: %w", err)
+	}
+
+	usage, err := s.repo.GetUsage(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota usage: %w", err)
+	}
+
+	return &dto.QuotaStatus{Quota: quota, Usage: *usage}, nil
+}
+
+func (s *quotaService) ListQuotas(ctx context.Context) ([]model.Quota, error) {
+	return s.repo.ListQuotas(ctx)
+}
+
+func (s *quotaService) GetQuota(ctx context.Context, id uint) (*model.Quota, error) {
+	return s.repo.GetQuota(ctx, id)
+}
+
+// The mutating methods below record every attempt in the audit log.
+
+func (s *quotaService) CreateQuota(ctx context.Context, quota *model.Quota) error {
+	err := s.createQuota(ctx, quota)
+	recordAdminChange(ctx, s.auditRepo, s.logger, dto.AuditQuotaCreated, quota, err)
+	return err
+}
+
+func (s *quotaService) UpdateQuota(ctx context.Context, id uint, quota *model.Quota) (*model.Quota, error) {
+	before, updated, err := s.updateQuota(ctx, id, quota)
+	details := map[string]interface{}{"id": id}
+	if before != nil {
+		details["subject_type"] = before.SubjectType
+		details["subject"] = before.Subject
+		details["changes"] = quotaChanges(before, quota)
+	}
+	recordAdminChange(ctx, s.auditRepo, s.logger, dto.AuditQuotaUpdated, details, err)
+	return updated, err
+}
+
+func (s *quotaService) DeleteQuota(ctx context.Context, id uint) error {
+	quota, err := s.deleteQuota(ctx, id)
+	var details interface{} = map[string]interface{}{"id": id}
+	if quota != nil {
+		details = quota
+	}
+	recordAdminChange(ctx, s.auditRepo, s.logger, dto.AuditQuotaDeleted, details, err)
+	return err
+}
+
+func (s *quotaService) createQuota(ctx context.Context, quota *model.Quota) error {
+	if err := s.repo.CreateQuota(ctx, quota); err != nil {
+		return err
+	}
+	s.logger.Info("Quota created", "id", quota.ID, "subjectType", quota.SubjectType, "subject", quota.Subject)
+	return nil
+}
+
+// updateQuota also returns the quota as it was before, for the audit log.
+func (s *quotaService) updateQuota(ctx context.Context, id uint, quota *model.Quota) (*model.Quota, *model.Quota, error) {
+	before, err := s.repo.GetQuota(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	updated, err := s.repo.UpdateQuota(ctx, id, quota)
+	if err != nil {
+		return before, nil, err
+	}
+	s.logger.Info("Quota updated", "id", id)
+	return before, updated, nil
+}
+
+// deleteQuota returns the deleted quota, for the audit log.
+func (s *quotaService) deleteQuota(ctx context.Context, id uint) (*model.Quota, error) {
+	quota, err := s.repo.GetQuota(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.DeleteQuota(ctx, id); err != nil {
+		return quota, err
+	}
+	s.logger.Info("Quota deleted", "id", id)
+	return quota, nil
+}
+
+// quotaChanges returns the limits that differ between a quota and its
+// requested replacement.
+func quotaChanges(before, after *model.Quota) map[string]dto.FieldChange {
+	limits := []struct {
+		name     string
+		old, new int64
+	}{
+		{"max_containers", before.MaxContainers, after.MaxContainers},
+		{"max_running", before.MaxRunning, after.MaxRunning},
+		{"max_cpu_shares", before.MaxCPUShares, after.MaxCPUShares},
+		{"max_memory_bytes", before.MaxMemoryBytes, after.MaxMemoryBytes},
+	}
+
+	changes := make(map[string]dto.FieldChange)
+	for _, l := range limits {
+		if l.old != l.new {
+			changes[l.name] = dto.FieldChange{Old: l.old, New: l.new}
+		}
+	}
+	return changes
+}
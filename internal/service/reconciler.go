@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"thanhnt208/container-adm-service/external/client"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
+)
+
+// IReconciler keeps the container rows in Postgres in line with what the
+// Docker daemon actually reports, so drift caused by manual `docker` commands
+// or crashes is corrected without waiting for a status message.
+type IReconciler interface {
+	Run(ctx context.Context)
+	ReconcileOnce(ctx context.Context) (*dto.ReconcileResult, error)
+}
+
+type reconciler struct {
+	repo         repository.IContainerRepository
+	logger       logger.ILogger
+	dockerClient client.IDockerClient
+	interval     time.Duration
+}
+
+func NewReconciler(repo repository.IContainerRepository, logger logger.ILogger, dockerClient client.IDockerClient, interval time.Duration) IReconciler {
+	return &reconciler{
+		repo:         repo,
+		logger:       logger,
+		dockerClient: dockerClient,
+		interval:     interval,
+	}
+}
+
+// Run reconciles once immediately and then on every tick until ctx is cancelled.
+// A non-positive interval disables the loop.
+func (r *reconciler) Run(ctx context.Context) {
+	if r.interval <= 0 {
+		r.logger.Info("Reconciler disabled")
+		return
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if result, err := r.ReconcileOnce(ctx); err != nil {
+			r.logger.Error("Reconciliation failed", "error", err)
+		} else {
+			r.logger.Info("Reconciliation finished", "checked", result.Checked, "updated", result.Updated, "missing", result.Missing, "failed", result.Failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Stopping reconciler due to context cancellation")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *reconciler) ReconcileOnce(ctx context.Context) (*dto.ReconcileResult, error) {
+	dockerContainers, err := r.dockerClient.ListContainers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Docker containers: %w", err)
+	}
+
+	byID := make(map[string]client.ContainerState, len(dockerContainers))
+	byName := make(map[string]client.ContainerState, len(dockerContainers))
+	for _, dc := range dockerContainers {
+		byID[dc.ID] = dc
+		byName[dc.Name] = dc
+	}
+
+	containers, err := r.repo.ListAllContainers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	result := &dto.ReconcileResult{}
+	for _, ctn := range containers {
+		result.Checked++
+
+		updated, missing, err := r.reconcileContainer(ctx, ctn, byID, byName)
+		if err != nil {
+			r.logger.Error("Failed to reconcile container", "id", ctn.ID, "containerID", ctn.ContainerID, "error", err)
+			result.Failed++
+			continue
+		}
+		if updated {
+			result.Updated++
+		}
+		if missing {
+			result.Missing++
+		}
+	}
+
+	return result, nil
+}
+
+func (r *reconciler) reconcileContainer(ctx context.Context, ctn model.Container, byID, byName map[string]client.ContainerState) (bool, bool, error) {
+	actual, found := byID[ctn.ContainerID]
+	if !found {
+		actual, found = byName[ctn.ContainerName]
+	}
+	if !found {
+		// The list may race with a container being created; confirm before flagging it.
+		state, err := r.dockerClient.InspectContainer(ctx, ctn.ContainerID)
+		switch {
+		case err == nil:
+			actual, found = *state, true
+		case !errors.Is(err, client.ErrContainerNotFound):
+			return false, false, err
+		}
+	}
+
+	updateData := make(map[string]interface{})
+	status := repository.StatusMissing
+	reason := "container no longer exists in Docker"
+	if found {
+		status = statusFromDockerState(actual.State)
+		if status == repository.StatusExited && ctn.Status == repository.StatusStopped {
+			// A stopped container is an exited one that was stopped on purpose.
+			status = repository.StatusStopped
+		}
+		reason = "docker reports state " + actual.State
+		if actual.ID != ctn.ContainerID {
+			updateData["container_id"] = actual.ID
+		}
+	}
+	if status != ctn.Status {
+		updateData["status"] = status
+		updateData["last_status_at"] = time.Now().UTC()
+	}
+
+	if len(updateData) == 0 {
+		return false, !found, nil
+	}
+
+	if _, err := r.repo.UpdateObservedContainer(ctx, ctn.ID, updateData); err != nil {
+		return false, false, err
+	}
+
+	if status != ctn.Status {
+		if err := r.repo.RecordStatusDrift(ctx, ctn.ID, ctn.Status, status, reason); err != nil {
+			r.logger.Warn("Failed to record status drift", "id", ctn.ID, "error", err)
+		}
+	}
+
+	r.logger.Info("Container reconciled", "id", ctn.ID, "previousStatus", ctn.Status, "status", status, "containerID", actual.ID)
+	return true, !found, nil
+}
+
+func statusFromDockerState(state string) string {
+	switch state {
+	case "running":
+		return repository.StatusRunning
+	case "paused":
+		return repository.StatusPaused
+	case "restarting":
+		return repository.StatusRestarting
+	case "exited":
+		return repository.StatusExited
+	case "dead":
+		return repository.StatusDead
+	}
+	// Created containers have never run and removing ones are on their way out.
+	return repository.StatusStopped
+}
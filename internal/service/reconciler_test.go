@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"thanhnt208/container-adm-service/external/client"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+)
+
+func TestReconcileOnce(t *testing.T) {
+	repo := newFakeContainerRepository(
+		model.Container{ID: 1, ContainerID: "aaa", ContainerName: "in-sync", Status: repository.StatusRunning},
+		model.Container{ID: 2, ContainerID: "bbb", ContainerName: "crashed", Status: repository.StatusRunning},
+		model.Container{ID: 3, ContainerID: "ccc", ContainerName: "removed", Status: repository.StatusRunning},
+		model.Container{ID: 4, ContainerID: "ddd", ContainerName: "stopped", Status: repository.StatusStopped},
+		model.Container{ID: 5, ContainerID: "eee", ContainerName: "recreated", Status: repository.StatusRunning},
+	)
+	docker := &fakeDockerClient{containers: map[string]client.ContainerState{
+		"aaa": {ID: "aaa", Name: "in-sync", State: "running"},
+		"bbb": {ID: "bbb", Name: "crashed", State: "exited"},
+		"ddd": {ID: "ddd", Name: "stopped", State: "exited"},
+		// Recreated by hand under the same name.
+		"fff": {ID: "fff", Name: "recreated", State: "running"},
+		// Started outside the service and unknown to Postgres.
+		"zzz": {ID: "zzz", Name: "orphan", State: "running"},
+	}}
+
+	r := NewReconciler(repo, nopLogger{}, docker, 0)
+	result, err := r.ReconcileOnce(context.Background())
+	if err != nil {
+		t.Fatalf("ReconcileOnce() error = %v", err)
+	}
+
+	if result.Checked != 5 || result.Updated != 3 || result.Missing != 1 || result.Failed != 0 {
+		t.Errorf("result = %+v, want checked 5, updated 3, missing 1, failed 0", *result)
+	}
+
+	wantStatus := map[uint]string{
+		1: repository.StatusRunning,
+		2: repository.StatusExited,
+		3: repository.StatusMissing,
+		4: repository.StatusStopped,
+		5: repository.StatusRunning,
+	}
+	for id, want := range wantStatus {
+		if got := repo.containers[id].Status; got != want {
+			t.Errorf("container %d status = %q, want %q", id, got, want)
+		}
+	}
+
+	if got := repo.containers[5].ContainerID; got != "fff" {
+		t.Errorf("recreated container_id = %q, want %q", got, "fff")
+	}
+	for _, id := range []uint{1, 4} {
+		if _, ok := repo.updates[id]; ok {
+			t.Errorf("container %d was updated although it is in sync", id)
+		}
+	}
+
+	wantDrifts := map[uint]statusDrift{
+		2: {id: 2, previous: repository.StatusRunning, status: repository.StatusExited},
+		3: {id: 3, previous: repository.StatusRunning, status: repository.StatusMissing},
+	}
+	if len(repo.drifts) != len(wantDrifts) {
+		t.Fatalf("drifts = %+v, want %d", repo.drifts, len(wantDrifts))
+	}
+	for _, drift := range repo.drifts {
+		if drift != wantDrifts[drift.id] {
+			t.Errorf("drift = %+v, want %+v", drift, wantDrifts[drift.id])
+		}
+	}
+
+	if len(repo.containers) != 5 {
+		t.Errorf("orphaned Docker container was added to Postgres")
+	}
+}
+
+func TestReconcileAppliesObservedStartOverQuota(t *testing.T) {
+	repo := newFakeContainerRepository(
+		model.Container{ID: 1, ContainerID: "aaa", ContainerName: "restarted", Status: repository.StatusExited},
+	)
+	repo.overQuota = true
+	// Started by its restart policy while the owner is over quota.
+	docker := &fakeDockerClient{containers: map[string]client.ContainerState{
+		"aaa": {ID: "aaa", Name: "restarted", State: "running"},
+	}}
+
+	result, err := NewReconciler(repo, nopLogger{}, docker, 0).ReconcileOnce(context.Background())
+	if err != nil {
+		t.Fatalf("ReconcileOnce() error = %v", err)
+	}
+	if result.Updated != 1 || result.Failed != 0 {
+		t.Errorf("result = %+v, want updated 1, failed 0", *result)
+	}
+	if got := repo.containers[1].Status; got != repository.StatusRunning {
+		t.Errorf("status = %q, want %q", got, repository.StatusRunning)
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/model"
+	"thanhnt208/container-adm-service/internal/repository"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/utils"
+
+	"github.com/distribution/reference"
+	"github.com/docker/docker/api/types/registry"
+)
+
+var (
+	ErrRegistryCredentialNotFound  = repository.ErrRegistryCredentialNotFound
+	ErrRegistryCredentialsDisabled = errors.New("registry credentials are not configured")
+)
+
+// dockerHubRegistry is the host Docker Hub images normalize to. The other
+// names Docker Hub is known by are stored under it.
+const dockerHubRegistry = "docker.io"
+
+// IRegistryCredentialService manages the logins used for image pulls.
+// Passwords are encrypted before they are stored and are never returned.
+type IRegistryCredentialService interface {
+	ListRegistryCredentials(ctx context.Context) ([]model.RegistryCredential, error)
+	SetRegistryCredential(ctx context.Context, registry, username, password string) (*model.RegistryCredential, error)
+	DeleteRegistryCredential(ctx context.Context, registry string) error
+	RegistryAuth(ctx context.Context, imageName string) (string, error)
+}
+
+type registryCredentialService struct {
+	repo      repository.IRegistryCredentialRepository
+	auditRepo repository.IAuditRepository
+	cipher    *utils.SecretCipher
+	logger    logger.ILogger
+}
+
+func NewRegistryCredentialService(repo repository.IRegistryCredentialRepository, auditRepo repository.IAuditRepository, cipher *utils.SecretCipher, logger logger.ILogger) IRegistryCredentialService {
+	return &registryCredentialService{
+		repo:      repo,
+		auditRepo: auditRepo,
+		cipher:    cipher,
+		logger:    logger,
+	}
+}
+
+func (s *registryCredentialService) ListRegistryCredentials(ctx context.Context) ([]model.RegistryCredential, error) {
+	return s.repo.ListRegistryCredentials(ctx)
+}
+
+// SetRegistryCredential and DeleteRegistryCredential record every attempt in
+// the audit log, without the password.
+
+func (s *registryCredentialService) SetRegistryCredential(ctx context.Context, host, username, password string) (*model.RegistryCredential, error) {
+	host = normalizeRegistry(host)
+	credential, err := s.setRegistryCredential(ctx, host, username, password)
+	recordAdminChange(ctx, s.auditRepo, s.logger, dto.AuditRegistryCredentialSet, map[string]interface{}{
+		"registry": host,
+		"username": username,
+	}, err)
+	return credential, err
+}
+
+func (s *registryCredentialService) DeleteRegistryCredential(ctx context.Context, host string) error {
+	host = normalizeRegistry(host)
+	err := s.deleteRegistryCredential(ctx, host)
+	recordAdminChange(ctx, s.auditRepo, s.logger, dto.AuditRegistryCredentialDeleted, map[string]interface{}{"registry": host}, err)
+	return err
+}
+
+func (s *registryCredentialService) setRegistryCredential(ctx context.Context, host, username, password string) (*model.RegistryCredential, error) {
+	if !s.cipher.Enabled() {
+		return nil, ErrRegistryCredentialsDisabled
+	}
+
+	encrypted, err := s.cipher.Encrypt([]byte(password), []byte(host))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt registry password: %w", err)
+	}
+
+	credential := &model.RegistryCredential{
+		Registry:          host,
+		Username:          username,
+		EncryptedPassword: encrypted,
+	}
+	if err := s.repo.UpsertRegistryCredential(ctx, credential); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Registry credential stored", "registry", host, "username", username)
+	return credential, nil
+}
+
+func (s *registryCredentialService) deleteRegistryCredential(ctx context.Context, host string) error {
+	if err := s.repo.DeleteRegistryCredential(ctx, host); err != nil {
+		return err
+	}
+	s.logger.Info("Registry credential deleted", "registry", host)
+	return nil
+}
+
+// RegistryAuth returns the encoded login for the registry of imageName, or
+// an empty string to pull anonymously when none is stored.
+func (s *registryCredentialService) RegistryAuth(ctx context.Context, imageName string) (string, error) {
+	if !s.cipher.Enabled() {
+		return "", nil
+	}
+
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return "", fmt.Errorf("invalid image reference %q: %w", imageName, err)
+	}
+	host := normalizeRegistry(reference.Domain(named))
+
+	credential, err := s.repo.GetRegistryCredential(ctx, host)
+	if errors.Is(err, repository.ErrRegistryCredentialNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	password, err := s.cipher.Decrypt(credential.EncryptedPassword, []byte(host))
+	if err != nil {
+		s.logger.Error("Failed to decrypt registry credential", "registry", host, "error", err)
+		return "", fmt.Errorf("failed to decrypt credential for registry %s", host)
+	}
+
+	auth, err := registry.EncodeAuthConfig(registry.AuthConfig{
+		Username:      credential.Username,
+		Password:      string(password),
+		ServerAddress: host,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode credential for registry %s", host)
+	}
+	return auth, nil
+}
+
+func normalizeRegistry(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/")
+	switch host {
+	case "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return dockerHubRegistry
+	}
+	return host
+}
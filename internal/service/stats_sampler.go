@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"thanhnt208/container-adm-service/external/client"
+	"thanhnt208/container-adm-service/internal/repository"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
+)
+
+// statsSampleWorkers bounds the concurrent stats calls. Each one takes about
+// a second because the daemon needs two CPU readings.
+const statsSampleWorkers = 8
+
+// IStatsSampler periodically stores the resource usage of active containers
+// in Elasticsearch, so it can be charted over time.
+type IStatsSampler interface {
+	Run(ctx context.Context)
+	SampleOnce(ctx context.Context) (sampled, failed int, err error)
+}
+
+type statsSampler struct {
+	repo         repository.IContainerRepository
+	logger       logger.ILogger
+	dockerClient client.IDockerClient
+	interval     time.Duration
+}
+
+func NewStatsSampler(repo repository.IContainerRepository, logger logger.ILogger, dockerClient client.IDockerClient, interval time.Duration) IStatsSampler {
+	return &statsSampler{
+		repo:         repo,
+		logger:       logger,
+		dockerClient: dockerClient,
+		interval:     interval,
+	}
+}
+
+// Run samples once immediately and then on every tick until ctx is cancelled.
+// A non-positive interval disables the loop.
+func (s *statsSampler) Run(ctx context.Context) {
+	if s.interval <= 0 {
+		s.logger.Info("Stats sampler disabled")
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if sampled, failed, err := s.SampleOnce(ctx); err != nil {
+			s.logger.Error("Stats sampling failed", "error", err)
+		} else {
+			s.logger.Info("Stats sampling finished", "sampled", sampled, "failed", failed)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Stopping stats sampler due to context cancellation")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *statsSampler) SampleOnce(ctx context.Context) (int, int, error) {
+	containers, err := s.repo.ListAllContainers(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		sampled int
+		failed  int
+	)
+	sem := make(chan struct{}, statsSampleWorkers)
+
+	for _, ctn := range containers {
+		if !repository.IsActiveStatus(ctn.Status) || ctn.ContainerID == "" {
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return sampled, failed, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(id uint, containerID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := s.sample(ctx, id, containerID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+				return
+			}
+			sampled++
+		}(ctn.ID, ctn.ContainerID)
+	}
+
+	wg.Wait()
+	return sampled, failed, nil
+}
+
+func (s *statsSampler) sample(ctx context.Context, id uint, containerID string) error {
+	stats, err := s.dockerClient.Stats(ctx, containerID)
+	if err != nil {
+		s.logger.Warn("Failed to sample container stats", "id", id, "containerID", containerID, "error", err)
+		return err
+	}
+
+	if err := s.repo.AddContainerMetrics(ctx, id, stats); err != nil {
+		s.logger.Error("Failed to store container stats", "id", id, "error", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"thanhnt208/container-adm-service/internal/dto"
+	"thanhnt208/container-adm-service/internal/repository"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"thanhnt208/container-adm-service/utils"
+	"time"
+)
+
+// ITokenRevocationService cuts off issued tokens before they expire. It is
+// consulted by utils.ParseJWT for every REST and gRPC request.
+type ITokenRevocationService interface {
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	RevokeUserTokens(ctx context.Context, userID uint, issuedBefore time.Time) error
+	IsRevoked(ctx context.Context, claims *utils.Claims) (bool, error)
+}
+
+type tokenRevocationService struct {
+	repo      repository.ITokenRevocationRepository
+	auditRepo repository.IAuditRepository
+	logger    logger.ILogger
+	maxTTL    time.Duration
+}
+
+// NewTokenRevocationService takes the longest lifetime of any issued token,
+// which bounds how long a user-wide revocation has to be kept.
+func NewTokenRevocationService(repo repository.ITokenRevocationRepository, auditRepo repository.IAuditRepository, logger logger.ILogger, maxTTL time.Duration) ITokenRevocationService {
+	return &tokenRevocationService{
+		repo:      repo,
+		auditRepo: auditRepo,
+		logger:    logger,
+		maxTTL:    maxTTL,
+	}
+}
+
+// RevokeToken and RevokeUserTokens record every attempt in the audit log.
+
+func (s *tokenRevocationService) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	err := s.revokeToken(ctx, jti, expiresAt)
+	details := map[string]interface{}{"jti": jti}
+	if !expiresAt.IsZero() {
+		details["expires_at"] = expiresAt.UTC()
+	}
+	recordAdminChange(ctx, s.auditRepo, s.logger, dto.AuditTokenRevoked, details, err)
+	return err
+}
+
+func (s *tokenRevocationService) RevokeUserTokens(ctx context.Context, userID uint, issuedBefore time.Time) error {
+	if issuedBefore.IsZero() {
+		issuedBefore = time.Now()
+	}
+	err := s.revokeUserTokens(ctx, userID, issuedBefore)
+	recordAdminChange(ctx, s.auditRepo, s.logger, dto.AuditUserTokensRevoked, map[string]interface{}{
+		"user_id":       userID,
+		"issued_before": issuedBefore.UTC(),
+	}, err)
+	return err
+}
+
+func (s *tokenRevocationService) revokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	if jti == "" {
+		return fmt.Errorf("token id is required")
+	}
+
+	ttl := time.Until(expiresAt)
+	if expiresAt.IsZero() || ttl > s.maxTTL {
+		ttl = s.maxTTL
+	}
+	if ttl <= 0 {
+		s.logger.Info("Token already expired, nothing to revoke", "jti", jti)
+		return nil
+	}
+
+	if err := s.repo.RevokeToken(ctx, jti, ttl); err != nil {
+		return err
+	}
+
+	s.logger.Info("Token revoked", "jti", jti, "ttl", ttl)
+	return nil
+}
+
+func (s *tokenRevocationService) revokeUserTokens(ctx context.Context, userID uint, issuedBefore time.Time) error {
+	// Tokens issued before the cut-off have all expired maxTTL after it.
+	ttl := time.Until(issuedBefore.Add(s.maxTTL))
+	if ttl <= 0 {
+		s.logger.Info("All tokens before cut-off already expired", "userID", userID, "issuedBefore", issuedBefore)
+		return nil
+	}
+
+	if err := s.repo.RevokeUserTokens(ctx, userID, issuedBefore, ttl); err != nil {
+		return err
+	}
+
+	s.logger.Info("User tokens revoked", "userID", userID, "issuedBefore", issuedBefore)
+	return nil
+}
+
+// IsRevoked reports whether the token was revoked on its own or as one of
+// its user's tokens. Tokens without an issue time cannot be told apart from
+// older ones and count as revoked once their user has been cut off.
+func (s *tokenRevocationService) IsRevoked(ctx context.Context, claims *utils.Claims) (bool, error) {
+	if claims.ID != "" {
+		revoked, err := s.repo.IsTokenRevoked(ctx, claims.ID)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	cutoff, found, err := s.repo.UserRevokedBefore(ctx, claims.UserID)
+	if err != nil || !found {
+		return false, err
+	}
+	if claims.IssuedAt == nil {
+		return true, nil
+	}
+	return !claims.IssuedAt.Time.After(cutoff), nil
+}
@@ -0,0 +1,378 @@
+package authz
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Actions that routes and RPCs are authorized for.
+const (
+	ActionContainerCreate = "container:create"
+	ActionContainerRead   = "container:read"
+	ActionContainerUpdate = "container:update"
+	ActionContainerDelete = "container:delete"
+	ActionContainerImport = "container:import"
+	ActionContainerExport = "container:export"
+	ActionContainerLogs   = "container:logs"
+	ActionContainerExec   = "container:exec"
+
+	ActionContainerStart   = "container:start"
+	ActionContainerStop    = "container:stop"
+	ActionContainerRestart = "container:restart"
+	ActionContainerPause   = "container:pause"
+	ActionContainerUnpause = "container:unpause"
+	ActionContainerKill    = "container:kill"
+
+	ActionJobRead   = "job:read"
+	ActionJobCancel = "job:cancel"
+)
+
+// Actions lists every known action. The default policy grants each of them
+// to callers holding the scope of the same name.
+var Actions = []string{
+	ActionContainerCreate,
+	ActionContainerRead,
+	ActionContainerUpdate,
+	ActionContainerDelete,
+	ActionContainerImport,
+	ActionContainerExport,
+	ActionContainerLogs,
+	ActionContainerExec,
+	ActionContainerStart,
+	ActionContainerStop,
+	ActionContainerRestart,
+	ActionContainerPause,
+	ActionContainerUnpause,
+	ActionContainerKill,
+	ActionJobRead,
+	ActionJobCancel,
+}
+
+// LifecycleActions change the run state of a container. The default policy
+// also grants them to callers holding the container:update scope, which
+// used to be the only way to start and stop containers.
+var LifecycleActions = []string{
+	ActionContainerStart,
+	ActionContainerStop,
+	ActionContainerRestart,
+	ActionContainerPause,
+	ActionContainerUnpause,
+	ActionContainerKill,
+}
+
+const (
+	EffectAllow = "allow"
+	EffectDeny  = "deny"
+
+	// Self in an owner or tenant condition refers to the subject.
+	Self = "self"
+)
+
+// Subject is the caller a decision is made for.
+type Subject struct {
+	UserID   uint     `json:"user_id"`
+	Username string   `json:"username,omitempty"`
+	Role     string   `json:"role"`
+	TenantID string   `json:"tenant_id,omitempty"`
+	Scopes   []string `json:"scopes"`
+}
+
+// Resource is the container an action targets. It is nil for actions that
+// do not target a single container, such as create or list.
+type Resource struct {
+	ID       uint              `json:"id"`
+	OwnerID  uint              `json:"owner_id"`
+	TenantID string            `json:"tenant_id"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+// Policy is the YAML policy file:
+//
+//	rules:
+//	  - name: readers
+//	    actions: [container:read]
+//	    scopes: [container:read]
+//	  - name: dev-operators
+//	    actions: [container:update]
+//	    roles: [operator]
+//	    conditions:
+//	      labels: {env: dev}
+//	  - name: own-containers
+//	    actions: ["container:*"]
+//	    roles: [developer]
+//	    conditions:
+//	      owner: self
+//
+// A rule applies to subjects with any of its roles or scopes, or to every
+// subject when it lists neither. Actions may end in "*" to match a prefix.
+// Rules with conditions only apply to actions on a container that meets all
+// of them; on actions over many containers, such as listing, they limit the
+// result to the containers that do. Deny rules take precedence; without a
+// matching allow rule the action is denied.
+type Policy struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+type Rule struct {
+	Name       string     `yaml:"name"`
+	Effect     string     `yaml:"effect"`
+	Actions    []string   `yaml:"actions"`
+	Roles      []string   `yaml:"roles"`
+	Scopes     []string   `yaml:"scopes"`
+	Conditions Conditions `yaml:"conditions"`
+}
+
+type Conditions struct {
+	Owner  string            `yaml:"owner"`
+	Tenant string            `yaml:"tenant"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+func (c Conditions) empty() bool {
+	return c.Owner == "" && c.Tenant == "" && len(c.Labels) == 0
+}
+
+// Decision is the outcome of an evaluation and the rule that decided it.
+type Decision struct {
+	Allowed bool   `json:"allowed"`
+	Rule    string `json:"rule,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+type Engine struct {
+	policy Policy
+}
+
+// NewEngine validates policy and returns an engine evaluating it.
+func NewEngine(policy Policy) (*Engine, error) {
+	for i := range policy.Rules {
+		rule := &policy.Rules[i]
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", i+1)
+		}
+		if rule.Effect == "" {
+			rule.Effect = EffectAllow
+		}
+		if rule.Effect != EffectAllow && rule.Effect != EffectDeny {
+			return nil, fmt.Errorf("rule %q: invalid effect %q", rule.Name, rule.Effect)
+		}
+		if len(rule.Actions) == 0 {
+			return nil, fmt.Errorf("rule %q: no actions", rule.Name)
+		}
+		for _, value := range []string{rule.Conditions.Owner, rule.Conditions.Tenant} {
+			if value != "" && value != Self {
+				return nil, fmt.Errorf("rule %q: owner and tenant conditions only support %q", rule.Name, Self)
+			}
+		}
+	}
+	return &Engine{policy: policy}, nil
+}
+
+// LoadEngine reads the policy from file, or uses DefaultPolicy without one.
+func LoadEngine(file string) (*Engine, error) {
+	if file == "" {
+		return NewEngine(DefaultPolicy())
+	}
+
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorization policy: %w", err)
+	}
+
+	var policy Policy
+	if err := yaml.Unmarshal(raw, &policy); err != nil {
+		return nil, fmt.Errorf("failed to decode authorization policy: %w", err)
+	}
+	return NewEngine(policy)
+}
+
+// DefaultPolicy allows each action to callers holding the scope of the same
+// name, which is how scopes were checked before policies existed. Jobs can
+// also be followed by callers allowed to read containers and cancelled by
+// those allowed to start them.
+func DefaultPolicy() Policy {
+	policy := Policy{}
+	for _, action := range Actions {
+		scopes := []string{action}
+		switch {
+		case contains(LifecycleActions, action):
+			scopes = append(scopes, ActionContainerUpdate)
+		case action == ActionJobRead:
+			scopes = append(scopes, ActionContainerRead)
+		case action == ActionJobCancel:
+			scopes = append(scopes, ActionContainerCreate, ActionContainerUpdate, ActionContainerImport)
+		}
+		policy.Rules = append(policy.Rules, Rule{
+			Name:    "scope:" + action,
+			Effect:  EffectAllow,
+			Actions: []string{action},
+			Scopes:  scopes,
+		})
+	}
+	return policy
+}
+
+// Evaluate decides whether subject may perform action on resource.
+func (e *Engine) Evaluate(subject Subject, action string, resource *Resource) Decision {
+	var allowedBy string
+	for _, rule := range e.policy.Rules {
+		if !rule.matches(subject, action, resource) {
+			continue
+		}
+		if rule.Effect == EffectDeny {
+			return Decision{Allowed: false, Rule: rule.Name, Reason: "denied by rule " + rule.Name}
+		}
+		if allowedBy == "" {
+			allowedBy = rule.Name
+		}
+	}
+
+	if allowedBy != "" {
+		return Decision{Allowed: true, Rule: allowedBy, Reason: "allowed by rule " + allowedBy}
+	}
+	return Decision{Allowed: false, Reason: "no rule allows " + action}
+}
+
+// Scope is the set of containers a collection action may cover for its
+// subject: every container when All is set, else those meeting the
+// conditions of any rule in Allow. Containers meeting the conditions of a
+// rule in Deny are left out either way.
+type Scope struct {
+	Subject Subject
+	All     bool
+	Allow   []Conditions
+	Deny    []Conditions
+}
+
+// Unrestricted reports whether the scope leaves every container in.
+func (s *Scope) Unrestricted() bool {
+	return s.All && len(s.Deny) == 0
+}
+
+// Contains reports whether resource is in the scope, which is what Evaluate
+// decides for it.
+func (s *Scope) Contains(resource *Resource) bool {
+	for _, conditions := range s.Deny {
+		if conditions.met(s.Subject, resource) {
+			return false
+		}
+	}
+	if s.All {
+		return true
+	}
+	for _, conditions := range s.Allow {
+		if conditions.met(s.Subject, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateCollection decides whether subject may perform action on a set of
+// containers, such as a list, an export or an aggregate. Rules with
+// conditions cannot be decided upfront, so the action is allowed when any
+// allow rule may apply and the result must be limited to the returned scope.
+func (e *Engine) EvaluateCollection(subject Subject, action string) (Decision, *Scope) {
+	scope := &Scope{Subject: subject}
+	var allowedBy string
+	for _, rule := range e.policy.Rules {
+		if !rule.appliesTo(subject, action) {
+			continue
+		}
+		conditional := !rule.Conditions.empty()
+		switch {
+		case rule.Effect == EffectDeny && conditional:
+			scope.Deny = append(scope.Deny, rule.Conditions)
+		case rule.Effect == EffectDeny:
+			return Decision{Allowed: false, Rule: rule.Name, Reason: "denied by rule " + rule.Name}, nil
+		case conditional:
+			scope.Allow = append(scope.Allow, rule.Conditions)
+		default:
+			scope.All = true
+		}
+		if rule.Effect == EffectAllow && allowedBy == "" {
+			allowedBy = rule.Name
+		}
+	}
+
+	if allowedBy == "" {
+		return Decision{Allowed: false, Reason: "no rule allows " + action}, nil
+	}
+	return Decision{Allowed: true, Rule: allowedBy, Reason: "allowed by rule " + allowedBy}, scope
+}
+
+func (r Rule) matches(subject Subject, action string, resource *Resource) bool {
+	if !r.appliesTo(subject, action) {
+		return false
+	}
+	return r.Conditions.empty() || r.Conditions.met(subject, resource)
+}
+
+// appliesTo reports whether the rule covers action for subject, regardless
+// of its conditions.
+func (r Rule) appliesTo(subject Subject, action string) bool {
+	if !matchAction(r.Actions, action) {
+		return false
+	}
+
+	if len(r.Roles) > 0 || len(r.Scopes) > 0 {
+		if !contains(r.Roles, subject.Role) && !containsAny(r.Scopes, subject.Scopes) {
+			return false
+		}
+	}
+	return true
+}
+
+// met reports whether resource meets all conditions for subject. No
+// resource meets any condition.
+func (c Conditions) met(subject Subject, resource *Resource) bool {
+	if resource == nil {
+		return false
+	}
+	if c.Owner == Self && resource.OwnerID != subject.UserID {
+		return false
+	}
+	if c.Tenant == Self && (subject.TenantID == "" || resource.TenantID != subject.TenantID) {
+		return false
+	}
+	for key, value := range c.Labels {
+		if resource.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAction(patterns []string, action string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(action, prefix) {
+				return true
+			}
+		} else if pattern == action {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(values, candidates []string) bool {
+	for _, candidate := range candidates {
+		if contains(values, candidate) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package authz
+
+import "testing"
+
+func testEngine(t *testing.T) *Engine {
+	t.Helper()
+	engine, err := NewEngine(Policy{Rules: []Rule{
+		{Name: "readers", Actions: []string{ActionContainerRead}, Scopes: []string{ActionContainerRead}},
+		{Name: "dev-operators", Actions: []string{ActionContainerUpdate}, Roles: []string{"operator"},
+			Conditions: Conditions{Labels: map[string]string{"env": "dev"}}},
+		{Name: "own-containers", Actions: []string{"container:*"}, Roles: []string{"developer"},
+			Conditions: Conditions{Owner: Self}},
+		{Name: "tenant-logs", Actions: []string{ActionContainerLogs}, Roles: []string{"support"},
+			Conditions: Conditions{Tenant: Self}},
+		{Name: "no-prod-exec", Effect: EffectDeny, Actions: []string{ActionContainerExec},
+			Conditions: Conditions{Labels: map[string]string{"env": "prod"}}},
+		{Name: "no-interns", Effect: EffectDeny, Actions: []string{ActionContainerDelete}, Roles: []string{"intern"}},
+	}})
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+	return engine
+}
+
+func TestEngineEvaluate(t *testing.T) {
+	engine := testEngine(t)
+
+	developer := Subject{UserID: 7, Role: "developer", TenantID: "acme"}
+	operator := Subject{UserID: 8, Role: "operator"}
+	support := Subject{UserID: 9, Role: "support", TenantID: "acme"}
+	reader := Subject{UserID: 10, Role: "viewer", Scopes: []string{ActionContainerRead}}
+
+	own := &Resource{ID: 1, OwnerID: 7, TenantID: "acme", Labels: map[string]string{"env": "prod"}}
+	dev := &Resource{ID: 2, OwnerID: 8, TenantID: "other", Labels: map[string]string{"env": "dev"}}
+
+	tests := []struct {
+		name     string
+		subject  Subject
+		action   string
+		resource *Resource
+		allowed  bool
+		rule     string
+	}{
+		{"scope grants read", reader, ActionContainerRead, dev, true, "readers"},
+		{"scope does not grant update", reader, ActionContainerUpdate, dev, false, ""},
+		{"label condition met", operator, ActionContainerUpdate, dev, true, "dev-operators"},
+		{"label condition not met", operator, ActionContainerUpdate, own, false, ""},
+		{"owner condition met through wildcard", developer, ActionContainerStop, own, true, "own-containers"},
+		{"owner condition not met", developer, ActionContainerStop, dev, false, ""},
+		{"conditions never meet a missing container", developer, ActionContainerCreate, nil, false, ""},
+		{"tenant condition met", support, ActionContainerLogs, own, true, "tenant-logs"},
+		{"tenant condition not met", support, ActionContainerLogs, dev, false, ""},
+		{"tenant condition without a subject tenant", Subject{Role: "support"}, ActionContainerLogs, &Resource{}, false, ""},
+		{"conditional deny wins over allow", developer, ActionContainerExec, own, false, "no-prod-exec"},
+		{"conditional deny not met", developer, ActionContainerExec, &Resource{OwnerID: 7}, true, "own-containers"},
+		{"role deny", Subject{Role: "intern", Scopes: []string{ActionContainerRead}}, ActionContainerDelete, dev, false, "no-interns"},
+		{"no rule for action", reader, ActionJobCancel, nil, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision := engine.Evaluate(tt.subject, tt.action, tt.resource)
+			if decision.Allowed != tt.allowed || decision.Rule != tt.rule {
+				t.Errorf("Evaluate() = %+v, want allowed %v by %q", decision, tt.allowed, tt.rule)
+			}
+		})
+	}
+}
+
+func TestEngineEvaluateCollection(t *testing.T) {
+	engine := testEngine(t)
+
+	resources := []*Resource{
+		{ID: 1, OwnerID: 7, TenantID: "acme", Labels: map[string]string{"env": "prod"}},
+		{ID: 2, OwnerID: 8, TenantID: "other", Labels: map[string]string{"env": "dev"}},
+		{ID: 3, OwnerID: 7, TenantID: "acme"},
+	}
+
+	tests := []struct {
+		name    string
+		subject Subject
+		action  string
+		allowed bool
+		want    []uint
+	}{
+		{"unconditional allow covers everything", Subject{Scopes: []string{ActionContainerRead}}, ActionContainerRead, true, []uint{1, 2, 3}},
+		{"owner condition narrows", Subject{UserID: 7, Role: "developer"}, ActionContainerRead, true, []uint{1, 3}},
+		{"conditional deny excludes", Subject{UserID: 7, Role: "developer"}, ActionContainerExec, true, []uint{3}},
+		{"unconditional deny", Subject{Role: "intern", Scopes: []string{ActionContainerRead}}, ActionContainerDelete, false, nil},
+		{"no allow rule", Subject{Role: "viewer"}, ActionContainerRead, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, scope := engine.EvaluateCollection(tt.subject, tt.action)
+			if decision.Allowed != tt.allowed {
+				t.Fatalf("EvaluateCollection() = %+v, want allowed %v", decision, tt.allowed)
+			}
+			if !tt.allowed {
+				return
+			}
+
+			var got []uint
+			for _, resource := range resources {
+				if !scope.Contains(resource) {
+					continue
+				}
+				got = append(got, resource.ID)
+				// The scope must agree with deciding on the container itself.
+				if !engine.Evaluate(tt.subject, tt.action, resource).Allowed {
+					t.Errorf("scope contains container %d, which Evaluate denies", resource.ID)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("scope contains %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("scope contains %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewEngineRejectsInvalidRules(t *testing.T) {
+	tests := map[string]Rule{
+		"invalid effect":    {Effect: "maybe", Actions: []string{ActionContainerRead}},
+		"no actions":        {Name: "empty"},
+		"unsupported owner": {Actions: []string{ActionContainerRead}, Conditions: Conditions{Owner: "42"}},
+	}
+	for name, rule := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewEngine(Policy{Rules: []Rule{rule}}); err == nil {
+				t.Error("NewEngine() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	engine, err := NewEngine(DefaultPolicy())
+	if err != nil {
+		t.Fatalf("NewEngine() error = %v", err)
+	}
+
+	updater := Subject{Scopes: []string{ActionContainerUpdate}}
+	if !engine.Evaluate(updater, ActionContainerStop, nil).Allowed {
+		t.Error("container:update scope does not allow lifecycle actions")
+	}
+	if engine.Evaluate(updater, ActionContainerDelete, nil).Allowed {
+		t.Error("container:update scope allows delete")
+	}
+	if !engine.Evaluate(Subject{Scopes: []string{ActionContainerRead}}, ActionJobRead, nil).Allowed {
+		t.Error("container:read scope does not allow following jobs")
+	}
+}
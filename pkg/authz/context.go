@@ -0,0 +1,25 @@
+package authz
+
+import "context"
+
+// ScopeKey is where the REST middleware stores the scope of a collection
+// action in the gin context, which resolves string keys through Value.
+const ScopeKey = "authz_scope"
+
+type scopeContextKey struct{}
+
+func ContextWithScope(ctx context.Context, scope *Scope) context.Context {
+	return context.WithValue(ctx, scopeContextKey{}, scope)
+}
+
+// ScopeFromContext returns the scope a collection request was authorized
+// for, if any.
+func ScopeFromContext(ctx context.Context) (*Scope, bool) {
+	if scope, ok := ctx.Value(scopeContextKey{}).(*Scope); ok && scope != nil {
+		return scope, true
+	}
+	if scope, ok := ctx.Value(ScopeKey).(*Scope); ok && scope != nil {
+		return scope, true
+	}
+	return nil, false
+}
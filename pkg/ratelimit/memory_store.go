@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// maxMemoryBuckets bounds the fallback store. Full buckets are dropped first
+// since they are indistinguishable from new ones; when none is full the
+// least recently used bucket makes room.
+const maxMemoryBuckets = 10000
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// refilled reports whether the bucket has had time to fill up completely.
+func (b *memoryBucket) refilled(now time.Time) bool {
+	refill := time.Duration(float64(b.limit.Burst) / b.limit.Rate * float64(time.Second))
+	return now.Sub(b.updated) > refill
+}
+
+type memoryStore struct {
+	mu         sync.Mutex
+	buckets    map[string]*memoryBucket
+	maxBuckets int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		buckets:    make(map[string]*memoryBucket),
+		maxBuckets: maxMemoryBuckets,
+	}
+}
+
+func (s *memoryStore) allow(key string, limit Limit, now time.Time) Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		if len(s.buckets) >= s.maxBuckets {
+			s.prune(now)
+		}
+		bucket = &memoryBucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = bucket
+	}
+	bucket.limit = limit
+
+	elapsed := now.Sub(bucket.updated).Seconds()
+	if elapsed > 0 {
+		bucket.tokens = math.Min(float64(limit.Burst), bucket.tokens+elapsed*limit.Rate)
+		bucket.updated = now
+	}
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return Result{Allowed: true, Remaining: int(bucket.tokens)}
+	}
+
+	wait := time.Duration((1 - bucket.tokens) / limit.Rate * float64(time.Second))
+	return Result{Allowed: false, RetryAfter: wait}
+}
+
+// prune drops the buckets that have refilled under their own limit. When
+// none has, it evicts the least recently used bucket so the store stays
+// within maxBuckets.
+func (s *memoryStore) prune(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for key, bucket := range s.buckets {
+		if bucket.refilled(now) {
+			delete(s.buckets, key)
+			continue
+		}
+		if oldestKey == "" || bucket.updated.Before(oldest) {
+			oldestKey, oldest = key, bucket.updated
+		}
+	}
+
+	if len(s.buckets) >= s.maxBuckets && oldestKey != "" {
+		delete(s.buckets, oldestKey)
+	}
+}
@@ -0,0 +1,131 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"thanhnt208/container-adm-service/pkg/logger"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultScope holds the limit applied to scopes without a limit of their own.
+const DefaultScope = "default"
+
+// Limit is a token bucket that holds up to Burst tokens and refills at
+// Rate tokens per second. Every request takes one token.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the outcome of a single Allow call.
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+type IRateLimiter interface {
+	Allow(ctx context.Context, scope, key string) (Result, error)
+}
+
+// RateLimiter keeps its buckets in Redis so that all instances share them.
+// While Redis is unreachable it falls back to per-instance buckets in memory.
+type RateLimiter struct {
+	limits   map[string]Limit
+	redis    *redis.Client
+	memory   *memoryStore
+	logger   logger.ILogger
+	disabled bool
+}
+
+// NewRateLimiter builds a limiter for the given per-scope limits. With a nil
+// Redis client only the in-memory buckets are used.
+func NewRateLimiter(limits map[string]Limit, redis *redis.Client, logger logger.ILogger) *RateLimiter {
+	return &RateLimiter{
+		limits: limits,
+		redis:  redis,
+		memory: newMemoryStore(),
+		logger: logger,
+	}
+}
+
+// Configure builds the limiter for the RATE_LIMITS entries, sharing its
+// buckets through Redis, or a disabled one when limiting is turned off.
+func Configure(enabled bool, entries []string, redis *redis.Client, logger logger.ILogger) (*RateLimiter, error) {
+	if !enabled {
+		return Disabled(), nil
+	}
+	limits, err := ParseLimits(entries)
+	if err != nil {
+		return nil, err
+	}
+	return NewRateLimiter(limits, redis, logger), nil
+}
+
+// Disabled returns a limiter that allows every request.
+func Disabled() *RateLimiter {
+	return &RateLimiter{disabled: true}
+}
+
+// Allow takes a token from the bucket of key under the limit of scope.
+// Scopes without a limit, and no default limit, are not limited.
+func (l *RateLimiter) Allow(ctx context.Context, scope, key string) (Result, error) {
+	if l.disabled {
+		return Result{Allowed: true}, nil
+	}
+
+	limit, ok := l.limits[scope]
+	if !ok {
+		if limit, ok = l.limits[DefaultScope]; !ok {
+			return Result{Allowed: true}, nil
+		}
+	}
+
+	now := time.Now()
+	if l.redis != nil {
+		res, err := l.allowRedis(ctx, key, limit, now)
+		if err == nil {
+			return res, nil
+		}
+		l.logger.Warn("Rate limiter falling back to in-memory buckets", "key", key, "error", err)
+	}
+	return l.memory.allow(key, limit, now), nil
+}
+
+// ParseLimits parses entries of the form scope=requests/period, for example
+// "container:create=10/1m". The bucket holds as many tokens as requests, so
+// up to that many requests can be made at once before the rate applies.
+func ParseLimits(entries []string) (map[string]Limit, error) {
+	limits := make(map[string]Limit, len(entries))
+	for _, entry := range entries {
+		scope, spec, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit %q: expected scope=requests/period", entry)
+		}
+
+		count, period, ok := strings.Cut(spec, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit %q: expected scope=requests/period", entry)
+		}
+
+		requests, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil || requests <= 0 {
+			return nil, fmt.Errorf("invalid rate limit %q: requests must be a positive integer", entry)
+		}
+
+		duration, err := time.ParseDuration(strings.TrimSpace(period))
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid rate limit %q: period must be a positive duration", entry)
+		}
+
+		limits[strings.TrimSpace(scope)] = Limit{
+			Rate:  float64(requests) / duration.Seconds(),
+			Burst: requests,
+		}
+	}
+	return limits, nil
+}
@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const redisKeyPrefix = "ratelimit:"
+
+// tokenBucketScript refills and takes from a bucket atomically. The bucket
+// is a hash of the remaining tokens and the time of the last update, and
+// expires once it would have refilled completely.
+//
+// KEYS[1] bucket key; ARGV rate per second, burst, now in milliseconds.
+// Returns allowed (0/1), remaining tokens and the wait in milliseconds.
+const tokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(bucket[1]) or burst
+local ts = tonumber(bucket[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+
+return {allowed, math.floor(tokens), wait}
+`
+
+func (l *RateLimiter) allowRedis(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	values, err := l.redis.Eval(ctx, tokenBucketScript, []string{redisKeyPrefix + key},
+		limit.Rate, limit.Burst, now.UnixMilli()).Int64Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to run token bucket script: %w", err)
+	}
+	if len(values) != 3 {
+		return Result{}, fmt.Errorf("unexpected token bucket reply %v", values)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
@@ -157,6 +157,7 @@ type GetContainerInfomationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	StartTime     int64                  `protobuf:"varint,1,opt,name=startTime,proto3" json:"startTime,omitempty"`
 	EndTime       int64                  `protobuf:"varint,2,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	Mode          string                 `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -205,6 +206,13 @@ func (x *GetContainerInfomationRequest) GetEndTime() int64 {
 	return 0
 }
 
+func (x *GetContainerInfomationRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
 type GetContainerInfomationResponse struct {
 	state                protoimpl.MessageState `protogen:"open.v1"`
 	NumContainers        int64                  `protobuf:"varint,1,opt,name=numContainers,proto3" json:"numContainers,omitempty"`
@@ -393,42 +401,1180 @@ func (x *ContainerUptimeDetails) GetPerContainerUptime() map[string]int64 {
 	return nil
 }
 
-var File_proto_container_proto protoreflect.FileDescriptor
+type GetContainerStatusHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	StartTime     int64                  `protobuf:"varint,2,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	EndTime       int64                  `protobuf:"varint,3,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Size          int32                  `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	Cursor        string                 `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_container_proto_rawDesc = "" +
-	"\n" +
-	"\x15proto/container.proto\x12\x15container_adm_service\"\x0e\n" +
-	"\fEmptyRequest\"Y\n" +
-	"\x11ContainerResponse\x12D\n" +
-	"\n" +
-	"containers\x18\x01 \x03(\v2$.container_adm_service.ContainerNameR\n" +
-	"containers\"E\n" +
-	"\rContainerName\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x04R\x02id\x12$\n" +
-	"\rcontainerName\x18\x02 \x01(\tR\rcontainerName\"W\n" +
-	"\x1dGetContainerInfomationRequest\x12\x1c\n" +
-	"\tstartTime\x18\x01 \x01(\x03R\tstartTime\x12\x18\n" +
-	"\aendTime\x18\x02 \x01(\x03R\aendTime\"\xd8\x01\n" +
-	"\x1eGetContainerInfomationResponse\x12$\n" +
-	"\rnumContainers\x18\x01 \x01(\x03R\rnumContainers\x122\n" +
-	"\x14numRunningContainers\x18\x02 \x01(\x03R\x14numRunningContainers\x122\n" +
-	"\x14numStoppedContainers\x18\x03 \x01(\x03R\x14numStoppedContainers\x12(\n" +
-	"\x0fmeanUptimeRatio\x18\x04 \x01(\x02R\x0fmeanUptimeRatio\"\x87\x02\n" +
-	"\"GetContainerUptimeDurationResponse\x12$\n" +
-	"\rnumContainers\x18\x01 \x01(\x03R\rnumContainers\x122\n" +
-	"\x14numRunningContainers\x18\x02 \x01(\x03R\x14numRunningContainers\x122\n" +
-	"\x14numStoppedContainers\x18\x03 \x01(\x03R\x14numStoppedContainers\x12S\n" +
-	"\ruptimeDetails\x18\x04 \x01(\v2-.container_adm_service.ContainerUptimeDetailsR\ruptimeDetails\"\xf8\x01\n" +
-	"\x16ContainerUptimeDetails\x12 \n" +
-	"\vtotalUptime\x18\x01 \x01(\x03R\vtotalUptime\x12u\n" +
-	"\x12perContainerUptime\x18\x02 \x03(\v2E.container_adm_service.ContainerUptimeDetails.PerContainerUptimeEntryR\x12perContainerUptime\x1aE\n" +
-	"\x17PerContainerUptimeEntry\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x012\x91\x03\n" +
+func (x *GetContainerStatusHistoryRequest) Reset() {
+	*x = GetContainerStatusHistoryRequest{}
+	mi := &file_proto_container_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetContainerStatusHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetContainerStatusHistoryRequest) ProtoMessage() {}
+
+func (x *GetContainerStatusHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetContainerStatusHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetContainerStatusHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetContainerStatusHistoryRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetContainerStatusHistoryRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *GetContainerStatusHistoryRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *GetContainerStatusHistoryRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetContainerStatusHistoryRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GetContainerStatusHistoryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type StatusSpan struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	StartedAt     int64                  `protobuf:"varint,2,opt,name=startedAt,proto3" json:"startedAt,omitempty"`
+	EndedAt       int64                  `protobuf:"varint,3,opt,name=endedAt,proto3" json:"endedAt,omitempty"`
+	Duration      int64                  `protobuf:"varint,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	Ongoing       bool                   `protobuf:"varint,5,opt,name=ongoing,proto3" json:"ongoing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusSpan) Reset() {
+	*x = StatusSpan{}
+	mi := &file_proto_container_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusSpan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusSpan) ProtoMessage() {}
+
+func (x *StatusSpan) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusSpan.ProtoReflect.Descriptor instead.
+func (*StatusSpan) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StatusSpan) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *StatusSpan) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *StatusSpan) GetEndedAt() int64 {
+	if x != nil {
+		return x.EndedAt
+	}
+	return 0
+}
+
+func (x *StatusSpan) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *StatusSpan) GetOngoing() bool {
+	if x != nil {
+		return x.Ongoing
+	}
+	return false
+}
+
+type GetContainerStatusHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Spans         []*StatusSpan          `protobuf:"bytes,2,rep,name=spans,proto3" json:"spans,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,3,opt,name=nextCursor,proto3" json:"nextCursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetContainerStatusHistoryResponse) Reset() {
+	*x = GetContainerStatusHistoryResponse{}
+	mi := &file_proto_container_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetContainerStatusHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetContainerStatusHistoryResponse) ProtoMessage() {}
+
+func (x *GetContainerStatusHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetContainerStatusHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetContainerStatusHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetContainerStatusHistoryResponse) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetContainerStatusHistoryResponse) GetSpans() []*StatusSpan {
+	if x != nil {
+		return x.Spans
+	}
+	return nil
+}
+
+func (x *GetContainerStatusHistoryResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ContainerActionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Timeout       int32                  `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Signal        string                 `protobuf:"bytes,3,opt,name=signal,proto3" json:"signal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContainerActionRequest) Reset() {
+	*x = ContainerActionRequest{}
+	mi := &file_proto_container_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContainerActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerActionRequest) ProtoMessage() {}
+
+func (x *ContainerActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerActionRequest.ProtoReflect.Descriptor instead.
+func (*ContainerActionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ContainerActionRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ContainerActionRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *ContainerActionRequest) GetSignal() string {
+	if x != nil {
+		return x.Signal
+	}
+	return ""
+}
+
+type ContainerActionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	ContainerId   string                 `protobuf:"bytes,3,opt,name=containerId,proto3" json:"containerId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContainerActionResponse) Reset() {
+	*x = ContainerActionResponse{}
+	mi := &file_proto_container_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContainerActionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerActionResponse) ProtoMessage() {}
+
+func (x *ContainerActionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerActionResponse.ProtoReflect.Descriptor instead.
+func (*ContainerActionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ContainerActionResponse) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ContainerActionResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ContainerActionResponse) GetContainerId() string {
+	if x != nil {
+		return x.ContainerId
+	}
+	return ""
+}
+
+type StreamContainerLogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Follow        bool                   `protobuf:"varint,2,opt,name=follow,proto3" json:"follow,omitempty"`
+	Tail          int32                  `protobuf:"varint,3,opt,name=tail,proto3" json:"tail,omitempty"`
+	Since         int64                  `protobuf:"varint,4,opt,name=since,proto3" json:"since,omitempty"`
+	Until         int64                  `protobuf:"varint,5,opt,name=until,proto3" json:"until,omitempty"`
+	Timestamps    bool                   `protobuf:"varint,6,opt,name=timestamps,proto3" json:"timestamps,omitempty"`
+	Stdout        bool                   `protobuf:"varint,7,opt,name=stdout,proto3" json:"stdout,omitempty"`
+	Stderr        bool                   `protobuf:"varint,8,opt,name=stderr,proto3" json:"stderr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamContainerLogsRequest) Reset() {
+	*x = StreamContainerLogsRequest{}
+	mi := &file_proto_container_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamContainerLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamContainerLogsRequest) ProtoMessage() {}
+
+func (x *StreamContainerLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamContainerLogsRequest.ProtoReflect.Descriptor instead.
+func (*StreamContainerLogsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *StreamContainerLogsRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *StreamContainerLogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+func (x *StreamContainerLogsRequest) GetTail() int32 {
+	if x != nil {
+		return x.Tail
+	}
+	return 0
+}
+
+func (x *StreamContainerLogsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *StreamContainerLogsRequest) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+func (x *StreamContainerLogsRequest) GetTimestamps() bool {
+	if x != nil {
+		return x.Timestamps
+	}
+	return false
+}
+
+func (x *StreamContainerLogsRequest) GetStdout() bool {
+	if x != nil {
+		return x.Stdout
+	}
+	return false
+}
+
+func (x *StreamContainerLogsRequest) GetStderr() bool {
+	if x != nil {
+		return x.Stderr
+	}
+	return false
+}
+
+type ContainerLogLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stream        string                 `protobuf:"bytes,1,opt,name=stream,proto3" json:"stream,omitempty"`
+	TimestampNano int64                  `protobuf:"varint,2,opt,name=timestampNano,proto3" json:"timestampNano,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContainerLogLine) Reset() {
+	*x = ContainerLogLine{}
+	mi := &file_proto_container_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContainerLogLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerLogLine) ProtoMessage() {}
+
+func (x *ContainerLogLine) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerLogLine.ProtoReflect.Descriptor instead.
+func (*ContainerLogLine) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ContainerLogLine) GetStream() string {
+	if x != nil {
+		return x.Stream
+	}
+	return ""
+}
+
+func (x *ContainerLogLine) GetTimestampNano() int64 {
+	if x != nil {
+		return x.TimestampNano
+	}
+	return 0
+}
+
+func (x *ContainerLogLine) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type StreamContainerStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamContainerStatsRequest) Reset() {
+	*x = StreamContainerStatsRequest{}
+	mi := &file_proto_container_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamContainerStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamContainerStatsRequest) ProtoMessage() {}
+
+func (x *StreamContainerStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamContainerStatsRequest.ProtoReflect.Descriptor instead.
+func (*StreamContainerStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StreamContainerStatsRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ContainerStats struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TimeNano         int64                  `protobuf:"varint,1,opt,name=timeNano,proto3" json:"timeNano,omitempty"`
+	CpuPercent       float64                `protobuf:"fixed64,2,opt,name=cpuPercent,proto3" json:"cpuPercent,omitempty"`
+	OnlineCpus       uint32                 `protobuf:"varint,3,opt,name=onlineCpus,proto3" json:"onlineCpus,omitempty"`
+	MemoryUsageBytes uint64                 `protobuf:"varint,4,opt,name=memoryUsageBytes,proto3" json:"memoryUsageBytes,omitempty"`
+	MemoryLimitBytes uint64                 `protobuf:"varint,5,opt,name=memoryLimitBytes,proto3" json:"memoryLimitBytes,omitempty"`
+	MemoryPercent    float64                `protobuf:"fixed64,6,opt,name=memoryPercent,proto3" json:"memoryPercent,omitempty"`
+	NetworkRxBytes   uint64                 `protobuf:"varint,7,opt,name=networkRxBytes,proto3" json:"networkRxBytes,omitempty"`
+	NetworkTxBytes   uint64                 `protobuf:"varint,8,opt,name=networkTxBytes,proto3" json:"networkTxBytes,omitempty"`
+	BlockReadBytes   uint64                 `protobuf:"varint,9,opt,name=blockReadBytes,proto3" json:"blockReadBytes,omitempty"`
+	BlockWriteBytes  uint64                 `protobuf:"varint,10,opt,name=blockWriteBytes,proto3" json:"blockWriteBytes,omitempty"`
+	Pids             uint64                 `protobuf:"varint,11,opt,name=pids,proto3" json:"pids,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ContainerStats) Reset() {
+	*x = ContainerStats{}
+	mi := &file_proto_container_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContainerStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContainerStats) ProtoMessage() {}
+
+func (x *ContainerStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContainerStats.ProtoReflect.Descriptor instead.
+func (*ContainerStats) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ContainerStats) GetTimeNano() int64 {
+	if x != nil {
+		return x.TimeNano
+	}
+	return 0
+}
+
+func (x *ContainerStats) GetCpuPercent() float64 {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return 0
+}
+
+func (x *ContainerStats) GetOnlineCpus() uint32 {
+	if x != nil {
+		return x.OnlineCpus
+	}
+	return 0
+}
+
+func (x *ContainerStats) GetMemoryUsageBytes() uint64 {
+	if x != nil {
+		return x.MemoryUsageBytes
+	}
+	return 0
+}
+
+func (x *ContainerStats) GetMemoryLimitBytes() uint64 {
+	if x != nil {
+		return x.MemoryLimitBytes
+	}
+	return 0
+}
+
+func (x *ContainerStats) GetMemoryPercent() float64 {
+	if x != nil {
+		return x.MemoryPercent
+	}
+	return 0
+}
+
+func (x *ContainerStats) GetNetworkRxBytes() uint64 {
+	if x != nil {
+		return x.NetworkRxBytes
+	}
+	return 0
+}
+
+func (x *ContainerStats) GetNetworkTxBytes() uint64 {
+	if x != nil {
+		return x.NetworkTxBytes
+	}
+	return 0
+}
+
+func (x *ContainerStats) GetBlockReadBytes() uint64 {
+	if x != nil {
+		return x.BlockReadBytes
+	}
+	return 0
+}
+
+func (x *ContainerStats) GetBlockWriteBytes() uint64 {
+	if x != nil {
+		return x.BlockWriteBytes
+	}
+	return 0
+}
+
+func (x *ContainerStats) GetPids() uint64 {
+	if x != nil {
+		return x.Pids
+	}
+	return 0
+}
+
+type WatchJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchJobRequest) Reset() {
+	*x = WatchJobRequest{}
+	mi := &file_proto_container_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchJobRequest) ProtoMessage() {}
+
+func (x *WatchJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchJobRequest.ProtoReflect.Descriptor instead.
+func (*WatchJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *WatchJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobRequest) Reset() {
+	*x = CancelJobRequest{}
+	mi := &file_proto_container_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobRequest) ProtoMessage() {}
+
+func (x *CancelJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CancelJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Job struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type            string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Status          string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Steps           []*JobStep             `protobuf:"bytes,4,rep,name=steps,proto3" json:"steps,omitempty"`
+	Result          string                 `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"`
+	Error           string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	ContainerId     uint64                 `protobuf:"varint,7,opt,name=containerId,proto3" json:"containerId,omitempty"`
+	CancelRequested bool                   `protobuf:"varint,8,opt,name=cancelRequested,proto3" json:"cancelRequested,omitempty"`
+	CreatedAtNano   int64                  `protobuf:"varint,9,opt,name=createdAtNano,proto3" json:"createdAtNano,omitempty"`
+	UpdatedAtNano   int64                  `protobuf:"varint,10,opt,name=updatedAtNano,proto3" json:"updatedAtNano,omitempty"`
+	StartedAtNano   int64                  `protobuf:"varint,11,opt,name=startedAtNano,proto3" json:"startedAtNano,omitempty"`
+	FinishedAtNano  int64                  `protobuf:"varint,12,opt,name=finishedAtNano,proto3" json:"finishedAtNano,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Job) Reset() {
+	*x = Job{}
+	mi := &file_proto_container_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Job) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Job) ProtoMessage() {}
+
+func (x *Job) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Job.ProtoReflect.Descriptor instead.
+func (*Job) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Job) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Job) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Job) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Job) GetSteps() []*JobStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *Job) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *Job) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Job) GetContainerId() uint64 {
+	if x != nil {
+		return x.ContainerId
+	}
+	return 0
+}
+
+func (x *Job) GetCancelRequested() bool {
+	if x != nil {
+		return x.CancelRequested
+	}
+	return false
+}
+
+func (x *Job) GetCreatedAtNano() int64 {
+	if x != nil {
+		return x.CreatedAtNano
+	}
+	return 0
+}
+
+func (x *Job) GetUpdatedAtNano() int64 {
+	if x != nil {
+		return x.UpdatedAtNano
+	}
+	return 0
+}
+
+func (x *Job) GetStartedAtNano() int64 {
+	if x != nil {
+		return x.StartedAtNano
+	}
+	return 0
+}
+
+func (x *Job) GetFinishedAtNano() int64 {
+	if x != nil {
+		return x.FinishedAtNano
+	}
+	return 0
+}
+
+type JobStep struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Target         string                 `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	Status         string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Layers         map[string]*JobLayer   `protobuf:"bytes,4,rep,name=layers,proto3" json:"layers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	StartedAtNano  int64                  `protobuf:"varint,5,opt,name=startedAtNano,proto3" json:"startedAtNano,omitempty"`
+	FinishedAtNano int64                  `protobuf:"varint,6,opt,name=finishedAtNano,proto3" json:"finishedAtNano,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *JobStep) Reset() {
+	*x = JobStep{}
+	mi := &file_proto_container_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStep) ProtoMessage() {}
+
+func (x *JobStep) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStep.ProtoReflect.Descriptor instead.
+func (*JobStep) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *JobStep) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobStep) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *JobStep) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobStep) GetLayers() map[string]*JobLayer {
+	if x != nil {
+		return x.Layers
+	}
+	return nil
+}
+
+func (x *JobStep) GetStartedAtNano() int64 {
+	if x != nil {
+		return x.StartedAtNano
+	}
+	return 0
+}
+
+func (x *JobStep) GetFinishedAtNano() int64 {
+	if x != nil {
+		return x.FinishedAtNano
+	}
+	return 0
+}
+
+type JobLayer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Current       int64                  `protobuf:"varint,2,opt,name=current,proto3" json:"current,omitempty"`
+	Total         int64                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobLayer) Reset() {
+	*x = JobLayer{}
+	mi := &file_proto_container_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobLayer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobLayer) ProtoMessage() {}
+
+func (x *JobLayer) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_container_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobLayer.ProtoReflect.Descriptor instead.
+func (*JobLayer) Descriptor() ([]byte, []int) {
+	return file_proto_container_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *JobLayer) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobLayer) GetCurrent() int64 {
+	if x != nil {
+		return x.Current
+	}
+	return 0
+}
+
+func (x *JobLayer) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+var File_proto_container_proto protoreflect.FileDescriptor
+
+const file_proto_container_proto_rawDesc = "" +
+	"\n" +
+	"\x15proto/container.proto\x12\x15container_adm_service\"\x0e\n" +
+	"\fEmptyRequest\"Y\n" +
+	"\x11ContainerResponse\x12D\n" +
+	"\n" +
+	"containers\x18\x01 \x03(\v2$.container_adm_service.ContainerNameR\n" +
+	"containers\"E\n" +
+	"\rContainerName\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12$\n" +
+	"\rcontainerName\x18\x02 \x01(\tR\rcontainerName\"k\n" +
+	"\x1dGetContainerInfomationRequest\x12\x1c\n" +
+	"\tstartTime\x18\x01 \x01(\x03R\tstartTime\x12\x18\n" +
+	"\aendTime\x18\x02 \x01(\x03R\aendTime\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\"\xd8\x01\n" +
+	"\x1eGetContainerInfomationResponse\x12$\n" +
+	"\rnumContainers\x18\x01 \x01(\x03R\rnumContainers\x122\n" +
+	"\x14numRunningContainers\x18\x02 \x01(\x03R\x14numRunningContainers\x122\n" +
+	"\x14numStoppedContainers\x18\x03 \x01(\x03R\x14numStoppedContainers\x12(\n" +
+	"\x0fmeanUptimeRatio\x18\x04 \x01(\x02R\x0fmeanUptimeRatio\"\x87\x02\n" +
+	"\"GetContainerUptimeDurationResponse\x12$\n" +
+	"\rnumContainers\x18\x01 \x01(\x03R\rnumContainers\x122\n" +
+	"\x14numRunningContainers\x18\x02 \x01(\x03R\x14numRunningContainers\x122\n" +
+	"\x14numStoppedContainers\x18\x03 \x01(\x03R\x14numStoppedContainers\x12S\n" +
+	"\ruptimeDetails\x18\x04 \x01(\v2-.container_adm_service.ContainerUptimeDetailsR\ruptimeDetails\"\xf8\x01\n" +
+	"\x16ContainerUptimeDetails\x12 \n" +
+	"\vtotalUptime\x18\x01 \x01(\x03R\vtotalUptime\x12u\n" +
+	"\x12perContainerUptime\x18\x02 \x03(\v2E.container_adm_service.ContainerUptimeDetails.PerContainerUptimeEntryR\x12perContainerUptime\x1aE\n" +
+	"\x17PerContainerUptimeEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"\xae\x01\n" +
+	" GetContainerStatusHistoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1c\n" +
+	"\tstartTime\x18\x02 \x01(\x03R\tstartTime\x12\x18\n" +
+	"\aendTime\x18\x03 \x01(\x03R\aendTime\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x05R\x04size\x12\x16\n" +
+	"\x06cursor\x18\x06 \x01(\tR\x06cursor\"\x92\x01\n" +
+	"\n" +
+	"StatusSpan\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1c\n" +
+	"\tstartedAt\x18\x02 \x01(\x03R\tstartedAt\x12\x18\n" +
+	"\aendedAt\x18\x03 \x01(\x03R\aendedAt\x12\x1a\n" +
+	"\bduration\x18\x04 \x01(\x03R\bduration\x12\x18\n" +
+	"\aongoing\x18\x05 \x01(\bR\aongoing\"\x8c\x01\n" +
+	"!GetContainerStatusHistoryResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x127\n" +
+	"\x05spans\x18\x02 \x03(\v2!.container_adm_service.StatusSpanR\x05spans\x12\x1e\n" +
+	"\n" +
+	"nextCursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"Z\n" +
+	"\x16ContainerActionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x18\n" +
+	"\atimeout\x18\x02 \x01(\x05R\atimeout\x12\x16\n" +
+	"\x06signal\x18\x03 \x01(\tR\x06signal\"c\n" +
+	"\x17ContainerActionResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12 \n" +
+	"\vcontainerId\x18\x03 \x01(\tR\vcontainerId\"\xd4\x01\n" +
+	"\x1aStreamContainerLogsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x16\n" +
+	"\x06follow\x18\x02 \x01(\bR\x06follow\x12\x12\n" +
+	"\x04tail\x18\x03 \x01(\x05R\x04tail\x12\x14\n" +
+	"\x05since\x18\x04 \x01(\x03R\x05since\x12\x14\n" +
+	"\x05until\x18\x05 \x01(\x03R\x05until\x12\x1e\n" +
+	"\n" +
+	"timestamps\x18\x06 \x01(\bR\n" +
+	"timestamps\x12\x16\n" +
+	"\x06stdout\x18\a \x01(\bR\x06stdout\x12\x16\n" +
+	"\x06stderr\x18\b \x01(\bR\x06stderr\"d\n" +
+	"\x10ContainerLogLine\x12\x16\n" +
+	"\x06stream\x18\x01 \x01(\tR\x06stream\x12$\n" +
+	"\rtimestampNano\x18\x02 \x01(\x03R\rtimestampNano\x12\x12\n" +
+	"\x04text\x18\x03 \x01(\tR\x04text\"-\n" +
+	"\x1bStreamContainerStatsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xa0\x03\n" +
+	"\x0eContainerStats\x12\x1a\n" +
+	"\btimeNano\x18\x01 \x01(\x03R\btimeNano\x12\x1e\n" +
+	"\n" +
+	"cpuPercent\x18\x02 \x01(\x01R\n" +
+	"cpuPercent\x12\x1e\n" +
+	"\n" +
+	"onlineCpus\x18\x03 \x01(\rR\n" +
+	"onlineCpus\x12*\n" +
+	"\x10memoryUsageBytes\x18\x04 \x01(\x04R\x10memoryUsageBytes\x12*\n" +
+	"\x10memoryLimitBytes\x18\x05 \x01(\x04R\x10memoryLimitBytes\x12$\n" +
+	"\rmemoryPercent\x18\x06 \x01(\x01R\rmemoryPercent\x12&\n" +
+	"\x0enetworkRxBytes\x18\a \x01(\x04R\x0enetworkRxBytes\x12&\n" +
+	"\x0enetworkTxBytes\x18\b \x01(\x04R\x0enetworkTxBytes\x12&\n" +
+	"\x0eblockReadBytes\x18\t \x01(\x04R\x0eblockReadBytes\x12(\n" +
+	"\x0fblockWriteBytes\x18\n" +
+	" \x01(\x04R\x0fblockWriteBytes\x12\x12\n" +
+	"\x04pids\x18\v \x01(\x04R\x04pids\"!\n" +
+	"\x0fWatchJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\"\n" +
+	"\x10CancelJobRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x8b\x03\n" +
+	"\x03Job\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x124\n" +
+	"\x05steps\x18\x04 \x03(\v2\x1e.container_adm_service.JobStepR\x05steps\x12\x16\n" +
+	"\x06result\x18\x05 \x01(\tR\x06result\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12 \n" +
+	"\vcontainerId\x18\a \x01(\x04R\vcontainerId\x12(\n" +
+	"\x0fcancelRequested\x18\b \x01(\bR\x0fcancelRequested\x12$\n" +
+	"\rcreatedAtNano\x18\t \x01(\x03R\rcreatedAtNano\x12$\n" +
+	"\rupdatedAtNano\x18\n" +
+	" \x01(\x03R\rupdatedAtNano\x12$\n" +
+	"\rstartedAtNano\x18\v \x01(\x03R\rstartedAtNano\x12&\n" +
+	"\x0efinishedAtNano\x18\f \x01(\x03R\x0efinishedAtNano\"\xbb\x02\n" +
+	"\aJobStep\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06target\x18\x02 \x01(\tR\x06target\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12B\n" +
+	"\x06layers\x18\x04 \x03(\v2*.container_adm_service.JobStep.LayersEntryR\x06layers\x12$\n" +
+	"\rstartedAtNano\x18\x05 \x01(\x03R\rstartedAtNano\x12&\n" +
+	"\x0efinishedAtNano\x18\x06 \x01(\x03R\x0efinishedAtNano\x1aZ\n" +
+	"\vLayersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x125\n" +
+	"\x05value\x18\x02 \x01(\v2\x1f.container_adm_service.JobLayerR\x05value:\x028\x01\"R\n" +
+	"\bJobLayer\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
+	"\acurrent\x18\x02 \x01(\x03R\acurrent\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total2\xd8\f\n" +
 	"\x13ContainerAdmService\x12a\n" +
 	"\x10GetAllContainers\x12#.container_adm_service.EmptyRequest\x1a(.container_adm_service.ContainerResponse\x12\x86\x01\n" +
 	"\x17GetContainerInformation\x124.container_adm_service.GetContainerInfomationRequest\x1a5.container_adm_service.GetContainerInfomationResponse\x12\x8d\x01\n" +
-	"\x1aGetContainerUptimeDuration\x124.container_adm_service.GetContainerInfomationRequest\x1a9.container_adm_service.GetContainerUptimeDurationResponseB\fZ\n" +
+	"\x1aGetContainerUptimeDuration\x124.container_adm_service.GetContainerInfomationRequest\x1a9.container_adm_service.GetContainerUptimeDurationResponse\x12\x8e\x01\n" +
+	"\x19GetContainerStatusHistory\x127.container_adm_service.GetContainerStatusHistoryRequest\x1a8.container_adm_service.GetContainerStatusHistoryResponse\x12o\n" +
+	"\x0eStartContainer\x12-.container_adm_service.ContainerActionRequest\x1a..container_adm_service.ContainerActionResponse\x12n\n" +
+	"\rStopContainer\x12-.container_adm_service.ContainerActionRequest\x1a..container_adm_service.ContainerActionResponse\x12q\n" +
+	"\x10RestartContainer\x12-.container_adm_service.ContainerActionRequest\x1a..container_adm_service.ContainerActionResponse\x12o\n" +
+	"\x0ePauseContainer\x12-.container_adm_service.ContainerActionRequest\x1a..container_adm_service.ContainerActionResponse\x12q\n" +
+	"\x10UnpauseContainer\x12-.container_adm_service.ContainerActionRequest\x1a..container_adm_service.ContainerActionResponse\x12n\n" +
+	"\rKillContainer\x12-.container_adm_service.ContainerActionRequest\x1a..container_adm_service.ContainerActionResponse\x12s\n" +
+	"\x13StreamContainerLogs\x121.container_adm_service.StreamContainerLogsRequest\x1a'.container_adm_service.ContainerLogLine0\x01\x12s\n" +
+	"\x14StreamContainerStats\x122.container_adm_service.StreamContainerStatsRequest\x1a%.container_adm_service.ContainerStats0\x01\x12P\n" +
+	"\bWatchJob\x12&.container_adm_service.WatchJobRequest\x1a\x1a.container_adm_service.Job0\x01\x12P\n" +
+	"\tCancelJob\x12'.container_adm_service.CancelJobRequest\x1a\x1a.container_adm_service.JobB\fZ\n" +
 	"./proto/pbb\x06proto3"
 
 var (
@@ -443,7 +1589,7 @@ func file_proto_container_proto_rawDescGZIP() []byte {
 	return file_proto_container_proto_rawDescData
 }
 
-var file_proto_container_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_container_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_proto_container_proto_goTypes = []any{
 	(*EmptyRequest)(nil),                       // 0: container_adm_service.EmptyRequest
 	(*ContainerResponse)(nil),                  // 1: container_adm_service.ContainerResponse
@@ -452,23 +1598,64 @@ var file_proto_container_proto_goTypes = []any{
 	(*GetContainerInfomationResponse)(nil),     // 4: container_adm_service.GetContainerInfomationResponse
 	(*GetContainerUptimeDurationResponse)(nil), // 5: container_adm_service.GetContainerUptimeDurationResponse
 	(*ContainerUptimeDetails)(nil),             // 6: container_adm_service.ContainerUptimeDetails
-	nil,                                        // 7: container_adm_service.ContainerUptimeDetails.PerContainerUptimeEntry
+	(*GetContainerStatusHistoryRequest)(nil),   // 7: container_adm_service.GetContainerStatusHistoryRequest
+	(*StatusSpan)(nil),                         // 8: container_adm_service.StatusSpan
+	(*GetContainerStatusHistoryResponse)(nil),  // 9: container_adm_service.GetContainerStatusHistoryResponse
+	(*ContainerActionRequest)(nil),             // 10: container_adm_service.ContainerActionRequest
+	(*ContainerActionResponse)(nil),            // 11: container_adm_service.ContainerActionResponse
+	(*StreamContainerLogsRequest)(nil),         // 12: container_adm_service.StreamContainerLogsRequest
+	(*ContainerLogLine)(nil),                   // 13: container_adm_service.ContainerLogLine
+	(*StreamContainerStatsRequest)(nil),        // 14: container_adm_service.StreamContainerStatsRequest
+	(*ContainerStats)(nil),                     // 15: container_adm_service.ContainerStats
+	(*WatchJobRequest)(nil),                    // 16: container_adm_service.WatchJobRequest
+	(*CancelJobRequest)(nil),                   // 17: container_adm_service.CancelJobRequest
+	(*Job)(nil),                                // 18: container_adm_service.Job
+	(*JobStep)(nil),                            // 19: container_adm_service.JobStep
+	(*JobLayer)(nil),                           // 20: container_adm_service.JobLayer
+	nil,                                        // 21: container_adm_service.ContainerUptimeDetails.PerContainerUptimeEntry
+	nil,                                        // 22: container_adm_service.JobStep.LayersEntry
 }
 var file_proto_container_proto_depIdxs = []int32{
-	2, // 0: container_adm_service.ContainerResponse.containers:type_name -> container_adm_service.ContainerName
-	6, // 1: container_adm_service.GetContainerUptimeDurationResponse.uptimeDetails:type_name -> container_adm_service.ContainerUptimeDetails
-	7, // 2: container_adm_service.ContainerUptimeDetails.perContainerUptime:type_name -> container_adm_service.ContainerUptimeDetails.PerContainerUptimeEntry
-	0, // 3: container_adm_service.ContainerAdmService.GetAllContainers:input_type -> container_adm_service.EmptyRequest
-	3, // 4: container_adm_service.ContainerAdmService.GetContainerInformation:input_type -> container_adm_service.GetContainerInfomationRequest
-	3, // 5: container_adm_service.ContainerAdmService.GetContainerUptimeDuration:input_type -> container_adm_service.GetContainerInfomationRequest
-	1, // 6: container_adm_service.ContainerAdmService.GetAllContainers:output_type -> container_adm_service.ContainerResponse
-	4, // 7: container_adm_service.ContainerAdmService.GetContainerInformation:output_type -> container_adm_service.GetContainerInfomationResponse
-	5, // 8: container_adm_service.ContainerAdmService.GetContainerUptimeDuration:output_type -> container_adm_service.GetContainerUptimeDurationResponse
-	6, // [6:9] is the sub-list for method output_type
-	3, // [3:6] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	2,  // 0: container_adm_service.ContainerResponse.containers:type_name -> container_adm_service.ContainerName
+	6,  // 1: container_adm_service.GetContainerUptimeDurationResponse.uptimeDetails:type_name -> container_adm_service.ContainerUptimeDetails
+	21, // 2: container_adm_service.ContainerUptimeDetails.perContainerUptime:type_name -> container_adm_service.ContainerUptimeDetails.PerContainerUptimeEntry
+	8,  // 3: container_adm_service.GetContainerStatusHistoryResponse.spans:type_name -> container_adm_service.StatusSpan
+	19, // 4: container_adm_service.Job.steps:type_name -> container_adm_service.JobStep
+	22, // 5: container_adm_service.JobStep.layers:type_name -> container_adm_service.JobStep.LayersEntry
+	20, // 6: container_adm_service.JobStep.LayersEntry.value:type_name -> container_adm_service.JobLayer
+	0,  // 7: container_adm_service.ContainerAdmService.GetAllContainers:input_type -> container_adm_service.EmptyRequest
+	3,  // 8: container_adm_service.ContainerAdmService.GetContainerInformation:input_type -> container_adm_service.GetContainerInfomationRequest
+	3,  // 9: container_adm_service.ContainerAdmService.GetContainerUptimeDuration:input_type -> container_adm_service.GetContainerInfomationRequest
+	7,  // 10: container_adm_service.ContainerAdmService.GetContainerStatusHistory:input_type -> container_adm_service.GetContainerStatusHistoryRequest
+	10, // 11: container_adm_service.ContainerAdmService.StartContainer:input_type -> container_adm_service.ContainerActionRequest
+	10, // 12: container_adm_service.ContainerAdmService.StopContainer:input_type -> container_adm_service.ContainerActionRequest
+	10, // 13: container_adm_service.ContainerAdmService.RestartContainer:input_type -> container_adm_service.ContainerActionRequest
+	10, // 14: container_adm_service.ContainerAdmService.PauseContainer:input_type -> container_adm_service.ContainerActionRequest
+	10, // 15: container_adm_service.ContainerAdmService.UnpauseContainer:input_type -> container_adm_service.ContainerActionRequest
+	10, // 16: container_adm_service.ContainerAdmService.KillContainer:input_type -> container_adm_service.ContainerActionRequest
+	12, // 17: container_adm_service.ContainerAdmService.StreamContainerLogs:input_type -> container_adm_service.StreamContainerLogsRequest
+	14, // 18: container_adm_service.ContainerAdmService.StreamContainerStats:input_type -> container_adm_service.StreamContainerStatsRequest
+	16, // 19: container_adm_service.ContainerAdmService.WatchJob:input_type -> container_adm_service.WatchJobRequest
+	17, // 20: container_adm_service.ContainerAdmService.CancelJob:input_type -> container_adm_service.CancelJobRequest
+	1,  // 21: container_adm_service.ContainerAdmService.GetAllContainers:output_type -> container_adm_service.ContainerResponse
+	4,  // 22: container_adm_service.ContainerAdmService.GetContainerInformation:output_type -> container_adm_service.GetContainerInfomationResponse
+	5,  // 23: container_adm_service.ContainerAdmService.GetContainerUptimeDuration:output_type -> container_adm_service.GetContainerUptimeDurationResponse
+	9,  // 24: container_adm_service.ContainerAdmService.GetContainerStatusHistory:output_type -> container_adm_service.GetContainerStatusHistoryResponse
+	11, // 25: container_adm_service.ContainerAdmService.StartContainer:output_type -> container_adm_service.ContainerActionResponse
+	11, // 26: container_adm_service.ContainerAdmService.StopContainer:output_type -> container_adm_service.ContainerActionResponse
+	11, // 27: container_adm_service.ContainerAdmService.RestartContainer:output_type -> container_adm_service.ContainerActionResponse
+	11, // 28: container_adm_service.ContainerAdmService.PauseContainer:output_type -> container_adm_service.ContainerActionResponse
+	11, // 29: container_adm_service.ContainerAdmService.UnpauseContainer:output_type -> container_adm_service.ContainerActionResponse
+	11, // 30: container_adm_service.ContainerAdmService.KillContainer:output_type -> container_adm_service.ContainerActionResponse
+	13, // 31: container_adm_service.ContainerAdmService.StreamContainerLogs:output_type -> container_adm_service.ContainerLogLine
+	15, // 32: container_adm_service.ContainerAdmService.StreamContainerStats:output_type -> container_adm_service.ContainerStats
+	18, // 33: container_adm_service.ContainerAdmService.WatchJob:output_type -> container_adm_service.Job
+	18, // 34: container_adm_service.ContainerAdmService.CancelJob:output_type -> container_adm_service.Job
+	21, // [21:35] is the sub-list for method output_type
+	7,  // [7:21] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_proto_container_proto_init() }
@@ -482,7 +1669,7 @@ func file_proto_container_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_container_proto_rawDesc), len(file_proto_container_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
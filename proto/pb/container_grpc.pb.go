@@ -22,6 +22,17 @@ const (
 	ContainerAdmService_GetAllContainers_FullMethodName           = "/container_adm_service.ContainerAdmService/GetAllContainers"
 	ContainerAdmService_GetContainerInformation_FullMethodName    = "/container_adm_service.ContainerAdmService/GetContainerInformation"
 	ContainerAdmService_GetContainerUptimeDuration_FullMethodName = "/container_adm_service.ContainerAdmService/GetContainerUptimeDuration"
+	ContainerAdmService_GetContainerStatusHistory_FullMethodName  = "/container_adm_service.ContainerAdmService/GetContainerStatusHistory"
+	ContainerAdmService_StartContainer_FullMethodName             = "/container_adm_service.ContainerAdmService/StartContainer"
+	ContainerAdmService_StopContainer_FullMethodName              = "/container_adm_service.ContainerAdmService/StopContainer"
+	ContainerAdmService_RestartContainer_FullMethodName           = "/container_adm_service.ContainerAdmService/RestartContainer"
+	ContainerAdmService_PauseContainer_FullMethodName             = "/container_adm_service.ContainerAdmService/PauseContainer"
+	ContainerAdmService_UnpauseContainer_FullMethodName           = "/container_adm_service.ContainerAdmService/UnpauseContainer"
+	ContainerAdmService_KillContainer_FullMethodName              = "/container_adm_service.ContainerAdmService/KillContainer"
+	ContainerAdmService_StreamContainerLogs_FullMethodName        = "/container_adm_service.ContainerAdmService/StreamContainerLogs"
+	ContainerAdmService_StreamContainerStats_FullMethodName       = "/container_adm_service.ContainerAdmService/StreamContainerStats"
+	ContainerAdmService_WatchJob_FullMethodName                   = "/container_adm_service.ContainerAdmService/WatchJob"
+	ContainerAdmService_CancelJob_FullMethodName                  = "/container_adm_service.ContainerAdmService/CancelJob"
 )
 
 // ContainerAdmServiceClient is the client API for ContainerAdmService service.
@@ -31,6 +42,17 @@ type ContainerAdmServiceClient interface {
 	GetAllContainers(ctx context.Context, in *EmptyRequest, opts ...grpc.CallOption) (*ContainerResponse, error)
 	GetContainerInformation(ctx context.Context, in *GetContainerInfomationRequest, opts ...grpc.CallOption) (*GetContainerInfomationResponse, error)
 	GetContainerUptimeDuration(ctx context.Context, in *GetContainerInfomationRequest, opts ...grpc.CallOption) (*GetContainerUptimeDurationResponse, error)
+	GetContainerStatusHistory(ctx context.Context, in *GetContainerStatusHistoryRequest, opts ...grpc.CallOption) (*GetContainerStatusHistoryResponse, error)
+	StartContainer(ctx context.Context, in *ContainerActionRequest, opts ...grpc.CallOption) (*ContainerActionResponse, error)
+	StopContainer(ctx context.Context, in *ContainerActionRequest, opts ...grpc.CallOption) (*ContainerActionResponse, error)
+	RestartContainer(ctx context.Context, in *ContainerActionRequest, opts ...grpc.CallOption) (*ContainerActionResponse, error)
+	PauseContainer(ctx context.Context, in *ContainerActionRequest, opts ...grpc.CallOption) (*ContainerActionResponse, error)
+	UnpauseContainer(ctx context.Context, in *ContainerActionRequest, opts ...grpc.CallOption) (*ContainerActionResponse, error)
+	KillContainer(ctx context.Context, in *ContainerActionRequest, opts ...grpc.CallOption) (*ContainerActionResponse, error)
+	StreamContainerLogs(ctx context.Context, in *StreamContainerLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ContainerLogLine], error)
+	StreamContainerStats(ctx context.Context, in *StreamContainerStatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ContainerStats], error)
+	WatchJob(ctx context.Context, in *WatchJobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Job], error)
+	CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*Job, error)
 }
 
 type containerAdmServiceClient struct {
@@ -71,6 +93,143 @@ func (c *containerAdmServiceClient) GetContainerUptimeDuration(ctx context.Conte
 	return out, nil
 }
 
+func (c *containerAdmServiceClient) GetContainerStatusHistory(ctx context.Context, in *GetContainerStatusHistoryRequest, opts ...grpc.CallOption) (*GetContainerStatusHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetContainerStatusHistoryResponse)
+	err := c.cc.Invoke(ctx, ContainerAdmService_GetContainerStatusHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containerAdmServiceClient) StartContainer(ctx context.Context, in *ContainerActionRequest, opts ...grpc.CallOption) (*ContainerActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ContainerActionResponse)
+	err := c.cc.Invoke(ctx, ContainerAdmService_StartContainer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containerAdmServiceClient) StopContainer(ctx context.Context, in *ContainerActionRequest, opts ...grpc.CallOption) (*ContainerActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ContainerActionResponse)
+	err := c.cc.Invoke(ctx, ContainerAdmService_StopContainer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containerAdmServiceClient) RestartContainer(ctx context.Context, in *ContainerActionRequest, opts ...grpc.CallOption) (*ContainerActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ContainerActionResponse)
+	err := c.cc.Invoke(ctx, ContainerAdmService_RestartContainer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containerAdmServiceClient) PauseContainer(ctx context.Context, in *ContainerActionRequest, opts ...grpc.CallOption) (*ContainerActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ContainerActionResponse)
+	err := c.cc.Invoke(ctx, ContainerAdmService_PauseContainer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containerAdmServiceClient) UnpauseContainer(ctx context.Context, in *ContainerActionRequest, opts ...grpc.CallOption) (*ContainerActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ContainerActionResponse)
+	err := c.cc.Invoke(ctx, ContainerAdmService_UnpauseContainer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containerAdmServiceClient) KillContainer(ctx context.Context, in *ContainerActionRequest, opts ...grpc.CallOption) (*ContainerActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ContainerActionResponse)
+	err := c.cc.Invoke(ctx, ContainerAdmService_KillContainer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *containerAdmServiceClient) StreamContainerLogs(ctx context.Context, in *StreamContainerLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ContainerLogLine], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ContainerAdmService_ServiceDesc.Streams[0], ContainerAdmService_StreamContainerLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamContainerLogsRequest, ContainerLogLine]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ContainerAdmService_StreamContainerLogsClient = grpc.ServerStreamingClient[ContainerLogLine]
+
+func (c *containerAdmServiceClient) StreamContainerStats(ctx context.Context, in *StreamContainerStatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ContainerStats], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ContainerAdmService_ServiceDesc.Streams[1], ContainerAdmService_StreamContainerStats_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamContainerStatsRequest, ContainerStats]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ContainerAdmService_StreamContainerStatsClient = grpc.ServerStreamingClient[ContainerStats]
+
+func (c *containerAdmServiceClient) WatchJob(ctx context.Context, in *WatchJobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Job], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ContainerAdmService_ServiceDesc.Streams[2], ContainerAdmService_WatchJob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchJobRequest, Job]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ContainerAdmService_WatchJobClient = grpc.ServerStreamingClient[Job]
+
+func (c *containerAdmServiceClient) CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*Job, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Job)
+	err := c.cc.Invoke(ctx, ContainerAdmService_CancelJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ContainerAdmServiceServer is the server API for ContainerAdmService service.
 // All implementations must embed UnimplementedContainerAdmServiceServer
 // for forward compatibility.
@@ -78,6 +237,17 @@ type ContainerAdmServiceServer interface {
 	GetAllContainers(context.Context, *EmptyRequest) (*ContainerResponse, error)
 	GetContainerInformation(context.Context, *GetContainerInfomationRequest) (*GetContainerInfomationResponse, error)
 	GetContainerUptimeDuration(context.Context, *GetContainerInfomationRequest) (*GetContainerUptimeDurationResponse, error)
+	GetContainerStatusHistory(context.Context, *GetContainerStatusHistoryRequest) (*GetContainerStatusHistoryResponse, error)
+	StartContainer(context.Context, *ContainerActionRequest) (*ContainerActionResponse, error)
+	StopContainer(context.Context, *ContainerActionRequest) (*ContainerActionResponse, error)
+	RestartContainer(context.Context, *ContainerActionRequest) (*ContainerActionResponse, error)
+	PauseContainer(context.Context, *ContainerActionRequest) (*ContainerActionResponse, error)
+	UnpauseContainer(context.Context, *ContainerActionRequest) (*ContainerActionResponse, error)
+	KillContainer(context.Context, *ContainerActionRequest) (*ContainerActionResponse, error)
+	StreamContainerLogs(*StreamContainerLogsRequest, grpc.ServerStreamingServer[ContainerLogLine]) error
+	StreamContainerStats(*StreamContainerStatsRequest, grpc.ServerStreamingServer[ContainerStats]) error
+	WatchJob(*WatchJobRequest, grpc.ServerStreamingServer[Job]) error
+	CancelJob(context.Context, *CancelJobRequest) (*Job, error)
 	mustEmbedUnimplementedContainerAdmServiceServer()
 }
 
@@ -97,6 +267,39 @@ func (UnimplementedContainerAdmServiceServer) GetContainerInformation(context.Co
 func (UnimplementedContainerAdmServiceServer) GetContainerUptimeDuration(context.Context, *GetContainerInfomationRequest) (*GetContainerUptimeDurationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetContainerUptimeDuration not implemented")
 }
+func (UnimplementedContainerAdmServiceServer) GetContainerStatusHistory(context.Context, *GetContainerStatusHistoryRequest) (*GetContainerStatusHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetContainerStatusHistory not implemented")
+}
+func (UnimplementedContainerAdmServiceServer) StartContainer(context.Context, *ContainerActionRequest) (*ContainerActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartContainer not implemented")
+}
+func (UnimplementedContainerAdmServiceServer) StopContainer(context.Context, *ContainerActionRequest) (*ContainerActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopContainer not implemented")
+}
+func (UnimplementedContainerAdmServiceServer) RestartContainer(context.Context, *ContainerActionRequest) (*ContainerActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestartContainer not implemented")
+}
+func (UnimplementedContainerAdmServiceServer) PauseContainer(context.Context, *ContainerActionRequest) (*ContainerActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseContainer not implemented")
+}
+func (UnimplementedContainerAdmServiceServer) UnpauseContainer(context.Context, *ContainerActionRequest) (*ContainerActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnpauseContainer not implemented")
+}
+func (UnimplementedContainerAdmServiceServer) KillContainer(context.Context, *ContainerActionRequest) (*ContainerActionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KillContainer not implemented")
+}
+func (UnimplementedContainerAdmServiceServer) StreamContainerLogs(*StreamContainerLogsRequest, grpc.ServerStreamingServer[ContainerLogLine]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamContainerLogs not implemented")
+}
+func (UnimplementedContainerAdmServiceServer) StreamContainerStats(*StreamContainerStatsRequest, grpc.ServerStreamingServer[ContainerStats]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamContainerStats not implemented")
+}
+func (UnimplementedContainerAdmServiceServer) WatchJob(*WatchJobRequest, grpc.ServerStreamingServer[Job]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchJob not implemented")
+}
+func (UnimplementedContainerAdmServiceServer) CancelJob(context.Context, *CancelJobRequest) (*Job, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
 func (UnimplementedContainerAdmServiceServer) mustEmbedUnimplementedContainerAdmServiceServer() {}
 func (UnimplementedContainerAdmServiceServer) testEmbeddedByValue()                             {}
 
@@ -172,6 +375,183 @@ func _ContainerAdmService_GetContainerUptimeDuration_Handler(srv interface{}, ct
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ContainerAdmService_GetContainerStatusHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetContainerStatusHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainerAdmServiceServer).GetContainerStatusHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContainerAdmService_GetContainerStatusHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainerAdmServiceServer).GetContainerStatusHistory(ctx, req.(*GetContainerStatusHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContainerAdmService_StartContainer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContainerActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainerAdmServiceServer).StartContainer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContainerAdmService_StartContainer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainerAdmServiceServer).StartContainer(ctx, req.(*ContainerActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContainerAdmService_StopContainer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContainerActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainerAdmServiceServer).StopContainer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContainerAdmService_StopContainer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainerAdmServiceServer).StopContainer(ctx, req.(*ContainerActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContainerAdmService_RestartContainer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContainerActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainerAdmServiceServer).RestartContainer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContainerAdmService_RestartContainer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainerAdmServiceServer).RestartContainer(ctx, req.(*ContainerActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContainerAdmService_PauseContainer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContainerActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainerAdmServiceServer).PauseContainer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContainerAdmService_PauseContainer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainerAdmServiceServer).PauseContainer(ctx, req.(*ContainerActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContainerAdmService_UnpauseContainer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContainerActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainerAdmServiceServer).UnpauseContainer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContainerAdmService_UnpauseContainer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainerAdmServiceServer).UnpauseContainer(ctx, req.(*ContainerActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContainerAdmService_KillContainer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ContainerActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainerAdmServiceServer).KillContainer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContainerAdmService_KillContainer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainerAdmServiceServer).KillContainer(ctx, req.(*ContainerActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContainerAdmService_StreamContainerLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamContainerLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ContainerAdmServiceServer).StreamContainerLogs(m, &grpc.GenericServerStream[StreamContainerLogsRequest, ContainerLogLine]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ContainerAdmService_StreamContainerLogsServer = grpc.ServerStreamingServer[ContainerLogLine]
+
+func _ContainerAdmService_StreamContainerStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamContainerStatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ContainerAdmServiceServer).StreamContainerStats(m, &grpc.GenericServerStream[StreamContainerStatsRequest, ContainerStats]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ContainerAdmService_StreamContainerStatsServer = grpc.ServerStreamingServer[ContainerStats]
+
+func _ContainerAdmService_WatchJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchJobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ContainerAdmServiceServer).WatchJob(m, &grpc.GenericServerStream[WatchJobRequest, Job]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ContainerAdmService_WatchJobServer = grpc.ServerStreamingServer[Job]
+
+func _ContainerAdmService_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContainerAdmServiceServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContainerAdmService_CancelJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContainerAdmServiceServer).CancelJob(ctx, req.(*CancelJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ContainerAdmService_ServiceDesc is the grpc.ServiceDesc for ContainerAdmService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -191,7 +571,55 @@ var ContainerAdmService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetContainerUptimeDuration",
 			Handler:    _ContainerAdmService_GetContainerUptimeDuration_Handler,
 		},
+		{
+			MethodName: "GetContainerStatusHistory",
+			Handler:    _ContainerAdmService_GetContainerStatusHistory_Handler,
+		},
+		{
+			MethodName: "StartContainer",
+			Handler:    _ContainerAdmService_StartContainer_Handler,
+		},
+		{
+			MethodName: "StopContainer",
+			Handler:    _ContainerAdmService_StopContainer_Handler,
+		},
+		{
+			MethodName: "RestartContainer",
+			Handler:    _ContainerAdmService_RestartContainer_Handler,
+		},
+		{
+			MethodName: "PauseContainer",
+			Handler:    _ContainerAdmService_PauseContainer_Handler,
+		},
+		{
+			MethodName: "UnpauseContainer",
+			Handler:    _ContainerAdmService_UnpauseContainer_Handler,
+		},
+		{
+			MethodName: "KillContainer",
+			Handler:    _ContainerAdmService_KillContainer_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _ContainerAdmService_CancelJob_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamContainerLogs",
+			Handler:       _ContainerAdmService_StreamContainerLogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamContainerStats",
+			Handler:       _ContainerAdmService_StreamContainerStats_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchJob",
+			Handler:       _ContainerAdmService_WatchJob_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/container.proto",
 }
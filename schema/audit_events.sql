@@ -0,0 +1,19 @@
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    actor_user_id BIGINT NOT NULL DEFAULT 0,
+    actor_username VARCHAR(255) NOT NULL DEFAULT '',
+    actor_role VARCHAR(64) NOT NULL DEFAULT '',
+    action VARCHAR(64) NOT NULL,
+    container_id BIGINT NOT NULL DEFAULT 0,
+    changes JSONB,
+    source_ip VARCHAR(64) NOT NULL DEFAULT '',
+    transport VARCHAR(16) NOT NULL,
+    correlation_id VARCHAR(255) NOT NULL DEFAULT '',
+    result VARCHAR(16) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_events_container_id ON audit_events (container_id, created_at);
+CREATE INDEX idx_audit_events_actor ON audit_events (actor_username, created_at);
+CREATE INDEX idx_audit_events_created_at ON audit_events (created_at);
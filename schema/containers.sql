@@ -4,6 +4,16 @@ CREATE TABLE containers (
     container_name VARCHAR(255) NOT NULL UNIQUE,
     image_name VARCHAR(255) NOT NULL,
     status VARCHAR(255) NOT NULL,
+    spec JSONB NOT NULL DEFAULT '{}'::jsonb,
+    last_status_at TIMESTAMP,
+    last_status_producer VARCHAR(255) NOT NULL DEFAULT '',
+    last_status_sequence BIGINT NOT NULL DEFAULT 0,
+    owner_id INTEGER NOT NULL DEFAULT 0,
+    owner_role VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_id VARCHAR(255) NOT NULL DEFAULT '',
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
+
+CREATE INDEX idx_containers_owner_id ON containers (owner_id);
+CREATE INDEX idx_containers_tenant_id ON containers (tenant_id);
@@ -0,0 +1,6 @@
+CREATE TABLE event_checkpoints (
+    source VARCHAR(64) PRIMARY KEY,
+    last_seen TIMESTAMP NOT NULL,
+    last_event_id TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
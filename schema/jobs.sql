@@ -0,0 +1,23 @@
+CREATE TABLE jobs (
+    id UUID PRIMARY KEY,
+    type VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    steps JSONB NOT NULL DEFAULT '[]'::jsonb,
+    request JSONB NOT NULL,
+    result JSONB,
+    error TEXT NOT NULL DEFAULT '',
+    container_id BIGINT NOT NULL DEFAULT 0,
+    actor JSONB NOT NULL,
+    owner_id INTEGER NOT NULL DEFAULT 0,
+    tenant_id VARCHAR(255) NOT NULL DEFAULT '',
+    correlation_id VARCHAR(255) NOT NULL DEFAULT '',
+    cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX idx_jobs_queued ON jobs (created_at) WHERE status = 'queued';
+CREATE INDEX idx_jobs_owner_id ON jobs (owner_id);
+CREATE INDEX idx_jobs_tenant_id ON jobs (tenant_id);
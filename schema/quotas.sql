@@ -0,0 +1,13 @@
+CREATE TABLE quotas (
+    id SERIAL PRIMARY KEY,
+    subject_type VARCHAR(16) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    max_containers BIGINT NOT NULL DEFAULT 0,
+    max_running BIGINT NOT NULL DEFAULT 0,
+    max_cpu_shares BIGINT NOT NULL DEFAULT 0,
+    max_memory_bytes BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_quotas_subject ON quotas (subject_type, subject);
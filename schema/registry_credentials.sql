@@ -0,0 +1,8 @@
+CREATE TABLE registry_credentials (
+    id SERIAL PRIMARY KEY,
+    registry VARCHAR(255) NOT NULL UNIQUE,
+    username VARCHAR(255) NOT NULL,
+    encrypted_password BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Keys under which the REST middlewares store request values in the gin
+// context. gin.Context resolves string keys through Value, so handlers can
+// pass the gin context down and the helpers below still find them.
+const (
+	ClaimsKey        = "claims"
+	CorrelationIDKey = "correlation_id"
+	RequestMetaKey   = "request_meta"
+)
+
+// Transports a change can arrive through.
+const (
+	TransportREST   = "rest"
+	TransportGRPC   = "grpc"
+	TransportKafka  = "kafka"
+	TransportSystem = "system"
+)
+
+// RequestMeta describes where a request came from.
+type RequestMeta struct {
+	SourceIP  string
+	Transport string
+}
+
+type claimsContextKey struct{}
+type correlationIDContextKey struct{}
+type requestMetaContextKey struct{}
+
+func ContextWithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	if claims, ok := ctx.Value(claimsContextKey{}).(*Claims); ok && claims != nil {
+		return claims, true
+	}
+	if claims, ok := ctx.Value(ClaimsKey).(*Claims); ok && claims != nil {
+		return claims, true
+	}
+	return nil, false
+}
+
+func ContextWithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDContextKey{}, correlationID)
+}
+
+func CorrelationIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(correlationIDContextKey{}).(string); ok && id != "" {
+		return id
+	}
+	if id, ok := ctx.Value(CorrelationIDKey).(string); ok && id != "" {
+		return id
+	}
+	return ""
+}
+
+func NewCorrelationID() string {
+	return uuid.NewString()
+}
+
+func ContextWithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaContextKey{}, meta)
+}
+
+// RequestMetaFromContext returns the request origin, or the system transport
+// for work that was not triggered by a request.
+func RequestMetaFromContext(ctx context.Context) RequestMeta {
+	if meta, ok := ctx.Value(requestMetaContextKey{}).(RequestMeta); ok {
+		return meta
+	}
+	if meta, ok := ctx.Value(RequestMetaKey).(RequestMeta); ok {
+		return meta
+	}
+	return RequestMeta{Transport: TransportSystem}
+}
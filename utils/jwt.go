@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"thanhnt208/container-adm-service/config"
 	"time"
 
@@ -9,14 +12,137 @@ import (
 
 var jwtSecret = []byte(config.LoadConfig().JWTSecret)
 
+var jwtVerifier = NewJWTVerifier(config.LoadConfig())
+
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// RevocationChecker reports whether a token that is otherwise valid has
+// been revoked.
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, claims *Claims) (bool, error)
+}
+
+var revocationChecker RevocationChecker
+
+// SetRevocationChecker makes ParseJWT reject revoked tokens. It is meant to
+// be called once during startup.
+func SetRevocationChecker(checker RevocationChecker) {
+	revocationChecker = checker
+}
+
+// asymmetricAlgorithms are the signing methods verified against the JWKS.
+var asymmetricAlgorithms = map[string]bool{
+	"RS256": true, "RS384": true, "RS512": true,
+	"ES256": true, "ES384": true, "ES512": true,
+}
+
 type Claims struct {
 	UserID   uint     `json:"user_id"`
 	Username string   `json:"username"`
 	Role     string   `json:"role"`
+	TenantID string   `json:"tenant_id,omitempty"`
 	Scopes   []string `json:"scopes"`
 	jwt.RegisteredClaims
 }
 
+const RoleAdmin = "admin"
+
+// IsAdmin reports whether the claims grant access across all tenants.
+func IsAdmin(claims *Claims) bool {
+	return claims != nil && claims.Role == RoleAdmin
+}
+
+// JWTVerifier checks the signature and registered claims of access tokens.
+// Asymmetric tokens are verified with the key named by their kid header.
+// HS256 with the shared secret is only accepted when legacy mode is enabled.
+type JWTVerifier struct {
+	keys       *JWKSKeySet
+	secret     []byte
+	algorithms []string
+	issuer     string
+	audience   string
+	leeway     time.Duration
+	maxTTL     time.Duration
+}
+
+func NewJWTVerifier(cfg *config.Config) *JWTVerifier {
+	v := &JWTVerifier{
+		issuer:   cfg.JWTIssuer,
+		audience: cfg.JWTAudience,
+		leeway:   time.Duration(cfg.JWTLeeway) * time.Second,
+		maxTTL:   cfg.MaxTokenTTL(),
+	}
+
+	for _, alg := range cfg.JWTAlgorithms {
+		if asymmetricAlgorithms[alg] {
+			v.algorithms = append(v.algorithms, alg)
+		}
+	}
+	if len(v.algorithms) > 0 && (cfg.JWTJWKSURL != "" || cfg.JWTJWKSFile != "") {
+		v.keys = NewJWKSKeySet(cfg.JWTJWKSURL, cfg.JWTJWKSFile, time.Duration(cfg.JWTJWKSRefresh)*time.Second)
+	}
+
+	if cfg.JWTAllowHS256 {
+		v.secret = []byte(cfg.JWTSecret)
+		v.algorithms = append(v.algorithms, jwt.SigningMethodHS256.Alg())
+	}
+
+	return v
+}
+
+func (v *JWTVerifier) Parse(ctx context.Context, tokenStr string) (*Claims, error) {
+	if len(v.algorithms) == 0 {
+		return nil, fmt.Errorf("no JWT signing algorithms are enabled")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(v.algorithms),
+		jwt.WithLeeway(v.leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if v.issuer != "" {
+		options = append(options, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		options = append(options, jwt.WithAudience(v.audience))
+	}
+
+	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if v.secret == nil {
+				return nil, fmt.Errorf("HS256 tokens are not accepted")
+			}
+			return v.secret, nil
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+			if v.keys == nil {
+				return nil, fmt.Errorf("no JWKS source configured")
+			}
+			kid, _ := token.Header["kid"].(string)
+			if kid == "" {
+				return nil, fmt.Errorf("token has no kid header")
+			}
+			return v.keys.Key(ctx, kid)
+		default:
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	// Revocations are kept for maxTTL, so a token valid for longer could
+	// outlive its revocation.
+	if v.maxTTL > 0 && time.Until(claims.ExpiresAt.Time) > v.maxTTL {
+		return nil, fmt.Errorf("%w: token lifetime exceeds %s", jwt.ErrTokenInvalidClaims, v.maxTTL)
+	}
+	return claims, nil
+}
+
 var GenerateJWT = func(userID uint, username, role string, scopes []string, expiry time.Duration) (string, error) {
 	claims := &Claims{
 		UserID:   userID,
@@ -32,16 +158,22 @@ var GenerateJWT = func(userID uint, username, role string, scopes []string, expi
 	return token.SignedString(jwtSecret)
 }
 
-func ParseJWT(tokenStr string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+// ParseJWT verifies tokenStr and rejects it if it has been revoked. When the
+// revocation list cannot be reached the token is rejected as well.
+func ParseJWT(ctx context.Context, tokenStr string) (*Claims, error) {
+	claims, err := jwtVerifier.Parse(ctx, tokenStr)
 	if err != nil {
 		return nil, err
 	}
-	claims, ok := token.Claims.(*Claims)
-	if !ok || !token.Valid {
-		return nil, jwt.ErrTokenInvalidClaims
+
+	if revocationChecker != nil {
+		revoked, err := revocationChecker.IsRevoked(ctx, claims)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check token revocation: %w", err)
+		}
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
 	}
 	return claims, nil
 }
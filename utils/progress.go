@@ -0,0 +1,31 @@
+package utils
+
+import "context"
+
+// ProgressReporter receives the progress of a long-running operation, such
+// as a job. Step starts a new step and ends the previous one; Layer reports
+// the download of one image layer within the current step.
+type ProgressReporter interface {
+	Step(name, target string)
+	Layer(id, status string, current, total int64)
+}
+
+type progressContextKey struct{}
+
+type noopProgress struct{}
+
+func (noopProgress) Step(string, string)                {}
+func (noopProgress) Layer(string, string, int64, int64) {}
+
+func ContextWithProgress(ctx context.Context, progress ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressContextKey{}, progress)
+}
+
+// ProgressFromContext returns the reporter of ctx, or one that discards
+// everything when the operation is not tracked.
+func ProgressFromContext(ctx context.Context) ProgressReporter {
+	if progress, ok := ctx.Value(progressContextKey{}).(ProgressReporter); ok && progress != nil {
+		return progress
+	}
+	return noopProgress{}
+}
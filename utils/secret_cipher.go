@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+var ErrNoEncryptionKey = errors.New("no encryption key configured")
+
+// SecretCipher encrypts secrets stored at rest with AES-256-GCM. The
+// ciphertext is the random nonce followed by the sealed data. Associated data
+// binds a ciphertext to its owner, so it cannot be moved to another row.
+type SecretCipher struct {
+	aead cipher.AEAD
+}
+
+// NewSecretCipher takes a base64 encoded 32-byte key. An empty key yields a
+// cipher that refuses to encrypt or decrypt.
+func NewSecretCipher(encodedKey string) (*SecretCipher, error) {
+	if encodedKey == "" {
+		return &SecretCipher{}, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid encryption key: expected 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return &SecretCipher{aead: aead}, nil
+}
+
+func (c *SecretCipher) Enabled() bool {
+	return c != nil && c.aead != nil
+}
+
+func (c *SecretCipher) Encrypt(plaintext, associatedData []byte) ([]byte, error) {
+	if !c.Enabled() {
+		return nil, ErrNoEncryptionKey
+	}
+
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, associatedData), nil
+}
+
+func (c *SecretCipher) Decrypt(ciphertext, associatedData []byte) ([]byte, error) {
+	if !c.Enabled() {
+		return nil, ErrNoEncryptionKey
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	plaintext, err := c.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], associatedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+	return plaintext, nil
+}
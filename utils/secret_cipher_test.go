@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func newTestCipher(t *testing.T) *SecretCipher {
+	t.Helper()
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))
+	c, err := NewSecretCipher(key)
+	if err != nil {
+		t.Fatalf("NewSecretCipher() error = %v", err)
+	}
+	return c
+}
+
+func TestSecretCipherRoundTrip(t *testing.T) {
+	c := newTestCipher(t)
+	host := []byte("registry.example.com")
+
+	ciphertext, err := c.Encrypt([]byte("s3cret"), host)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if bytes.Contains(ciphertext, []byte("s3cret")) {
+		t.Error("ciphertext contains the plaintext")
+	}
+
+	plaintext, err := c.Decrypt(ciphertext, host)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	if string(plaintext) != "s3cret" {
+		t.Errorf("Decrypt() = %q, want %q", plaintext, "s3cret")
+	}
+
+	again, err := c.Encrypt([]byte("s3cret"), host)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if bytes.Equal(ciphertext, again) {
+		t.Error("Encrypt() reused a nonce")
+	}
+}
+
+func TestSecretCipherRejectsTampering(t *testing.T) {
+	c := newTestCipher(t)
+	host := []byte("registry.example.com")
+
+	ciphertext, err := c.Encrypt([]byte("s3cret"), host)
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	tests := map[string]struct {
+		ciphertext []byte
+		host       []byte
+	}{
+		"flipped byte":       {flipLastByte(ciphertext), host},
+		"other host":         {ciphertext, []byte("ghcr.io")},
+		"no host":            {ciphertext, nil},
+		"truncated":          {ciphertext[:len(ciphertext)-1], host},
+		"shorter than nonce": {ciphertext[:4], host},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := c.Decrypt(tt.ciphertext, tt.host); err == nil {
+				t.Error("Decrypt() error = nil, want an error")
+			}
+		})
+	}
+
+	other, err := NewSecretCipher(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, 32)))
+	if err != nil {
+		t.Fatalf("NewSecretCipher() error = %v", err)
+	}
+	if _, err := other.Decrypt(ciphertext, host); err == nil {
+		t.Error("Decrypt() with another key error = nil, want an error")
+	}
+}
+
+func TestSecretCipherWithoutKey(t *testing.T) {
+	c, err := NewSecretCipher("")
+	if err != nil {
+		t.Fatalf("NewSecretCipher() error = %v", err)
+	}
+	if c.Enabled() {
+		t.Error("Enabled() = true without a key")
+	}
+	if _, err := c.Encrypt([]byte("s3cret"), nil); !errors.Is(err, ErrNoEncryptionKey) {
+		t.Errorf("Encrypt() error = %v, want %v", err, ErrNoEncryptionKey)
+	}
+	if _, err := c.Decrypt([]byte("anything"), nil); !errors.Is(err, ErrNoEncryptionKey) {
+		t.Errorf("Decrypt() error = %v, want %v", err, ErrNoEncryptionKey)
+	}
+}
+
+func TestNewSecretCipherInvalidKey(t *testing.T) {
+	for name, key := range map[string]string{
+		"not base64": "%%%",
+		"too short":  base64.StdEncoding.EncodeToString(make([]byte, 16)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewSecretCipher(key); err == nil {
+				t.Error("NewSecretCipher() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func flipLastByte(b []byte) []byte {
+	out := append([]byte{}, b...)
+	out[len(out)-1] ^= 0xff
+	return out
+}